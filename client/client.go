@@ -0,0 +1,264 @@
+// Package client is the Go client of the minesweeper API, it wraps its routes behind typed methods
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of the API
+const (
+	HeaderAPIKey             = "X-API-KEY"
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	HeaderLastEventID        = "Last-Event-ID"
+)
+
+// Client defaults
+const (
+	DefaultTimeout      = 30 * time.Second
+	DefaultRetries      = 2
+	DefaultRetryBackoff = 250 * time.Millisecond
+)
+
+type Client interface {
+	// APIKey is the key the requests are sent with, signing in sets it
+	APIKey() string
+	SetAPIKey(apiKey string)
+
+	SignIn(ctx context.Context, credentials Credentials) (string, error)
+	CreateUser(ctx context.Context, input UserInput) (*User, error)
+	GetUser(ctx context.Context, userID string) (*User, error)
+	UpdateUser(ctx context.Context, userID string, input UserInput) (*User, error)
+	Me(ctx context.Context) (*User, error)
+
+	CreateGame(ctx context.Context, input GameCreateInput) (*Game, error)
+	GetGame(ctx context.Context, gameID string) (*Game, error)
+	ListGames(ctx context.Context) (map[string]*Game, error)
+	MyGames(ctx context.Context, status string) ([]GameSummary, error)
+	StartGame(ctx context.Context, gameID string) (*Game, error)
+	Click(ctx context.Context, gameID string, input GameInput) (*Delta, error)
+	Reveal(ctx context.Context, gameID string, input RevealInput) (*Delta, error)
+	Flag(ctx context.Context, gameID string, input FlagInput) (*Delta, error)
+	Unflag(ctx context.Context, gameID string, input FlagInput) (*Delta, error)
+	PlayMoves(ctx context.Context, gameID string, input GameMovesInput) (*MovesResult, error)
+	DeleteGame(ctx context.Context, gameID string) error
+
+	Events(ctx context.Context, gameID string) (*EventStream, error)
+	ResumeEvents(ctx context.Context, gameID string, lastSeq uint64) (*EventStream, error)
+}
+
+// Config of the client, only the base URL is required
+type Config struct {
+	BaseURL    string // like https://minesweeper-svc.herokuapp.com
+	APIKey     string
+	HTTPClient *http.Client // by default one timing out after DefaultTimeout
+	// Retries of the requests safe to retry: reads, and the writes sent with an idempotency key, which get
+	// the response of the first attempt if it got through. Negative disables them.
+	Retries int
+	// RetryBackoff is the wait before the first retry, it doubles on each one
+	RetryBackoff time.Duration
+}
+
+type ClientSvc struct {
+	baseURL      string
+	httpClient   *http.Client
+	retries      int
+	retryBackoff time.Duration
+	mu           sync.RWMutex
+	apiKey       string
+}
+
+func NewClientSvc(cfg Config) Client {
+	svc := &ClientSvc{
+		baseURL:      strings.TrimSuffix(cfg.BaseURL, "/"),
+		httpClient:   cfg.HTTPClient,
+		retries:      cfg.Retries,
+		retryBackoff: cfg.RetryBackoff,
+		apiKey:       cfg.APIKey,
+	}
+	if svc.httpClient == nil {
+		svc.httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	if svc.retries == 0 {
+		svc.retries = DefaultRetries
+	} else if svc.retries < 0 {
+		svc.retries = 0
+	}
+	if svc.retryBackoff <= 0 {
+		svc.retryBackoff = DefaultRetryBackoff
+	}
+
+	return svc
+}
+
+func (svc *ClientSvc) APIKey() string {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+
+	return svc.apiKey
+}
+
+func (svc *ClientSvc) SetAPIKey(apiKey string) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.apiKey = apiKey
+}
+
+// request is a call to the API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	// idempotent requests get an idempotency key when they write, and are retried
+	idempotent bool
+	// read requests sent with a write method, like the GraphQL queries, need no idempotency key to be retried
+	read bool
+}
+
+// envelope is the body of the successful responses
+type envelope struct {
+	Code     int             `json:"code"`
+	Message  string          `json:"message"`
+	Response json.RawMessage `json:"response"`
+}
+
+// do sends a request, retrying it while it is safe to, and decodes the response into out
+func (svc *ClientSvc) do(ctx context.Context, req request, out interface{}) error {
+	return svc.roundTrip(ctx, req, func(status int, data []byte) error {
+		return decode(status, data, out)
+	})
+}
+
+// roundTrip sends a request and decodes its response, sending it again while it is safe to
+func (svc *ClientSvc) roundTrip(ctx context.Context, req request, decode func(status int, data []byte) error) error {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return err
+		}
+	}
+	var idempotencyKey string
+	if req.idempotent && !req.read && req.method != http.MethodGet {
+		idempotencyKey = newIdempotencyKey()
+	}
+	retries := 0
+	if req.idempotent || req.read {
+		retries = svc.retries
+	}
+	for attempt := 0; ; attempt++ {
+		// Failed sends are retried, as are the errors answered while the server couldn't handle the request
+		status, data, err := svc.send(ctx, req, body, idempotencyKey)
+		if err == nil {
+			var apiErr *Error
+			if err = decode(status, data); err == nil || !errors.As(err, &apiErr) || !retryable(apiErr) {
+				return err
+			}
+		}
+		if attempt >= retries || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-time.After(svc.retryBackoff << attempt):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// send sends a request once, returning the status and body of the response
+func (svc *ClientSvc) send(ctx context.Context, req request, body []byte, idempotencyKey string) (int, []byte,
+	error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, svc.url(req.path, req.query), reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if apiKey := svc.APIKey(); apiKey != "" {
+		httpReq.Header.Set(HeaderAPIKey, apiKey)
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set(HeaderIdempotencyKey, idempotencyKey)
+	}
+	resp, err := svc.httpClient.Do(httpReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, data, nil
+}
+
+func (svc *ClientSvc) url(path string, query url.Values) string {
+	u := svc.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	return u
+}
+
+// decode decodes a response into out, or into an *Error when the API answered one
+func decode(status int, data []byte, out interface{}) error {
+	if status >= http.StatusBadRequest {
+		return newError(status, data)
+	}
+	if out == nil || status == http.StatusNoContent {
+		return nil
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return fmt.Errorf("decoding the response: %w", err)
+	}
+	if err := json.Unmarshal(env.Response, out); err != nil {
+		return fmt.Errorf("decoding the response: %w", err)
+	}
+
+	return nil
+}
+
+// retryable tells whether a request answered with the error can be sent again: the server was unavailable,
+// or an attempt with the same idempotency key was still being handled
+func retryable(apiErr *Error) bool {
+	switch apiErr.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return errors.Is(apiErr, ErrIdempotencyKeyInFlight)
+}
+
+// newIdempotencyKey returns a random key, sent along every attempt of a write
+func newIdempotencyKey() string {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(key)
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+)
+
+// CodeGroup is added by the message catalog to the codes it answers with
+const CodeGroup = 10000
+
+// Error is an error answered by the API, its code is the one of the message catalog. Errors are told apart
+// with errors.Is against the errors below, which compares their codes.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	Details    string `json:"details"`
+}
+
+// Errors of the API, by their code
+var (
+	ErrUnexpected             = catalogError(1)
+	ErrUnauthorized           = catalogError(codes.MsgCodeUnauthorized)
+	ErrNotFound               = catalogError(codes.MsgCodeDBRecordsNotFound)
+	ErrInvalidInput           = catalogError(codes.MsgCodeReqHelperInvalidValue)
+	ErrInvalidGameRules       = catalogError(codes.MsgCodeInvalidGameRules)
+	ErrGameNotOwned           = catalogError(codes.MsgCodeGameNotOwned)
+	ErrInvalidMoves           = catalogError(codes.MsgCodeInvalidMoves)
+	ErrIdempotencyKeyReused   = catalogError(codes.MsgCodeIdempotencyKeyReused)
+	ErrIdempotencyKeyInFlight = catalogError(codes.MsgCodeIdempotencyKeyInFlight)
+)
+
+func catalogError(msgCode int) *Error {
+	return &Error{Code: msgCode + CodeGroup}
+}
+
+// newError decodes the error the API answered
+func newError(status int, data []byte) *Error {
+	apiErr := &Error{StatusCode: status}
+	if err := json.Unmarshal(data, apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+		apiErr.Details = string(data)
+	}
+
+	return apiErr
+}
+
+func (e *Error) Error() string {
+	if e.Details == "" {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Details
+}
+
+// Is tells whether the error has the code of the target
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && t.Code == e.Code
+}
+
+// MsgCode is the code of the error in the message catalog, see codes
+func (e *Error) MsgCode() int {
+	return e.Code - CodeGroup
+}
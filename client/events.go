@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventStream reads the events of a game streamed by the API as Server-Sent Events. When the stream breaks
+// it reconnects with the seq of the last event read, to get the events missed meanwhile. A game.resync
+// event tells the missed events are gone and the game has to be read again.
+type EventStream struct {
+	svc     *ClientSvc
+	ctx     context.Context
+	cancel  context.CancelFunc
+	gameID  string
+	lastSeq uint64
+	resume  bool
+	body    io.ReadCloser
+	reader  *bufio.Reader
+}
+
+// Events streams the events of a game from now on
+func (svc *ClientSvc) Events(ctx context.Context, gameID string) (*EventStream, error) {
+	return svc.stream(ctx, gameID, 0, false)
+}
+
+// ResumeEvents streams the events of a game after the one of lastSeq
+func (svc *ClientSvc) ResumeEvents(ctx context.Context, gameID string, lastSeq uint64) (*EventStream, error) {
+	return svc.stream(ctx, gameID, lastSeq, true)
+}
+
+func (svc *ClientSvc) stream(ctx context.Context, gameID string, lastSeq uint64, resume bool) (*EventStream,
+	error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &EventStream{svc: svc, ctx: ctx, cancel: cancel, gameID: gameID, lastSeq: lastSeq, resume: resume}
+	if err := s.connect(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// LastSeq is the seq of the last event read
+func (s *EventStream) LastSeq() uint64 {
+	return s.lastSeq
+}
+
+// Next blocks until the next event of the game, reconnecting when the stream breaks. It returns an error
+// once the stream is closed, its context done or it can't reconnect.
+func (s *EventStream) Next() (*Event, error) {
+	for attempt := 0; ; attempt++ {
+		e, err := s.read()
+		if err == nil {
+			return e, nil
+		}
+		s.body.Close()
+		if s.ctx.Err() != nil {
+			return nil, s.ctx.Err()
+		}
+		if attempt >= s.svc.retries {
+			return nil, err
+		}
+		select {
+		case <-time.After(s.svc.retryBackoff << attempt):
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		}
+		if err = s.connect(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close stops the stream
+func (s *EventStream) Close() error {
+	s.cancel()
+
+	return s.body.Close()
+}
+
+// connect opens the stream, resuming after the last event read if any
+func (s *EventStream) connect() error {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, s.svc.url(gamesPath(s.gameID, "/events"), nil), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if apiKey := s.svc.APIKey(); apiKey != "" {
+		req.Header.Set(HeaderAPIKey, apiKey)
+	}
+	if s.resume {
+		req.Header.Set(HeaderLastEventID, strconv.FormatUint(s.lastSeq, 10))
+	}
+	// The stream stays open for as long as it is read, so it doesn't get the timeout of the other requests
+	httpClient := *s.svc.httpClient
+	httpClient.Timeout = 0
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		data, _ := ioutil.ReadAll(resp.Body)
+		return newError(resp.StatusCode, data)
+	}
+	s.body = resp.Body
+	s.reader = bufio.NewReader(resp.Body)
+
+	return nil
+}
+
+// read reads the next event of the stream, skipping the keep-alive comments
+func (s *EventStream) read() (*Event, error) {
+	var data bytes.Buffer
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var e Event
+			if err = json.Unmarshal(data.Bytes(), &e); err != nil {
+				return nil, fmt.Errorf("decoding the event: %w", err)
+			}
+			if e.Seq > 0 {
+				s.lastSeq = e.Seq
+				s.resume = true
+			}
+			return &e, nil
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
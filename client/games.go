@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// gamesPath is the path of a game of the resource routes
+func gamesPath(gameID string, sub string) string {
+	return "/v2/api/games/" + url.PathEscape(gameID) + sub
+}
+
+// CreateGame creates a game for the user of the API key. Fog games are only told as the player can see
+// them, so their mine fields are left empty.
+func (svc *ClientSvc) CreateGame(ctx context.Context, input GameCreateInput) (*Game, error) {
+	var game Game
+	err := svc.do(ctx, request{method: http.MethodPost, path: "/v2/api/games", body: input, idempotent: true}, &game)
+	if err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+func (svc *ClientSvc) GetGame(ctx context.Context, gameID string) (*Game, error) {
+	var game Game
+	if err := svc.do(ctx, request{method: http.MethodGet, path: gamesPath(gameID, ""), idempotent: true}, &game); err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+// ListGames lists every game by its ID, only admins can
+func (svc *ClientSvc) ListGames(ctx context.Context) (map[string]*Game, error) {
+	var games map[string]*Game
+	if err := svc.do(ctx, request{method: http.MethodGet, path: "/v1/api/games", idempotent: true}, &games); err != nil {
+		return nil, err
+	}
+
+	return games, nil
+}
+
+// StartGame starts a created game, games already started answer an error with http.StatusConflict
+func (svc *ClientSvc) StartGame(ctx context.Context, gameID string) (*Game, error) {
+	var game Game
+	err := svc.do(ctx, request{method: http.MethodPost, path: gamesPath(gameID, "/start"), idempotent: true}, &game)
+	if err != nil {
+		return nil, err
+	}
+
+	return &game, nil
+}
+
+// Click plays any click of the game, like the arcade radar and detector, returning what it changed. A
+// losing click is told by the status of the delta.
+func (svc *ClientSvc) Click(ctx context.Context, gameID string, input GameInput) (*Delta, error) {
+	var delta Delta
+	err := svc.do(ctx, request{
+		method:     http.MethodPatch,
+		path:       "/v1/api/games/" + url.PathEscape(gameID),
+		query:      url.Values{"response": {"delta"}},
+		body:       input,
+		idempotent: true,
+	}, &delta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delta, nil
+}
+
+// Reveal reveals a field, or chords it, returning what it changed. A losing reveal is told by the status
+// of the delta.
+func (svc *ClientSvc) Reveal(ctx context.Context, gameID string, input RevealInput) (*Delta, error) {
+	return svc.play(ctx, http.MethodPost, gameID, "/reveals", input)
+}
+
+// Flag flags a field, returning what it changed
+func (svc *ClientSvc) Flag(ctx context.Context, gameID string, input FlagInput) (*Delta, error) {
+	return svc.play(ctx, http.MethodPost, gameID, "/flags", input)
+}
+
+// Unflag takes the flag off a field, returning what it changed. Fields without a flag are left as they are.
+func (svc *ClientSvc) Unflag(ctx context.Context, gameID string, input FlagInput) (*Delta, error) {
+	return svc.play(ctx, http.MethodDelete, gameID, "/flags", input)
+}
+
+func (svc *ClientSvc) play(ctx context.Context, method, gameID, sub string, input interface{}) (*Delta, error) {
+	var delta Delta
+	err := svc.do(ctx, request{method: method, path: gamesPath(gameID, sub), body: input, idempotent: true}, &delta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &delta, nil
+}
+
+// PlayMoves plays a batch of moves in order, with no other move played in between
+func (svc *ClientSvc) PlayMoves(ctx context.Context, gameID string, input GameMovesInput) (
+	*MovesResult, error) {
+	var result MovesResult
+	err := svc.do(ctx, request{method: http.MethodPost, path: gamesPath(gameID, "/moves"), body: input,
+		idempotent: true}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// DeleteGame soft deletes a game of the user of the API key
+func (svc *ClientSvc) DeleteGame(ctx context.Context, gameID string) error {
+	return svc.do(ctx, request{method: http.MethodDelete, path: gamesPath(gameID, ""), idempotent: true}, nil)
+}
+
+// GameSummary is a game as listed by MyGames, without its board
+type GameSummary struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Rows       int        `json:"rows"`
+	Cols       int        `json:"cols"`
+	Mines      int        `json:"mines"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+const myGamesQuery = `query($status: String) {
+  games(status: $status) { id type rows cols mines status createdAt startedAt finishedAt }
+}`
+
+// MyGames lists the games created by the user of the API key, the newest first, of the status if not empty
+func (svc *ClientSvc) MyGames(ctx context.Context, status string) ([]GameSummary, error) {
+	variables := map[string]interface{}{}
+	if status != "" {
+		variables["status"] = status
+	}
+	var data struct {
+		Games []GameSummary `json:"games"`
+	}
+	if err := svc.query(ctx, myGamesQuery, variables, &data); err != nil {
+		return nil, err
+	}
+
+	return data.Games, nil
+}
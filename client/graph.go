@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// graphInput is a GraphQL request, see graph.QueryInput
+type graphInput struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// graphResponse is a GraphQL response, its errors carry the code and details of the catalog errors in their
+// extensions
+type graphResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Code    int    `json:"code"`
+			Details string `json:"details"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// query runs a GraphQL query and decodes its data into out, the first error of the response is returned
+func (svc *ClientSvc) query(ctx context.Context, query string, variables map[string]interface{},
+	out interface{}) error {
+	req := request{method: http.MethodPost, path: "/graphql", body: graphInput{query, variables}, read: true}
+
+	return svc.roundTrip(ctx, req, func(status int, data []byte) error {
+		if status >= http.StatusBadRequest {
+			return newError(status, data)
+		}
+		var resp graphResponse
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return fmt.Errorf("decoding the response: %w", err)
+		}
+		if len(resp.Errors) > 0 {
+			gerr := resp.Errors[0]
+			return &Error{StatusCode: status, Code: gerr.Extensions.Code, Message: gerr.Message,
+				Details: gerr.Extensions.Details}
+		}
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return fmt.Errorf("decoding the response: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,227 @@
+package client
+
+import "time"
+
+// The types below are the ones the API sends and takes over the wire, so the client can be used without
+// the packages of the server
+
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type UserInput struct {
+	Credentials
+	Email    string `json:"email"`
+	Fullname string `json:"fullname"`
+}
+
+type User struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Username  string    `json:"username"`
+	Fullname  string    `json:"fullname"`
+	APIKeys   []APIKey  `json:"apiKeys"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type APIKey struct {
+	APIKey string `json:"apiKey"`
+}
+
+// GameCreateInput are the rules of a new game, only its size is required
+type GameCreateInput struct {
+	Rows       int    `json:"row"`
+	Cols       int    `json:"col"`
+	Mines      int    `json:"mines"`
+	Type       string `json:"type,omitempty"`   // classic or endless
+	Kernel     string `json:"kernel,omitempty"` // moore, knight or manhattan2
+	Arcade     bool   `json:"arcade,omitempty"`
+	Fog        *Fog   `json:"fog,omitempty"`
+	Tier       string `json:"tier,omitempty"`       // beginner, intermediate, expert or master
+	Durability string `json:"durability,omitempty"` // move or batch
+}
+
+// Fog enables the limited visibility mode
+type Fog struct {
+	Radius int `json:"radius"`
+	Memory int `json:"memory"`
+}
+
+// GameInput is a move, its click type is one of click, flag, unflag, chord, radar or detector
+type GameInput struct {
+	Row       int    `json:"row"`
+	Col       int    `json:"col"`
+	ClickType string `json:"clickType,omitempty"`
+}
+
+// RevealInput reveals a field, or chords it
+type RevealInput struct {
+	Row   int  `json:"row"`
+	Col   int  `json:"col"`
+	Chord bool `json:"chord"`
+}
+
+// FlagInput is a field to flag, or to take the flag off
+type FlagInput struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// GameMovesInput is a batch of moves played in order
+type GameMovesInput struct {
+	Moves []GameInput `json:"moves"`
+}
+
+type GameStatus string
+
+// Statuses of the games
+const (
+	GameStatusCreated   GameStatus = "created"
+	GameStatusStarted   GameStatus = "started"
+	GameStatusVictory   GameStatus = "victory"
+	GameStatusDefeat    GameStatus = "defeat"
+	GameStatusForfeited GameStatus = "forfeited"
+	GameStatusAbandoned GameStatus = "abandoned"
+)
+
+// Finished tells whether the game with the status can't be played anymore
+func (s GameStatus) Finished() bool {
+	switch s {
+	case GameStatusVictory, GameStatusDefeat, GameStatusForfeited, GameStatusAbandoned:
+		return true
+	}
+
+	return false
+}
+
+// Game is a game as the user is allowed to see it, endless and fog games have no mine field
+type Game struct {
+	ID         string      `json:"id"`
+	Version    uint64      `json:"version"` // version of the state, every change increases it
+	Rows       int         `json:"rows"`
+	Cols       int         `json:"cols"`
+	Mines      int         `json:"mines"`
+	Rules      Rules       `json:"rules"`
+	Status     GameStatus  `json:"status"`
+	MineField  [][]Field   `json:"mineField"`
+	Difficulty *Difficulty `json:"difficulty,omitempty"`
+	History    []Move      `json:"history,omitempty"`
+	StartedAt  *time.Time  `json:"startedAt,omitempty"`
+	FinishedAt *time.Time  `json:"finishedAt,omitempty"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	CreatedBy  string      `json:"createdBy"`
+	Code       string      `json:"code,omitempty"` // board code, told once the game is finished
+	Unranked   bool        `json:"unranked,omitempty"`
+}
+
+type Rules struct {
+	Type     string `json:"type,omitempty"` // classic when empty
+	Kernel   string `json:"kernel"`
+	Arcade   bool   `json:"arcade,omitempty"`
+	Fog      *Fog   `json:"fog,omitempty"`
+	Tier     string `json:"tier,omitempty"`
+	Practice bool   `json:"practice,omitempty"`
+}
+
+type Position struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// Field is a square of the mine field
+type Field struct {
+	Mine       bool       `json:"mine"`
+	Clicked    bool       `json:"clicked"`
+	Flagged    bool       `json:"flagged"`
+	AdjCount   int        `json:"adjMines"` // count of adjacent mines
+	Position   Position   `json:"position"`
+	ClickedBy  string     `json:"clickedBy"`
+	RevealedAt *time.Time `json:"revealedAt,omitempty"`
+	Item       string     `json:"item,omitempty"` // arcade item awarded to whoever reveals it
+}
+
+type Difficulty struct {
+	BBBV     int     `json:"3bv"` // minimum clicks needed to clear the board
+	Unsolved int     `json:"unsolved"`
+	Guesses  int     `json:"guesses"`
+	Openings int     `json:"openings"`
+	Density  float64 `json:"density"`
+	Rating   float64 `json:"rating"`
+	Tier     string  `json:"tier"`
+}
+
+// Move is a move of the history of a game
+type Move struct {
+	Seq    int       `json:"seq"`
+	Player string    `json:"player"`
+	Action string    `json:"action"` // click type name, or the item event
+	Row    int       `json:"row"`
+	Col    int       `json:"col"`
+	Item   string    `json:"item,omitempty"`
+	Result string    `json:"result,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// CellDelta is the state of a field a move or an event changed, its content is only told once revealed
+type CellDelta struct {
+	Row       int    `json:"row"`
+	Col       int    `json:"col"`
+	Revealed  bool   `json:"revealed"`
+	Flagged   bool   `json:"flagged"`
+	Mine      bool   `json:"mine,omitempty"`
+	AdjMines  int    `json:"adjMines,omitempty"`
+	Fogged    bool   `json:"fogged,omitempty"` // revealed out of sight of the player, its count is hidden
+	ClickedBy string `json:"clickedBy,omitempty"`
+}
+
+// Delta is what a move changed in a game
+type Delta struct {
+	GameID         string      `json:"gameID"`
+	Version        uint64      `json:"version"` // version of the game after the move
+	Move           *Move       `json:"move,omitempty"`
+	Cells          []CellDelta `json:"cells"`
+	Status         GameStatus  `json:"status"`
+	PreviousStatus GameStatus  `json:"previousStatus,omitempty"` // status before the move, when it changed it
+}
+
+// MoveOutcome is what became of a move of a batch: played, failed or skipped
+type MoveOutcome struct {
+	Index   int    `json:"index"`
+	Outcome string `json:"outcome"`
+	Delta   *Delta `json:"delta,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MovesResult is what a batch of moves did to a game
+type MovesResult struct {
+	GameID  string        `json:"gameID"`
+	Version uint64        `json:"version"`
+	Status  GameStatus    `json:"status"`
+	Moves   []MoveOutcome `json:"moves"`
+}
+
+type EventType string
+
+// Types of the events of the games
+const (
+	EventCellRevealed EventType = "cell.revealed"
+	EventFlagChanged  EventType = "flag.changed"
+	EventGameStarted  EventType = "game.started"
+	EventGameFinished EventType = "game.finished"
+	EventPlayerJoined EventType = "player.joined"
+	EventResync       EventType = "game.resync" // events were missed, the game has to be read again
+)
+
+// Event is something that happened in a game
+type Event struct {
+	Seq     uint64      `json:"seq"` // position of the event in the events of the game, starting at 1
+	Type    EventType   `json:"type"`
+	GameID  string      `json:"gameID"`
+	Version uint64      `json:"version,omitempty"` // version of the game after the event
+	Player  string      `json:"player,omitempty"`  // who caused the event
+	Cells   []CellDelta `json:"cells,omitempty"`
+	Status  GameStatus  `json:"status,omitempty"`
+	At      time.Time   `json:"at"`
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// SignIn signs the user in, the API key it returns is the one the client sends from then on
+func (svc *ClientSvc) SignIn(ctx context.Context, credentials Credentials) (string, error) {
+	var apiKey APIKey
+	err := svc.do(ctx, request{method: http.MethodPost, path: "/v1/auth/signIn", body: credentials}, &apiKey)
+	if err != nil {
+		return "", err
+	}
+	svc.SetAPIKey(apiKey.APIKey)
+
+	return apiKey.APIKey, nil
+}
+
+func (svc *ClientSvc) CreateUser(ctx context.Context, input UserInput) (*User, error) {
+	var user User
+	if err := svc.do(ctx, request{method: http.MethodPost, path: "/v1/auth/user", body: input}, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (svc *ClientSvc) GetUser(ctx context.Context, userID string) (*User, error) {
+	var user User
+	err := svc.do(ctx, request{method: http.MethodGet, path: "/v1/auth/user/" + url.PathEscape(userID),
+		idempotent: true}, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (svc *ClientSvc) UpdateUser(ctx context.Context, userID string, input UserInput) (*User, error) {
+	var user User
+	err := svc.do(ctx, request{method: http.MethodPut, path: "/v1/auth/user/" + url.PathEscape(userID),
+		body: input}, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Me gets the user of the API key
+func (svc *ClientSvc) Me(ctx context.Context) (*User, error) {
+	var user User
+	if err := svc.do(ctx, request{method: http.MethodGet, path: "/v2/api/users/me", idempotent: true}, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
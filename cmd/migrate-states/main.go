@@ -0,0 +1,31 @@
+// Command migrate-states upgrades every stored game state to the current schema version, states are
+// upgraded when read anyway, this rewrites them eagerly so old versions can be dropped
+package main
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/config"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+)
+
+func main() {
+	cfg, err := config.InitConfig()
+	if err != nil {
+		panic(err)
+	}
+	log := logger.New(cfg.Debug)
+	db, err := database.NewDB(cfg.DB, log)
+	if err != nil {
+		log.SendFatal(err)
+	}
+	upgraded, err := repo.NewGameRepoSvc(db).UpgradeGameStates(context.Background())
+	if err != nil {
+		log.Error().Err(err).Int("upgraded", upgraded).Msg("Game states migration failed")
+		log.SendFatal(err)
+	}
+	log.Info().Int("upgraded", upgraded).Int("schemaVersion", engine.StateSchemaVersion).Msg("Game states migrated")
+}
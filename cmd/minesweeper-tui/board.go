@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/client"
+)
+
+// ANSI escapes the screens are drawn with, each one over the last instead of clearing it for the screen not
+// to flicker
+const (
+	home        = "\x1b[H" // cursor to the top left
+	clearLine   = "\x1b[K" // rest of the line
+	clearBelow  = "\x1b[J" // rest of the screen
+	clearScreen = "\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	reverse     = "\x1b[7m"
+	bold        = "\x1b[1m"
+	dim         = "\x1b[2m"
+	red         = "\x1b[31m"
+	reset       = "\x1b[0m"
+)
+
+// colors of the counts of adjacent mines, like the classic game
+var countColors = []string{"", "\x1b[34m", "\x1b[32m", "\x1b[31m", "\x1b[35m", "\x1b[33m", "\x1b[36m", "\x1b[30;1m",
+	"\x1b[37m"}
+
+// board is the state of a game as the player sees it, kept up to date by the moves and the streamed events
+type board struct {
+	game       *client.Game
+	row, col   int // cursor
+	message    string
+	streaming  bool
+	startedAt  *time.Time
+	finishedAt *time.Time
+}
+
+func newBoard(game *client.Game) *board {
+	return &board{game: game, startedAt: game.StartedAt, finishedAt: game.FinishedAt}
+}
+
+// playable tells whether the board can be played here, only classic boards are sent whole
+func (b *board) playable() bool {
+	return len(b.game.MineField) == b.game.Rows && b.game.Rows > 0 && b.game.Rules.Fog == nil
+}
+
+func (b *board) finished() bool {
+	return b.game.Status.Finished()
+}
+
+func (b *board) move(dRow, dCol int) {
+	b.row = (b.row + dRow + b.game.Rows) % b.game.Rows
+	b.col = (b.col + dCol + b.game.Cols) % b.game.Cols
+}
+
+func (b *board) field() *client.Field {
+	return &b.game.MineField[b.row][b.col]
+}
+
+// apply updates the fields and the status a move or an event changed
+func (b *board) apply(cells []client.CellDelta, status client.GameStatus, at time.Time) {
+	for _, cell := range cells {
+		if cell.Row < 0 || cell.Row >= b.game.Rows || cell.Col < 0 || cell.Col >= b.game.Cols {
+			continue
+		}
+		field := &b.game.MineField[cell.Row][cell.Col]
+		field.Clicked = cell.Revealed
+		field.Flagged = cell.Flagged
+		field.Mine = field.Mine || cell.Mine // deltas only tell the mines revealed
+		field.AdjCount = cell.AdjMines
+		field.ClickedBy = cell.ClickedBy
+	}
+	if status == "" || status == b.game.Status {
+		return
+	}
+	b.game.Status = status
+	switch {
+	case status == client.GameStatusStarted && b.startedAt == nil:
+		b.startedAt = &at
+	case b.game.Status.Finished() && b.finishedAt == nil:
+		b.finishedAt = &at
+	}
+}
+
+// elapsed is the time the game has been played for
+func (b *board) elapsed() time.Duration {
+	if b.startedAt == nil {
+		return 0
+	}
+	until := time.Now()
+	if b.finishedAt != nil {
+		until = *b.finishedAt
+	}
+
+	return until.Sub(*b.startedAt).Truncate(time.Second)
+}
+
+// flags counts the flagged fields
+func (b *board) flags() (flags int) {
+	for _, fields := range b.game.MineField {
+		for _, field := range fields {
+			if field.Flagged {
+				flags++
+			}
+		}
+	}
+
+	return
+}
+
+// render draws the board, mines are only shown once revealed or once the game finished
+func (b *board) render() string {
+	var sb strings.Builder
+	sb.WriteString(home)
+	fmt.Fprintf(&sb, "%sminesweeper%s  %dx%d  mines %d  flags %d  %s  %s\r\n\r\n", bold, reset, b.game.Rows,
+		b.game.Cols, b.game.Mines, b.flags(), b.elapsed(), b.status())
+	for r, fields := range b.game.MineField {
+		sb.WriteString("  ")
+		for c := range fields {
+			cell := b.cell(&fields[c])
+			if r == b.row && c == b.col {
+				cell = reverse + stripColor(cell) + reset
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteString("\r\n")
+	}
+	live := "live updates off"
+	if b.streaming {
+		live = "live"
+	}
+	fmt.Fprintf(&sb, "\r\n%s%s  arrows/hjkl move  space reveal  f flag/unflag  c chord  r reload  q back%s\r\n", dim, live,
+		reset)
+	if b.message != "" {
+		fmt.Fprintf(&sb, "\r\n%s\r\n", b.message)
+	}
+
+	return sb.String()
+}
+
+func (b *board) status() string {
+	switch b.game.Status {
+	case client.GameStatusVictory:
+		return bold + "\x1b[32mvictory!" + reset
+	case client.GameStatusDefeat:
+		return bold + red + "defeat" + reset
+	}
+
+	return string(b.game.Status)
+}
+
+func (b *board) cell(field *client.Field) string {
+	switch {
+	case field.Flagged:
+		return red + "F " + reset
+	case field.Clicked && field.Mine, b.finished() && field.Mine:
+		return red + "* " + reset
+	case !field.Clicked:
+		return dim + ". " + reset
+	case field.AdjCount == 0:
+		return "  "
+	}
+	color := ""
+	if field.AdjCount < len(countColors) {
+		color = countColors[field.AdjCount]
+	}
+
+	return fmt.Sprintf("%s%d %s", color, field.AdjCount, reset)
+}
+
+// stripColor leaves the text of a cell, to draw it under the cursor
+func stripColor(s string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escaped = true
+		case escaped:
+			escaped = r != 'm'
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
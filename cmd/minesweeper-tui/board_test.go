@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Board", func() {
+	var b *board
+
+	BeforeEach(func() {
+		game := &client.Game{ID: "game", Rows: 3, Cols: 3, Mines: 1, Status: client.GameStatusCreated,
+			MineField: make([][]client.Field, 3)}
+		for i := range game.MineField {
+			game.MineField[i] = make([]client.Field, 3)
+		}
+		game.MineField[2][2].Mine = true
+		b = newBoard(game)
+	})
+
+	Describe("apply", func() {
+		It("updates the fields the cells changed, leaving the ones off the board out", func() {
+			b.apply([]client.CellDelta{
+				{Row: 0, Col: 0, Revealed: true, AdjMines: 1, ClickedBy: "alice"},
+				{Row: 1, Col: 1, Flagged: true},
+				{Row: 3, Col: 0, Revealed: true},
+				{Row: 0, Col: -1, Revealed: true},
+			}, "", time.Now())
+			Expect(b.game.MineField[0][0].Clicked).To(BeTrue())
+			Expect(b.game.MineField[0][0].AdjCount).To(Equal(1))
+			Expect(b.game.MineField[0][0].ClickedBy).To(Equal("alice"))
+			Expect(b.game.MineField[1][1].Flagged).To(BeTrue())
+			Expect(b.flags()).To(Equal(1))
+
+			b.apply([]client.CellDelta{{Row: 1, Col: 1}}, "", time.Now())
+			Expect(b.game.MineField[1][1].Flagged).To(BeFalse())
+		})
+
+		It("keeps the mines known, the deltas only tell the ones revealed", func() {
+			b.apply([]client.CellDelta{{Row: 2, Col: 2, Flagged: true}}, "", time.Now())
+			Expect(b.game.MineField[2][2].Mine).To(BeTrue())
+		})
+
+		It("times the game from its start to its end", func() {
+			startedAt := time.Now().Add(-time.Minute)
+			b.apply(nil, client.GameStatusStarted, startedAt)
+			Expect(b.game.Status).To(Equal(client.GameStatusStarted))
+			Expect(b.startedAt).To(Equal(&startedAt))
+			Expect(b.finished()).To(BeFalse())
+
+			finishedAt := startedAt.Add(42 * time.Second)
+			b.apply([]client.CellDelta{{Row: 2, Col: 2, Revealed: true, Mine: true}}, client.GameStatusDefeat,
+				finishedAt)
+			Expect(b.finished()).To(BeTrue())
+			Expect(b.finishedAt).To(Equal(&finishedAt))
+			Expect(b.elapsed()).To(Equal(42 * time.Second))
+
+			// Statuses told again don't move the times
+			b.apply(nil, client.GameStatusDefeat, time.Now())
+			Expect(b.finishedAt).To(Equal(&finishedAt))
+		})
+	})
+
+	Describe("drawing", func() {
+		It("strips the colors of the cells", func() {
+			Expect(stripColor(red + "F " + reset)).To(Equal("F "))
+			Expect(stripColor(countColors[7] + "7 " + reset)).To(Equal("7 "))
+			Expect(stripColor(". ")).To(Equal(". "))
+		})
+
+		It("draws the screens over the last one instead of clearing it", func() {
+			var out bytes.Buffer
+			a := newApp(nil, &out, nil)
+			a.draw(b.render())
+			screen := out.String()
+			Expect(screen).To(HavePrefix(home))
+			Expect(screen).NotTo(ContainSubstring(clearScreen))
+			Expect(screen).To(HaveSuffix(clearBelow))
+			for _, line := range strings.Split(strings.TrimSuffix(screen, clearBelow), "\r\n") {
+				if line != "" {
+					Expect(line).To(HaveSuffix(clearLine))
+				}
+			}
+		})
+	})
+})
@@ -0,0 +1,58 @@
+package main
+
+import "bufio"
+
+// key is a key pressed in the terminal
+type key int
+
+// Keys other than the printable ones, which are their rune
+const (
+	keyUp key = -(iota + 1)
+	keyDown
+	keyLeft
+	keyRight
+	keyEnter
+	keyEscape
+	keyCtrlC
+)
+
+// readKeys decodes the keys read from the terminal in raw mode, until it can't be read anymore
+func readKeys(in *bufio.Reader, keys chan<- key) {
+	defer close(keys)
+	for {
+		b, err := in.ReadByte()
+		if err != nil {
+			return
+		}
+		switch b {
+		case 3:
+			keys <- keyCtrlC
+		case '\r', '\n':
+			keys <- keyEnter
+		case 27:
+			// Arrows come as ESC [ A..D, a lone ESC is the escape key
+			if in.Buffered() < 2 {
+				keys <- keyEscape
+				continue
+			}
+			if next, _ := in.Peek(1); next[0] != '[' && next[0] != 'O' {
+				keys <- keyEscape
+				continue
+			}
+			_, _ = in.ReadByte()
+			arrow, _ := in.ReadByte()
+			switch arrow {
+			case 'A':
+				keys <- keyUp
+			case 'B':
+				keys <- keyDown
+			case 'C':
+				keys <- keyRight
+			case 'D':
+				keys <- keyLeft
+			}
+		default:
+			keys <- key(b)
+		}
+	}
+}
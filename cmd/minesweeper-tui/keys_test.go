@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keys", func() {
+	// read decodes the keys of the input until it ends
+	read := func(input string) []key {
+		keys := make(chan key)
+		go readKeys(bufio.NewReader(strings.NewReader(input)), keys)
+		var read []key
+		for k := range keys {
+			read = append(read, k)
+		}
+
+		return read
+	}
+
+	It("decodes the printable keys, enter and ctrl-c", func() {
+		Expect(read("f c\r\n\x03")).To(Equal([]key{'f', ' ', 'c', keyEnter, keyEnter, keyCtrlC}))
+	})
+
+	It("decodes the arrows in both of their encodings", func() {
+		Expect(read("\x1b[A\x1b[B\x1b[C\x1b[D\x1bOA\x1bOD")).To(Equal([]key{keyUp, keyDown, keyRight, keyLeft, keyUp,
+			keyLeft}))
+	})
+
+	It("tells a lone escape apart from the arrows", func() {
+		Expect(read("q\x1b")).To(Equal([]key{'q', keyEscape}))
+		Expect(read("\x1bx[A")).To(Equal([]key{keyEscape, 'x', '[', 'A'}))
+	})
+
+	It("closes the keys once the input ends", func() {
+		Expect(read("")).To(BeEmpty())
+	})
+})
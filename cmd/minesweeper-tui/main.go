@@ -0,0 +1,84 @@
+// Command minesweeper-tui plays the games of the service in the terminal: it signs in, lists the games of the
+// player, creates games of the classic sizes and plays them, following the moves of whoever else plays them
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cmelgarejo/minesweeper-svc/client"
+	"golang.org/x/term"
+)
+
+func main() {
+	baseURL := flag.String("url", env("MINESWEEPER_URL", "http://localhost:8080"), "URL of the minesweeper service")
+	username := flag.String("user", "", "username to sign in with, asked for when there's no API key")
+	apiKey := flag.String("api-key", os.Getenv("MINESWEEPER_API_KEY"), "API key to play with instead of signing in")
+	flag.Parse()
+
+	ctx := context.Background()
+	c := client.NewClientSvc(client.Config{BaseURL: *baseURL, APIKey: *apiKey})
+	// The username and the keys are read through the same buffer, for no key typed ahead to get lost
+	stdin := bufio.NewReader(os.Stdin)
+	if *apiKey == "" {
+		if err := signIn(ctx, c, stdin, *username); err != nil {
+			fmt.Fprintln(os.Stderr, "sign in failed:", err)
+			os.Exit(1)
+		}
+	}
+	if err := run(ctx, c, stdin); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run plays in the terminal in raw mode, restoring it once the player quits
+func run(ctx context.Context, c client.Client, stdin *bufio.Reader) error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("the terminal can't be played in: %w", err)
+	}
+	defer func() {
+		fmt.Print(home + clearScreen + showCursor)
+		_ = term.Restore(fd, state)
+	}()
+	fmt.Print(hideCursor + clearScreen)
+	keys := make(chan key)
+	go readKeys(stdin, keys)
+
+	return newApp(c, os.Stdout, keys).run(ctx)
+}
+
+// signIn asks for the credentials the user isn't given for, the password without echoing it
+func signIn(ctx context.Context, c client.Client, stdin *bufio.Reader, username string) error {
+	if username == "" {
+		fmt.Print("username: ")
+		line, err := stdin.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		username = strings.TrimSpace(line)
+	}
+	fmt.Print("password: ")
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	_, err = c.SignIn(ctx, client.Credentials{Username: username, Password: string(password)})
+
+	return err
+}
+
+func env(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/client"
+)
+
+// errQuit is returned by the screens when the player wants to leave the app
+var errQuit = errors.New("quit")
+
+// preset is a board size offered when creating a game
+type preset struct {
+	name  string
+	input client.GameCreateInput
+}
+
+// presets are the sizes of the classic difficulties
+var presets = []preset{
+	{name: "beginner", input: client.GameCreateInput{Rows: 9, Cols: 9, Mines: 10}},
+	{name: "intermediate", input: client.GameCreateInput{Rows: 16, Cols: 16, Mines: 40}},
+	{name: "expert", input: client.GameCreateInput{Rows: 16, Cols: 30, Mines: 99}},
+}
+
+// app draws the screens on out and plays them with the keys, talking to the server through the client
+type app struct {
+	c      client.Client
+	out    io.Writer
+	keys   <-chan key
+	player string // name the events tell the moves of the player by
+}
+
+func newApp(c client.Client, out io.Writer, keys <-chan key) *app {
+	return &app{c: c, out: out, keys: keys}
+}
+
+// run shows the games of the player until they quit
+func (a *app) run(ctx context.Context) error {
+	var (
+		games    []client.GameSummary
+		selected int
+		message  string
+	)
+	refresh := func() {
+		var err error
+		if games, err = a.c.MyGames(ctx, ""); err != nil {
+			message = err.Error()
+		}
+		if selected >= len(games) {
+			selected = 0
+		}
+	}
+	if me, err := a.c.Me(ctx); err == nil {
+		a.player = me.Fullname
+	}
+	refresh()
+	for {
+		a.draw(renderMenu(games, selected, message))
+		message = ""
+		k, ok := <-a.keys
+		if !ok {
+			return nil
+		}
+		switch k {
+		case keyUp, 'k':
+			if selected > 0 {
+				selected--
+			}
+		case keyDown, 'j':
+			if selected < len(games)-1 {
+				selected++
+			}
+		case keyEnter:
+			if len(games) == 0 {
+				continue
+			}
+			if err := a.play(ctx, games[selected].ID); err != nil {
+				if err == errQuit {
+					return nil
+				}
+				message = err.Error()
+			}
+			refresh()
+		case 'n':
+			gameID, err := a.create(ctx)
+			if err == errQuit {
+				return nil
+			}
+			if err != nil {
+				message = err.Error()
+			}
+			if gameID != "" {
+				if err = a.play(ctx, gameID); err == errQuit {
+					return nil
+				} else if err != nil {
+					message = err.Error()
+				}
+			}
+			selected = 0
+			refresh()
+		case 'r':
+			refresh()
+		case 'q', keyCtrlC:
+			return nil
+		}
+	}
+}
+
+func renderMenu(games []client.GameSummary, selected int, message string) string {
+	var sb strings.Builder
+	sb.WriteString(home)
+	fmt.Fprintf(&sb, "%sminesweeper%s  your games\r\n\r\n", bold, reset)
+	if len(games) == 0 {
+		sb.WriteString("  no games yet, press n to create one\r\n")
+	}
+	for i, game := range games {
+		line := fmt.Sprintf("  %-36s  %5s  %2dx%-2d  %3d mines  %-9s  %s  ", game.ID, game.Type, game.Rows, game.Cols,
+			game.Mines, game.Status, game.CreatedAt.Local().Format("2006-01-02 15:04"))
+		if i == selected {
+			line = reverse + line + reset
+		}
+		sb.WriteString(line + "\r\n")
+	}
+	fmt.Fprintf(&sb, "\r\n%sarrows/jk select  enter play  n new game  r refresh  q quit%s\r\n", dim, reset)
+	if message != "" {
+		fmt.Fprintf(&sb, "\r\n%s\r\n", message)
+	}
+
+	return sb.String()
+}
+
+// create asks for a preset and creates a game of it, it returns no game when the player changed their mind
+func (a *app) create(ctx context.Context) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(home)
+	fmt.Fprintf(&sb, "%snew game%s\r\n\r\n", bold, reset)
+	for i, p := range presets {
+		fmt.Fprintf(&sb, "  %d  %-12s  %2dx%-2d  %d mines\r\n", i+1, p.name, p.input.Rows, p.input.Cols, p.input.Mines)
+	}
+	fmt.Fprintf(&sb, "\r\n%s1-%d create  q back%s\r\n", dim, len(presets), reset)
+	a.draw(sb.String())
+	for {
+		k, ok := <-a.keys
+		if !ok || k == keyCtrlC {
+			return "", errQuit
+		}
+		if k == 'q' || k == keyEscape {
+			return "", nil
+		}
+		if i := int(k - '1'); i >= 0 && i < len(presets) {
+			game, err := a.c.CreateGame(ctx, presets[i].input)
+			if err != nil {
+				return "", err
+			}
+
+			return game.ID, nil
+		}
+	}
+}
+
+// play plays the game until the player goes back, the board is kept up to date with the events of the game
+// while the server streams them
+func (a *app) play(ctx context.Context, gameID string) error {
+	game, err := a.c.GetGame(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	b := newBoard(game)
+	if !b.playable() {
+		return fmt.Errorf("game %s can't be played here, only classic boards without fog can", gameID)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := a.follow(ctx, gameID)
+	b.streaming = events != nil
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		a.draw(b.render())
+		select {
+		case k, ok := <-a.keys:
+			if !ok || k == keyCtrlC {
+				return errQuit
+			}
+			if k == 'q' || k == keyEscape {
+				return nil
+			}
+			b.message = ""
+			a.handleKey(ctx, b, k)
+		case e, ok := <-events:
+			if !ok {
+				events, b.streaming = nil, false
+				continue
+			}
+			a.handleEvent(ctx, b, e)
+		case <-ticker.C:
+		}
+	}
+}
+
+// follow streams the events of the game until the context is done, it returns no channel when the server
+// doesn't stream them and closes it when the stream is lost
+func (a *app) follow(ctx context.Context, gameID string) <-chan *client.Event {
+	stream, err := a.c.Events(ctx, gameID)
+	if err != nil {
+		return nil
+	}
+	events := make(chan *client.Event)
+	go func() {
+		defer close(events)
+		defer stream.Close()
+		for {
+			e, err := stream.Next()
+			if err != nil {
+				return
+			}
+			select {
+			case events <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+func (a *app) handleKey(ctx context.Context, b *board, k key) {
+	switch k {
+	case keyUp, 'k':
+		b.move(-1, 0)
+	case keyDown, 'j':
+		b.move(1, 0)
+	case keyLeft, 'h':
+		b.move(0, -1)
+	case keyRight, 'l':
+		b.move(0, 1)
+	case ' ', keyEnter:
+		// Revealing a revealed count chords it, like clicking both buttons on it
+		field := b.field()
+		a.playMove(ctx, b, func(row, col int) (*client.Delta, error) {
+			return a.c.Reveal(ctx, b.game.ID, client.RevealInput{Row: row, Col: col,
+				Chord: field.Clicked && field.AdjCount > 0})
+		})
+	case 'c':
+		a.playMove(ctx, b, func(row, col int) (*client.Delta, error) {
+			return a.c.Reveal(ctx, b.game.ID, client.RevealInput{Row: row, Col: col, Chord: true})
+		})
+	case 'f':
+		// Flagging a flagged field takes the flag off
+		flag := a.c.Flag
+		if b.field().Flagged {
+			flag = a.c.Unflag
+		}
+		a.playMove(ctx, b, func(row, col int) (*client.Delta, error) {
+			return flag(ctx, b.game.ID, client.FlagInput{Row: row, Col: col})
+		})
+	case 'r':
+		a.reload(ctx, b)
+	}
+}
+
+// playMove plays a move on the field under the cursor, starting the game first if it wasn't
+func (a *app) playMove(ctx context.Context, b *board, move func(row, col int) (*client.Delta, error)) {
+	if b.finished() {
+		b.message = "the game is over, q to go back"
+		return
+	}
+	if b.game.Status == client.GameStatusCreated {
+		game, err := a.c.StartGame(ctx, b.game.ID)
+		if err != nil {
+			b.message = err.Error()
+			return
+		}
+		b.game.Status, b.game.Version, b.startedAt = game.Status, game.Version, game.StartedAt
+	}
+	delta, err := move(b.row, b.col)
+	if err != nil {
+		b.message = err.Error()
+		return
+	}
+	b.apply(delta.Cells, delta.Status, time.Now())
+	if delta.Version > b.game.Version {
+		b.game.Version = delta.Version
+	}
+	if b.finished() {
+		a.reload(ctx, b)
+	}
+}
+
+func (a *app) handleEvent(ctx context.Context, b *board, e *client.Event) {
+	switch e.Type {
+	case client.EventResync:
+		a.reload(ctx, b)
+		return
+	case client.EventPlayerJoined:
+		if e.Player != a.player {
+			b.message = e.Player + " is watching"
+		}
+		return
+	}
+	// The moves of the player were applied already, their events come after
+	if e.Version != 0 && e.Version <= b.game.Version {
+		return
+	}
+	b.apply(e.Cells, e.Status, e.At)
+	if e.Version != 0 {
+		b.game.Version = e.Version
+	}
+	if e.Type == client.EventGameFinished {
+		a.reload(ctx, b)
+	}
+}
+
+// reload reads the game again, keeping the cursor where it was
+func (a *app) reload(ctx context.Context, b *board) {
+	game, err := a.c.GetGame(ctx, b.game.ID)
+	if err != nil {
+		b.message = err.Error()
+		return
+	}
+	b.game, b.startedAt, b.finishedAt = game, game.StartedAt, game.FinishedAt
+}
+
+// draw draws the screen over the last one, clearing what's left of the lines and of the screen below it
+func (a *app) draw(screen string) {
+	_, _ = io.WriteString(a.out, strings.ReplaceAll(screen, "\r\n", clearLine+"\r\n")+clearBelow)
+}
@@ -28,3 +28,13 @@ func NewDB(cfg DBConfig, log *logger.Logger) (*DB, error) {
 		DB:     db,
 	}, nil
 }
+
+// Close closes the connection pool of the db
+func (db *DB) Close() error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
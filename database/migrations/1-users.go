@@ -3,7 +3,6 @@ package migrations
 import (
 	"github.com/cmelgarejo/minesweeper-svc/database/models"
 	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
-	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
 	"github.com/go-gormigrate/gormigrate/v2"
 	"gorm.io/gorm"
 )
@@ -56,8 +55,7 @@ func firstUserMigration() *gormigrate.Migration {
 			if err = tx.Create(p2).Error; err != nil {
 				return err
 			}
-			gameService := service.MineSweeperGameSvcImpl{}
-			game, _ := gameService.NewMineSweeperSvc().CreateGame(5, 5, 3, adm.Fullname)
+			game := engine.NewGame(5, 5, 3, engine.Rules{}, adm.Fullname)
 			game.ID = TestGameID
 			testGame := &models.Game{
 				Rows: 5, Cols: 5, Mines: 3,
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func gameAttemptsMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "GAME_ATTEMPTS",
+		Migrate: func(tx *gorm.DB) (err error) {
+			return tx.AutoMigrate(models.Game{})
+		},
+		Rollback: func(tx *gorm.DB) (err error) {
+			if err = tx.Migrator().DropColumn(&models.Game{}, "PreviousAttemptID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.Game{}, "FirstAttemptID")
+		},
+	}
+}
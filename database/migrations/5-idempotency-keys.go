@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func idempotencyKeysMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "IDEMPOTENCY_KEYS",
+		Migrate: func(tx *gorm.DB) (err error) {
+			return tx.AutoMigrate(models.IdempotencyKey{})
+		},
+		Rollback: func(tx *gorm.DB) (err error) {
+			return tx.Migrator().DropTable(&models.IdempotencyKey{})
+		},
+	}
+}
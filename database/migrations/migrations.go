@@ -9,6 +9,10 @@ func RunMigrations(db *gorm.DB, migrations ...*gormigrate.Migration) (err error)
 	e := append([]*gormigrate.Migration{
 		initialMigration(),
 		firstUserMigration(),
+		gameDifficultyMigration(),
+		gameSavesMigration(),
+		gameAttemptsMigration(),
+		idempotencyKeysMigration(),
 	}, migrations...)
 	m := gormigrate.New(db, gormigrate.DefaultOptions, e)
 
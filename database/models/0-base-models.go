@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/cmelgarejo/minesweeper-svc/utils"
@@ -32,8 +33,16 @@ func (j JSONB) Value() (driver.Value, error) {
 	return string(valueString), err
 }
 
+// Scan reads the JSON of the column, drivers like the sqlite one give it back as the string Value stored
 func (j *JSONB) Scan(value interface{}) error {
-	if err := json.Unmarshal(value.([]byte), &j); err != nil {
+	data, ok := value.([]byte)
+	if s, isString := value.(string); isString {
+		data, ok = []byte(s), true
+	}
+	if !ok {
+		return fmt.Errorf("JSONB can't be scanned from %T", value)
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
 		return err
 	}
 	return nil
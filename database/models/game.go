@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cmelgarejo/minesweeper-svc/utils"
@@ -10,27 +11,116 @@ import (
 // Game contains the structure of the game
 type Game struct {
 	BaseModel
-	Rows        int        `json:"rows"`
-	Cols        int        `json:"cols"`
-	Mines       int        `json:"mines"`
-	Status      string     `json:"status"`
-	GameState   JSONB      `json:"gameState" gorm:"type:jsonb"`
-	StartedAt   *time.Time `json:"startedAt,omitempty"`
-	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
-	CreatedByID string     `json:"-"`         // who created this game - id needed by GORM
-	CreatedBy   *User      `json:"createdBy"` // who created this game
+	Rows              int        `json:"rows"`
+	Cols              int        `json:"cols"`
+	Mines             int        `json:"mines"`
+	Status            string     `json:"status"`
+	Difficulty        float64    `json:"difficulty"`                            // difficulty rating of the board
+	Tier              string     `json:"tier" gorm:"index"`                     // difficulty tier of the board
+	Unranked          bool       `json:"unranked"`                              // forked and restored games don't count for rankings
+	PreviousAttemptID string     `json:"previousAttemptID,omitempty"`           // attempt at the same board retried
+	FirstAttemptID    string     `json:"firstAttemptID,omitempty" gorm:"index"` // first attempt at the same board
+	GameState         JSONB      `json:"gameState" gorm:"type:jsonb"`
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	FinishedAt        *time.Time `json:"finishedAt,omitempty"`
+	CreatedByID       string     `json:"-"`         // who created this game - id needed by GORM
+	CreatedBy         *User      `json:"createdBy"` // who created this game
 }
 
 func (g *Game) UpdateGameState(game *engine.Game) {
-	b, _ := utils.ToJSONBytes(game)
+	g.GameState = encodeGameState(game)
+	g.Unranked = game.Unranked
+	g.PreviousAttemptID = game.PreviousAttemptID
+	g.FirstAttemptID = game.FirstAttemptID
+	g.Status = string(game.Status)
+	g.StartedAt = game.StartedAt
+	g.FinishedAt = game.FinishedAt
+	if game.Difficulty != nil {
+		g.Difficulty = game.Difficulty.Rating
+		g.Tier = game.Difficulty.Tier
+	}
+}
+
+// GetGameState decodes the stored game state, the state still has to be validated by the game service
+func (g *Game) GetGameState() (game *engine.Game, err error) {
+	if g.GameState == nil {
+		return nil, fmt.Errorf("Game %s has no state stored", g.ID)
+	}
+	if _, err = g.UpgradeGameState(); err != nil {
+		return nil, err
+	}
+
+	return decodeGameState(g.GameState)
+}
+
+// UpgradeGameState moves the stored game state to the current schema version, it returns whether the
+// state changed and has to be stored again
+func (g *Game) UpgradeGameState() (upgraded bool, err error) {
+	if g.GameState == nil {
+		return false, nil
+	}
+	state := map[string]interface{}(g.GameState)
+	first := engine.StateVersion(state) == 0
+	if first {
+		// The first states were stored with only their mine field, the game itself lived in the columns. Its
+		// sizes and mines are counted out of the mine field by the upgrader, the columns kept the input unclamped.
+		defaults := map[string]interface{}{
+			"id":        g.ID,
+			"rows":      g.Rows,
+			"cols":      g.Cols,
+			"mines":     g.Mines,
+			"status":    g.Status,
+			"createdAt": g.CreatedAt,
+		}
+		for key, value := range defaults {
+			if _, found := state[key]; !found {
+				state[key] = value
+			}
+		}
+	}
+	if upgraded, err = engine.UpgradeState(state); err != nil || !first {
+		return upgraded, err
+	}
+	g.Rows, g.Cols, g.Mines = stateInt(state, "rows"), stateInt(state, "cols"), stateInt(state, "mines")
+
+	return upgraded, nil
+}
+
+// stateInt reads a number of a stored game state, whether it was decoded from JSON or set as an int
+func stateInt(state map[string]interface{}, key string) int {
+	switch value := state[key].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	default:
+		return 0
+	}
+}
+
+// encodeGameState stores a game state in the current schema version, with its board compacted
+func encodeGameState(game *engine.Game) JSONB {
+	game.SchemaVersion = engine.StateSchemaVersion
+	b, _ := utils.ToJSONBytes(game.Compact())
 	gameState := JSONB{}
 	_ = utils.ToObject(b, &gameState)
-	g.GameState = gameState
+
+	return gameState
 }
 
-func (g *Game) GetGameState() (game *engine.Game) {
-	b, _ := utils.ToJSONBytes(g.GameState)
-	_ = utils.ToObject(b, &game)
+// decodeGameState upgrades a stored game state to the current schema version and decodes it
+func decodeGameState(gameState JSONB) (game *engine.Game, err error) {
+	if _, err = engine.UpgradeState(gameState); err != nil {
+		return nil, err
+	}
+	b, err := utils.ToJSONBytes(gameState)
+	if err != nil {
+		return nil, err
+	}
+	var compact engine.CompactGame
+	if err = utils.ToObject(b, &compact); err != nil {
+		return nil, err
+	}
 
-	return
+	return compact.Expand()
 }
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// IdempotencyKey is a mutating request a user made with an Idempotency-Key header and the response it got,
+// replayed when the request is retried with the same key
+type IdempotencyKey struct {
+	BaseModel
+	UserID      string    `gorm:"uniqueIndex:idx_idempotency_user_key"`
+	Key         string    `gorm:"uniqueIndex:idx_idempotency_user_key"`
+	RequestHash string    // hash of the method, url and body of the request
+	StatusCode  int       // zero while the request is in flight
+	ContentType string    // of the response
+	Body        []byte    // of the response
+	ExpiresAt   time.Time `gorm:"index"` // the key can be reused after this, in flight it's the end of its lease
+}
+
+// Completed tells if the response of the request was stored
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
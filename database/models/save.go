@@ -0,0 +1,31 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+)
+
+// GameSave is a named snapshot of a game, it can be restored later as a practice game
+type GameSave struct {
+	BaseModel
+	GameID      string `json:"gameId" gorm:"uniqueIndex:idx_game_save_name"`
+	Name        string `json:"name" gorm:"uniqueIndex:idx_game_save_name"`
+	GameState   JSONB  `json:"-" gorm:"type:jsonb"`
+	CreatedByID string `json:"-"`         // who saved the game - id needed by GORM
+	CreatedBy   *User  `json:"createdBy"` // who saved the game
+}
+
+// UpdateGameState snapshots the game state in the save
+func (s *GameSave) UpdateGameState(game *engine.Game) {
+	s.GameState = encodeGameState(game)
+}
+
+// GetGameState decodes the saved game state
+func (s *GameSave) GetGameState() (*engine.Game, error) {
+	if s.GameState == nil {
+		return nil, fmt.Errorf("Save %s of game %s has no state stored", s.Name, s.GameID)
+	}
+
+	return decodeGameState(s.GameState)
+}
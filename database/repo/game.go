@@ -2,16 +2,32 @@ package repo
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/cmelgarejo/minesweeper-svc/database"
 	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type GameRepo interface {
 	UpsertGame(ctx context.Context, gameID *string, input *models.Game) (*models.Game, error)
 	Read(ctx context.Context, gameID string) (game *models.Game, err error)
+	ReadWithDeleted(ctx context.Context, gameID string) (game *models.Game, err error)
 	List(ctx context.Context) (games []*models.Game, err error)
+	ListByCreator(ctx context.Context, userID string) (games []*models.Game, err error)
+	ListIdle(ctx context.Context, before time.Time) (games []*models.Game, err error)
+	Delete(ctx context.Context, gameID string) error
+	Restore(ctx context.Context, gameID string) error
+	HardDelete(ctx context.Context, gameID string) error
+	UpgradeGameStates(ctx context.Context) (upgraded int, err error)
+	UpdateGameStates(ctx context.Context, games []*models.Game) error
+	UpsertSave(ctx context.Context, save *models.GameSave) (*models.GameSave, error)
+	ReadSave(ctx context.Context, gameID, name string) (save *models.GameSave, err error)
+	ListSaves(ctx context.Context, gameID string) (saves []*models.GameSave, err error)
+	ListAttempts(ctx context.Context, firstAttemptID, createdByID string) (games []*models.Game, err error)
 }
 
 type GameRepoSvc struct {
@@ -31,19 +47,71 @@ func (svc *GameRepoSvc) Read(ctx context.Context, gameID string) (*models.Game,
 	return rec, err
 }
 
+// ReadWithDeleted reads a game even if it was soft deleted
+func (svc *GameRepoSvc) ReadWithDeleted(ctx context.Context, gameID string) (*models.Game, error) {
+	rec := &models.Game{BaseModel: models.BaseModel{ID: gameID}}
+	err := svc.db.Unscoped().Model(rec).Preload(clause.Associations).First(rec).Error
+
+	return rec, err
+}
+
+// ListIdle lists the started games not stored since before
+func (svc *GameRepoSvc) ListIdle(ctx context.Context, before time.Time) (games []*models.Game, err error) {
+	err = svc.db.Model(games).Where("status = ? AND updated_at < ?", engine.GameStatusStarted, before).
+		Find(&games).Error
+
+	return
+}
+
+// Delete soft deletes a game, it can be restored later
+func (svc *GameRepoSvc) Delete(ctx context.Context, gameID string) error {
+	return svc.db.Delete(&models.Game{BaseModel: models.BaseModel{ID: gameID}}).Error
+}
+
+// Restore brings back a soft deleted game
+func (svc *GameRepoSvc) Restore(ctx context.Context, gameID string) error {
+	return svc.db.Unscoped().Model(&models.Game{BaseModel: models.BaseModel{ID: gameID}}).
+		Update("deleted_at", nil).Error
+}
+
+// HardDelete removes a game and its save slots for good
+func (svc *GameRepoSvc) HardDelete(ctx context.Context, gameID string) error {
+	return svc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("game_id = ?", gameID).Delete(&models.GameSave{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Game{BaseModel: models.BaseModel{ID: gameID}}).Error
+	})
+}
+
 func (svc *GameRepoSvc) List(ctx context.Context) (games []*models.Game, err error) {
 	err = svc.db.Model(games).Preload(clause.Associations).Find(&games).Error
 
 	return
 }
 
+// ListByCreator lists the games created by the user, the newest first
+func (svc *GameRepoSvc) ListByCreator(ctx context.Context, userID string) (games []*models.Game, err error) {
+	err = svc.db.Model(games).Preload(clause.Associations).Where("created_by_id = ?", userID).
+		Order("created_at DESC").Find(&games).Error
+
+	return
+}
+
+// UpsertGame creates the game, or updates the stored one when its id is given. Soft deleted games are
+// left alone, updating them answers gorm.ErrRecordNotFound instead of bringing them back
 func (svc *GameRepoSvc) UpsertGame(ctx context.Context, gameID *string, input *models.Game) (*models.Game, error) {
 	var err error
 	if gameID == nil {
 		err = svc.db.Model(input).FirstOrCreate(input, input).Error
 	} else {
 		input.ID = *gameID
-		err = svc.db.Unscoped().Model(input).Save(input).Error
+		// Updates skip the soft delete scope of gorm, it has to be spelled out
+		result := svc.db.Model(input).Where("deleted_at IS NULL").Select("*").Omit("id", "created_at", "deleted_at", clause.Associations).
+			Updates(input)
+		if err = result.Error; err == nil && result.RowsAffected == 0 {
+			err = gorm.ErrRecordNotFound
+		}
 	}
 	if err != nil {
 		return nil, err
@@ -51,3 +119,86 @@ func (svc *GameRepoSvc) UpsertGame(ctx context.Context, gameID *string, input *m
 
 	return input, nil
 }
+
+// UpgradeGameStates rewrites every stored game state that is behind the current schema version
+func (svc *GameRepoSvc) UpgradeGameStates(ctx context.Context) (upgraded int, err error) {
+	var games []*models.Game
+	err = svc.db.WithContext(ctx).Unscoped().Model(&models.Game{}).Where("game_state IS NOT NULL").
+		FindInBatches(&games, 100, func(tx *gorm.DB, batch int) error {
+			// tx still holds the clauses of the batch query, each batch gets stored in a transaction of its own
+			return tx.Session(&gorm.Session{NewDB: true}).Transaction(func(tx *gorm.DB) error {
+				for _, game := range games {
+					changed, err := game.UpgradeGameState()
+					if err != nil {
+						return fmt.Errorf("Game %s: %w", game.ID, err)
+					}
+					if !changed {
+						continue
+					}
+					err = tx.Unscoped().Model(game).Updates(map[string]interface{}{"game_state": game.GameState,
+						"rows": game.Rows, "cols": game.Cols, "mines": game.Mines}).Error
+					if err != nil {
+						return err
+					}
+					upgraded++
+				}
+
+				return nil
+			})
+		}).Error
+
+	return upgraded, err
+}
+
+// UpsertSave stores a save slot, overwriting the save of the game with the same name
+func (svc *GameRepoSvc) UpsertSave(ctx context.Context, save *models.GameSave) (*models.GameSave, error) {
+	err := svc.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "game_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"game_state", "created_by_id", "updated_at"}),
+	}).Create(save).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return save, nil
+}
+
+func (svc *GameRepoSvc) ReadSave(ctx context.Context, gameID, name string) (*models.GameSave, error) {
+	rec := &models.GameSave{}
+	err := svc.db.Model(rec).Where("game_id = ? AND name = ?", gameID, name).First(rec).Error
+
+	return rec, err
+}
+
+func (svc *GameRepoSvc) ListSaves(ctx context.Context, gameID string) (saves []*models.GameSave, err error) {
+	err = svc.db.Model(saves).Where("game_id = ?", gameID).Order("created_at").Find(&saves).Error
+
+	return
+}
+
+// ListAttempts lists every attempt at a board, starting with the first one
+func (svc *GameRepoSvc) ListAttempts(ctx context.Context, firstAttemptID, createdByID string) (games []*models.Game, err error) {
+	err = svc.db.Model(games).Where("id = ? OR first_attempt_id = ?", firstAttemptID, firstAttemptID).
+		Where("created_by_id = ?", createdByID).Order("created_at").Find(&games).Error
+
+	return
+}
+
+// gameStateColumns are the columns derived from the game state, see models.Game.UpdateGameState
+var gameStateColumns = []string{"game_state", "status", "started_at", "finished_at", "difficulty", "tier",
+	"unranked", "previous_attempt_id", "first_attempt_id", "updated_at"}
+
+// UpdateGameStates stores the state of the games in a single transaction, only the columns derived from
+// the state are written. Soft deleted games are left alone
+func (svc *GameRepoSvc) UpdateGameStates(ctx context.Context, games []*models.Game) error {
+	return svc.db.Transaction(func(tx *gorm.DB) error {
+		for _, game := range games {
+			game.UpdatedAt = time.Now()
+			err := tx.Model(game).Where("deleted_at IS NULL").Select(gameStateColumns).Updates(game).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepo interface {
+	Reserve(ctx context.Context, key *models.IdempotencyKey) (existing *models.IdempotencyKey, err error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) error
+	Release(ctx context.Context, key *models.IdempotencyKey) error
+	Purge(ctx context.Context, before time.Time) (purged int64, err error)
+}
+
+type IdempotencyRepoSvc struct {
+	db *database.DB
+}
+
+func NewIdempotencyRepoSvc(db *database.DB) IdempotencyRepo {
+	return &IdempotencyRepoSvc{
+		db: db,
+	}
+}
+
+// Reserve stores the key of a request about to be handled, unless the user already used it. In that case
+// the stored key is returned, with the response of the request when it was completed. Expired keys, be
+// it their retention or the lease of a request that never completed, are replaced.
+func (svc *IdempotencyRepoSvc) Reserve(ctx context.Context, key *models.IdempotencyKey) (
+	existing *models.IdempotencyKey, err error) {
+	err = svc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ? AND key = ? AND expires_at < ?", key.UserID, key.Key, time.Now()).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+		existing = &models.IdempotencyKey{}
+
+		return tx.Where("user_id = ? AND key = ?", key.UserID, key.Key).First(existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Complete stores the response of the request of the key, and when it expires
+func (svc *IdempotencyRepoSvc) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+	return svc.db.Model(key).Select("status_code", "content_type", "body", "expires_at", "updated_at").Updates(key).Error
+}
+
+// Release drops the key of a request that could not be completed, so it can be retried
+func (svc *IdempotencyRepoSvc) Release(ctx context.Context, key *models.IdempotencyKey) error {
+	return svc.db.Unscoped().Delete(key).Error
+}
+
+// Purge drops the keys expired before
+func (svc *IdempotencyRepoSvc) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result := svc.db.Unscoped().Where("expires_at < ?", before).Delete(&models.IdempotencyKey{})
+
+	return result.RowsAffected, result.Error
+}
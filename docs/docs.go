@@ -31,6 +31,105 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/graphql": {
+            "get": {
+                "description": "Upgrades to a WebSocket speaking the graphql-ws protocol: after connection_init, each start message runs a subscription like gameEvents and sends its events as data messages until stopped. A socket runs up to 8 subscriptions at once.\nClients that can't set headers on the upgrade request can send the API key in the apiKey query parameter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graph"
+                ],
+                "summary": "Subscribes to GraphQL subscriptions over a WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API Key, for clients that can't set headers",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "graphql-ws messages",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "426": {
+                        "description": "Upgrade Required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Queries the games, users, moves and stats of the graph, or creates, starts, clicks and flags games. Fields telling what a user owns are only resolved for the user itself and the admins.\nAnswers in the GraphQL response format, errors carry the code and details of the message catalog in their extensions. Subscriptions to the game events go over a WebSocket upgrade of this same path, with the graphql-ws protocol.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graph"
+                ],
+                "summary": "Runs a GraphQL query or mutation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Query Input",
+                        "name": "queryInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graph.QueryInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GraphQL response with data and errors",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/api/games": {
             "get": {
                 "description": "Gets a list of games, only Admins can see it",
@@ -45,6 +144,15 @@ var doc = `{
                 ],
                 "summary": "Gets a list of games",
                 "parameters": [
+                    {
+                        "enum": [
+                            "compact"
+                        ],
+                        "type": "string",
+                        "description": "Mine field encoding, compact packs it as a base64 board",
+                        "name": "encoding",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "default": "587fa65a9c375165828a6fbb5f9963a7",
@@ -102,6 +210,12 @@ var doc = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Game Input",
                         "name": "gameInput",
@@ -140,9 +254,9 @@ var doc = `{
                 }
             }
         },
-        "/v1/api/games/start/{id}": {
+        "/v1/api/games/abandon": {
             "post": {
-                "description": "Starts a game of minesweeper and returns the mine field state",
+                "description": "Abandons every started game that wasn't played for longer than the configured idle timeout, only Admins can do it",
                 "consumes": [
                     "application/json"
                 ],
@@ -152,16 +266,8 @@ var doc = `{
                 "tags": [
                     "game"
                 ],
-                "summary": "Starts a game of minesweeper",
+                "summary": "Abandons the idle games",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
-                        "description": "Game ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "default": "587fa65a9c375165828a6fbb5f9963a7",
@@ -169,6 +275,12 @@ var doc = `{
                         "name": "X-API-KEY",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -199,9 +311,9 @@ var doc = `{
                 }
             }
         },
-        "/v1/api/games/{id}": {
-            "get": {
-                "description": "Gets the information of a minesweeper game, fields and users",
+        "/v1/api/games/codes/{code}": {
+            "post": {
+                "description": "Creates a game with the same board as the game the code was taken from, finished games show their board code",
                 "consumes": [
                     "application/json"
                 ],
@@ -211,13 +323,12 @@ var doc = `{
                 "tags": [
                     "game"
                 ],
-                "summary": "Gets the information of a minesweeper game",
+                "summary": "Creates a game of minesweeper out of a board code",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
-                        "description": "Game ID",
-                        "name": "id",
+                        "description": "Board code",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
@@ -228,11 +339,17 @@ var doc = `{
                         "name": "X-API-KEY",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/responses.Response"
                         }
@@ -256,9 +373,11 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Clicks field on a game of minesweeper and returns the mine field state",
+            }
+        },
+        "/v1/api/games/start/{id}": {
+            "post": {
+                "description": "Starts a game of minesweeper and returns the mine field state",
                 "consumes": [
                     "application/json"
                 ],
@@ -268,8 +387,17 @@ var doc = `{
                 "tags": [
                     "game"
                 ],
-                "summary": "Clicks field on a game of minesweeper",
+                "summary": "Starts a game of minesweeper",
                 "parameters": [
+                    {
+                        "enum": [
+                            "compact"
+                        ],
+                        "type": "string",
+                        "description": "Mine field encoding, compact packs it as a base64 board",
+                        "name": "encoding",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "default": "ef99fdfd88565827ad330d83aac5fbaa",
@@ -287,13 +415,10 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "Game Input",
-                        "name": "gameInput",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.GameInput"
-                        }
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -324,9 +449,9 @@ var doc = `{
                 }
             }
         },
-        "/v1/auth/signIn": {
-            "post": {
-                "description": "Sign in user of minesweeper and returns an API Key",
+        "/v1/api/games/{id}": {
+            "get": {
+                "description": "Gets the information of a minesweeper game, fields and users",
                 "consumes": [
                     "application/json"
                 ],
@@ -334,18 +459,34 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "game"
                 ],
-                "summary": "Sign in user of minesweeper",
+                "summary": "Gets the information of a minesweeper game",
                 "parameters": [
                     {
-                        "description": "Credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.Credentials"
-                        }
+                        "enum": [
+                            "compact"
+                        ],
+                        "type": "string",
+                        "description": "Mine field encoding, compact packs it as a base64 board",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -374,11 +515,9 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/auth/user": {
-            "post": {
-                "description": "Creates an user of minesweeper and returns an userID",
+            },
+            "delete": {
+                "description": "Soft deletes a game, which can be restored later. Admins can delete it for good with hard=true",
                 "consumes": [
                     "application/json"
                 ],
@@ -386,23 +525,42 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "game"
                 ],
-                "summary": "Creates an user of minesweeper",
+                "summary": "Deletes a game of minesweeper",
                 "parameters": [
                     {
-                        "description": "User Input",
-                        "name": "userInput",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/requests.UserInput"
-                        }
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete the game for good, only Admins can do it",
+                        "name": "hard",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/responses.Response"
                         }
@@ -426,11 +584,9 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/auth/user/{id}": {
-            "get": {
-                "description": "Gets the information of a minesweeper user, fields and users",
+            },
+            "patch": {
+                "description": "Clicks field on a game of minesweeper and returns the mine field state, losing clicks tell the defeat by the status of the game",
                 "consumes": [
                     "application/json"
                 ],
@@ -438,16 +594,58 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "game"
                 ],
-                "summary": "Gets the information of a minesweeper user",
+                "summary": "Clicks field on a game of minesweeper",
                 "parameters": [
                     {
+                        "enum": [
+                            "compact"
+                        ],
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Mine field encoding, compact packs it as a base64 board",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "delta"
+                        ],
+                        "type": "string",
+                        "description": "Response mode, delta returns only the changed cells, the status and the version of the game, defeats included",
+                        "name": "response",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Game Input",
+                        "name": "gameInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.GameInput"
+                        }
                     }
                 ],
                 "responses": {
@@ -476,9 +674,11 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an user of minesweeper and returns an userID",
+            }
+        },
+        "/v1/api/games/{id}/attempts": {
+            "get": {
+                "description": "Lists the attempts of the caller at the board of the game, from the first one on, with their outcome, moves and time played",
                 "consumes": [
                     "application/json"
                 ],
@@ -486,24 +686,1515 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "game"
                 ],
-                "summary": "Updates an user of minesweeper",
+                "summary": "Lists the attempts at the board of a game of minesweeper",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User Input",
-                        "name": "userInput",
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/difficulty": {
+            "get": {
+                "description": "Rates the board using its 3BV, the safe fields a deductive solver can't reach without guessing, its openings and its mine density",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Rates the difficulty of the board of a minesweeper game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/forfeit": {
+            "post": {
+                "description": "Gives up a game that didn't finish, the game moves to the forfeited status and its board is revealed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Forfeits a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/fork": {
+            "post": {
+                "description": "Copies the board, the moves and the timer of a game into a new unranked game owned by the caller, forks of lost games continue from right before the losing move",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Forks a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/moves": {
+            "post": {
+                "description": "Plays the clicks and flags in order, with no other move played in between and the game stored once. The moves after one ending the game are skipped, the ones that can't be played are reported and the batch goes on.\nReturns the outcome of each move with what it changed, and the version and status of the game after the batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Plays a batch of moves in a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Moves Input",
+                        "name": "movesInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.GameMovesInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/restore": {
+            "post": {
+                "description": "Brings back a soft deleted game, games deleted for good can't be restored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Restores a deleted game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/retry": {
+            "post": {
+                "description": "Creates a new game with the same board and rules as the game, linked to it as its previous attempt, the game itself is left untouched",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Retries the board of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/saves": {
+            "get": {
+                "description": "Lists the named save slots of a game, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Lists the save slots of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Snapshots the current state of a game in a named save slot, saving again with the same name overwrites the slot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Saves a game of minesweeper in a named slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Save Input",
+                        "name": "saveInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.GameSaveInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/saves/{name}/restore": {
+            "post": {
+                "description": "Creates a practice game owned by the caller out of a save slot, practice games are unranked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Restores a save slot of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Save slot name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/api/games/{id}/view": {
+            "get": {
+                "description": "Gets a window of the board, endless games accept any coordinate, even negative ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Gets a window of the board of a minesweeper game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Top row of the window",
+                        "name": "row",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Left col of the window",
+                        "name": "col",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Rows of the window",
+                        "name": "rows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Cols of the window",
+                        "name": "cols",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/signIn": {
+            "post": {
+                "description": "Sign in user of minesweeper and returns an API Key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Sign in user of minesweeper",
+                "parameters": [
+                    {
+                        "description": "Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.Credentials"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/user": {
+            "post": {
+                "description": "Creates an user of minesweeper and returns an userID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Creates an user of minesweeper",
+                "parameters": [
+                    {
+                        "description": "User Input",
+                        "name": "userInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/user/{id}": {
+            "get": {
+                "description": "Gets the information of a minesweeper user, fields and users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Gets the information of a minesweeper user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an user of minesweeper and returns an userID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Updates an user of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Input",
+                        "name": "userInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.UserInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games": {
+            "post": {
+                "description": "Creates a game of minesweeper and returns it, located by the Location header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Creates a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Game Input",
+                        "name": "gameInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.GameCreateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}": {
+            "get": {
+                "description": "Gets a game of minesweeper as the current user is allowed to see it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Gets a game of minesweeper",
+                "parameters": [
+                    {
+                        "enum": [
+                            "compact"
+                        ],
+                        "type": "string",
+                        "description": "Mine field encoding, compact packs it as a base64 board",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft deletes a game, which can be restored later. Admins can delete it for good with hard=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Deletes a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete the game for good, only Admins can do it",
+                        "name": "hard",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}/events": {
+            "get": {
+                "description": "Server-Sent Events stream of the game events (cell.revealed, flag.changed, game.started, game.finished, player.joined) carrying only the changed cells, each with its seq as id.\nReconnecting clients send the seq of the last event they got in Last-Event-ID, or in the lastEventId query parameter, to get the events they missed. When those are gone a game.resync event asks them to read the game again.\nClients that can't set headers can send the API key in the apiKey query parameter.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Streams the events of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seq of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seq of the last event received, for clients that can't set headers",
+                        "name": "lastEventId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API Key, for clients that can't set headers",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/engine.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}/flags": {
+            "post": {
+                "description": "Flags a field and returns what changed: the cells, the status and the version of the game",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Flags a field of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Flag Input",
+                        "name": "flagInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.FlagInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Takes the flag off a field and returns what changed: the cells, the status and the version of the game. Fields without a flag are left as they are.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Takes the flag off a field of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Flag Input",
+                        "name": "flagInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.FlagInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}/moves": {
+            "post": {
+                "description": "Plays the clicks and flags in order, with no other move played in between and the game stored once. The moves after one ending the game are skipped, the ones that can't be played are reported and the batch goes on.\nReturns the outcome of each move with what it changed, and the version and status of the game after the batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Plays a batch of moves in a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Moves Input",
+                        "name": "movesInput",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/requests.GameMovesInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}/reveals": {
+            "post": {
+                "description": "Reveals a field, or with chord the fields around a revealed one once its mines are flagged, and returns what changed: the cells, the status and the version of the game, defeats included",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Reveals a field of a game of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Reveal Input",
+                        "name": "revealInput",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/requests.UserInput"
+                            "$ref": "#/definitions/requests.RevealInput"
                         }
                     }
                 ],
@@ -526,6 +2217,186 @@ var doc = `{
                             "$ref": "#/definitions/responses.ResponseError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}/start": {
+            "post": {
+                "description": "Starts a created game of minesweeper and returns it, games already started conflict",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Starts a game of minesweeper",
+                "parameters": [
+                    {
+                        "enum": [
+                            "compact"
+                        ],
+                        "type": "string",
+                        "description": "Mine field encoding, compact packs it as a base64 board",
+                        "name": "encoding",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key get the response of the first request",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/games/{id}/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket that pushes the events of the game as they happen (cell.revealed, flag.changed, game.started, game.finished, player.joined) carrying only the changed cells.\nCommands like {\"type\": \"click\", \"row\": 0, \"col\": 0, \"clickType\": \"flag\"} are played by the current user, failed ones are answered with an error message.\nClients that can't set headers on the upgrade request can send the API key in the apiKey query parameter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "game"
+                ],
+                "summary": "Plays a game of minesweeper over a WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "ef99fdfd88565827ad330d83aac5fbaa",
+                        "description": "Game ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API Key, for clients that can't set headers",
+                        "name": "apiKey",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/engine.Event"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
+                    "426": {
+                        "description": "Upgrade Required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/api/users/me": {
+            "get": {
+                "description": "Gets the user the API key belongs to, with its API keys",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Gets the current user of minesweeper",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "587fa65a9c375165828a6fbb5f9963a7",
+                        "description": "API Key",
+                        "name": "X-API-KEY",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.ResponseError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -537,6 +2408,87 @@ var doc = `{
         }
     },
     "definitions": {
+        "engine.CellDelta": {
+            "type": "object",
+            "properties": {
+                "adjMines": {
+                    "type": "integer"
+                },
+                "clickedBy": {
+                    "type": "string"
+                },
+                "col": {
+                    "type": "integer"
+                },
+                "flagged": {
+                    "type": "boolean"
+                },
+                "fogged": {
+                    "description": "revealed out of sight of the player, its count is hidden",
+                    "type": "boolean"
+                },
+                "mine": {
+                    "type": "boolean"
+                },
+                "revealed": {
+                    "type": "boolean"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "engine.Event": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "cells": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/engine.CellDelta"
+                    }
+                },
+                "gameID": {
+                    "type": "string"
+                },
+                "player": {
+                    "description": "who caused the event",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "position of the event in the events of the game, starting at 1",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "version of the game after the event",
+                    "type": "integer"
+                }
+            }
+        },
+        "graph.QueryInput": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ me { username stats { played winRate } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
         "requests.Credentials": {
             "type": "object",
             "properties": {
@@ -550,13 +2502,63 @@ var doc = `{
                 }
             }
         },
+        "requests.FlagInput": {
+            "type": "object",
+            "properties": {
+                "col": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "requests.Fog": {
+            "type": "object",
+            "properties": {
+                "memory": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "radius": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
         "requests.GameCreateInput": {
             "type": "object",
             "properties": {
+                "arcade": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "col": {
                     "type": "integer",
                     "example": 5
                 },
+                "durability": {
+                    "description": "Durability of the game in write-behind mode, by default ranked games store every move",
+                    "type": "string",
+                    "enum": [
+                        "move",
+                        "batch"
+                    ]
+                },
+                "fog": {
+                    "$ref": "#/definitions/requests.Fog"
+                },
+                "kernel": {
+                    "type": "string",
+                    "enum": [
+                        "moore",
+                        "knight",
+                        "manhattan2"
+                    ],
+                    "example": "moore"
+                },
                 "mines": {
                     "type": "integer",
                     "example": 5
@@ -564,6 +2566,23 @@ var doc = `{
                 "row": {
                     "type": "integer",
                     "example": 5
+                },
+                "tier": {
+                    "type": "string",
+                    "enum": [
+                        "beginner",
+                        "intermediate",
+                        "expert",
+                        "master"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "classic",
+                        "endless"
+                    ],
+                    "example": "classic"
                 }
             }
         },
@@ -574,7 +2593,11 @@ var doc = `{
                     "type": "string",
                     "enum": [
                         "click",
-                        "flag"
+                        "flag",
+                        "unflag",
+                        "chord",
+                        "radar",
+                        "detector"
                     ]
                 },
                 "col": {
@@ -587,6 +2610,43 @@ var doc = `{
                 }
             }
         },
+        "requests.GameMovesInput": {
+            "type": "object",
+            "properties": {
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/requests.GameInput"
+                    }
+                }
+            }
+        },
+        "requests.GameSaveInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "before the last corner"
+                }
+            }
+        },
+        "requests.RevealInput": {
+            "type": "object",
+            "properties": {
+                "chord": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "col": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
         "requests.UserInput": {
             "type": "object",
             "properties": {
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
 	"github.com/cmelgarejo/minesweeper-svc/utils/config"
 	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
 	server "github.com/cmelgarejo/minesweeper-svc/web"
 	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc"
 	"github.com/loopcontext/msgcat"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -24,10 +33,50 @@ func main() {
 		log.SendFatal(err)
 	}
 	gamesSvc := service.MineSweeperGameSvcImpl{}
-	gameEngine := gamesSvc.NewMineSweeperSvc()
-	appsrv, err := server.InitFiberServer(cfg, log, &catalog, db, gameEngine)
+	gameEngine := gamesSvc.NewMineSweeperSvc(log, gameConfig(cfg.Game), repo.NewGameRepoSvc(db))
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	gameEngine.StartWorkers(workersCtx)
+	appsrv, err := server.InitFiberServer(workersCtx, cfg, log, &catalog, db, gameEngine)
 	if err != nil {
 		log.SendFatal(err)
 	}
-	log.SendFatal(appsrv.Listen(cfg.Server.BuildServerAddr()))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		if err := appsrv.Listen(cfg.Server.BuildServerAddr()); err != nil {
+			log.SendFatal(err)
+		}
+	}()
+	var grpcsrv *grpc.Server
+	if cfg.Server.GRPCPort != 0 {
+		lis, err := net.Listen("tcp", cfg.Server.BuildGRPCAddr())
+		if err != nil {
+			log.SendFatal(err)
+		}
+		grpcsrv = rpc.NewServer(*log, catalog, repo.NewAuthRepoSvc(db, *log, catalog), repo.NewGameRepoSvc(db),
+			gameEngine)
+		go func() {
+			if err := grpcsrv.Serve(lis); err != nil {
+				log.SendFatal(err)
+			}
+		}()
+	}
+	sig := <-signals
+	log.Info().Str("signal", sig.String()).Msg("Shutting down")
+	stopWorkers()
+	server.Shutdown(log, cfg.Server.ShutdownTimeout, appsrv, grpcsrv, gameEngine, db)
+}
+
+// gameConfig builds the configuration of the game service out of the settings read from the environment
+func gameConfig(cfg config.Game) service.Config {
+	return service.Config{
+		RepairState:   cfg.RepairState,
+		IdleTimeout:   cfg.IdleTimeout,
+		CacheSize:     cfg.CacheSize,
+		CacheTTL:      cfg.CacheTTL,
+		ReapInterval:  cfg.ReapInterval,
+		WriteBehind:   cfg.WriteBehind,
+		FlushInterval: cfg.FlushInterval,
+		FlushBatch:    cfg.FlushBatch,
+	}
 }
@@ -16,4 +16,16 @@ var (
 	MsgCodeReqHelperLimit1Obj        = 1307
 	MsgCodeProcessOkWithErrs         = 1400
 	MsgCodeTotalDefeat               = 1500
+	MsgCodeInvalidGameRules          = 1600
+	MsgCodeCorruptedGameState        = 1601
+	MsgCodeInvalidBoardCode          = 1602
+	MsgCodeGameNotOwned              = 1603
+	MsgCodeUnknownGameCommand        = 1604
+	MsgCodeInvalidMoves              = 1605
+	MsgCodeInvalidIdempotencyKey     = 1700
+	MsgCodeIdempotencyKeyReused      = 1701
+	MsgCodeIdempotencyKeyInFlight    = 1702
+	MsgCodeGraphFieldDenied          = 1800
+	MsgCodeGraphUnknownMessage       = 1801
+	MsgCodeGraphTooManySubscriptions = 1802
 )
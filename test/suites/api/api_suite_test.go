@@ -0,0 +1,186 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/config"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	server "github.com/cmelgarejo/minesweeper-svc/web"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/middleware"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/gofiber/adaptor/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/loopcontext/msgcat"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestAPI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "API Suite")
+}
+
+var (
+	app        *fiber.App
+	db         *database.DB
+	gameEngine service.MineSweeperGameSvc
+	// apiServer serves the Fiber app through net/http
+	apiServer *httptest.Server
+	// appAddr is where the Fiber app listens itself, for the sockets and the event streams the net/http
+	// adaptor can't serve
+	appAddr string
+)
+
+var _ = BeforeSuite(func() {
+	gdb, err := gorm.Open(sqlite.Open("file:api_suite?mode=memory&cache=shared"),
+		&gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	Expect(err).NotTo(HaveOccurred())
+	// Connections to a shared in-memory db lock each other out of its tables instead of waiting
+	sqlDB, err := gdb.DB()
+	Expect(err).NotTo(HaveOccurred())
+	sqlDB.SetMaxOpenConns(1)
+	Expect(migrations.RunMigrations(gdb)).To(Succeed())
+	log := logger.New(false)
+	db = &database.DB{Logger: log, DB: gdb}
+	catalog, err := msgcat.NewMessageCatalog(msgcat.Config{ResourcePath: "../../../resources/messages"})
+	Expect(err).NotTo(HaveOccurred())
+	cfg := &config.Config{Server: config.Server{ResponseContentType: common.AppTypeJSON, IdleTimeout: time.Second,
+		IdempotencyRetention: config.DefaultIdempotencyRetention, IdempotencyLease: config.DefaultIdempotencyLease}}
+	gamesSvc := service.MineSweeperGameSvcImpl{}
+	gameEngine = gamesSvc.NewMineSweeperSvc(log, service.Config{}, repo.NewGameRepoSvc(db))
+	app, err = server.InitFiberServer(context.Background(), cfg, log, &catalog, db, gameEngine)
+	Expect(err).NotTo(HaveOccurred())
+	apiServer = httptest.NewServer(adaptor.FiberApp(app))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	appAddr = ln.Addr().String()
+	go func() {
+		_ = app.Listener(ln)
+	}()
+})
+
+var _ = AfterSuite(func() {
+	if apiServer != nil {
+		apiServer.Close()
+	}
+	if app != nil {
+		// Event streams never end on their own
+		gameEngine.CloseSubscriptions()
+		Expect(app.Shutdown()).To(Succeed())
+	}
+})
+
+// apiResponse is the envelope of every response of the API
+type apiResponse struct {
+	Code     int             `json:"code"`
+	Message  string          `json:"message"`
+	Response json.RawMessage `json:"response"`
+}
+
+// call sends a request to the API as the user of the API key, headers are pairs of names and values
+func call(method, path, apiKey string, body interface{}, headers ...string) (*http.Response, apiResponse) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+	}
+	req, err := http.NewRequest(method, apiServer.URL+path, bytes.NewReader(payload))
+	Expect(err).NotTo(HaveOccurred())
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set(middleware.HeaderAPIKey, apiKey)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	var decoded apiResponse
+	if resp.StatusCode != http.StatusNoContent {
+		Expect(json.NewDecoder(resp.Body).Decode(&decoded)).To(Succeed())
+	}
+
+	return resp, decoded
+}
+
+// decode decodes the payload of a response
+func decode(resp apiResponse, out interface{}) {
+	ExpectWithOffset(1, json.Unmarshal(resp.Response, out)).To(Succeed())
+}
+
+// users counts the users signed up, to keep their names unique across specs
+var users int
+
+// signUp creates a user and signs it in, returning its API key
+func signUp(name string) string {
+	users++
+	username := fmt.Sprintf("%s%d", name, users)
+	credentials := requests.Credentials{Username: username, Password: username}
+	resp, _ := call(http.MethodPost, "/v1/auth/user", "", requests.UserInput{
+		Credentials: credentials,
+		Email:       username + "@minesweeper.svc",
+		Fullname:    "Player " + username,
+	})
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+	resp, body := call(http.MethodPost, "/v1/auth/signIn", "", credentials)
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	var apiKey models.UserAPIKey
+	decode(body, &apiKey)
+
+	return apiKey.APIKey
+}
+
+// createGame creates a started game as the user of the API key, returning its ID
+func createGame(apiKey string) string {
+	resp, body := call(http.MethodPost, "/v1/api/games", apiKey, requests.GameCreateInput{Rows: 9, Cols: 9, Mines: 10})
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+	var gameID string
+	decode(body, &gameID)
+	resp, _ = call(http.MethodPost, "/v1/api/games/start/"+gameID, apiKey, nil)
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	return gameID
+}
+
+// makeAdmin grants the user of the API key the admin role
+func makeAdmin(apiKey string) {
+	key := &models.UserAPIKey{}
+	Expect(db.Where("api_key = ?", apiKey).First(key).Error).To(Succeed())
+	Expect(db.Model(&models.User{}).Where("id = ?", key.UserID).Update("admin", true).Error).To(Succeed())
+}
+
+// findField finds a field of the game with a mine, or without one
+func findField(gameID string, mine bool) engine.Position {
+	game, err := gameEngine.GetGame(gameID)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	for i := range game.MineField {
+		for j, field := range game.MineField[i] {
+			if field.Mine == mine {
+				return engine.Position{Row: i, Col: j}
+			}
+		}
+	}
+	Fail("the game has no such field")
+
+	return engine.Position{}
+}
@@ -0,0 +1,56 @@
+package api_test
+
+import (
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Attempts", func() {
+	var owner, other, gameID string
+
+	BeforeEach(func() {
+		owner, other = signUp("retrier"), signUp("rival")
+		gameID = createGame(owner)
+	})
+
+	retry := func(apiKey, gameID string) string {
+		resp, body := call(http.MethodPost, "/v1/api/games/"+gameID+"/retry", apiKey, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var retryID string
+		decode(body, &retryID)
+
+		return retryID
+	}
+
+	attempts := func(apiKey, gameID string) (ids []string) {
+		resp, body := call(http.MethodGet, "/v1/api/games/"+gameID+"/attempts", apiKey, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var list []engine.Attempt
+		decode(body, &list)
+		for _, attempt := range list {
+			ids = append(ids, attempt.ID)
+		}
+
+		return ids
+	}
+
+	It("lets other players retry only the games already finished", func() {
+		resp, _ := call(http.MethodPost, "/v1/api/games/"+gameID+"/retry", other, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		retry(other, gameID)
+	})
+
+	It("lists only the attempts of the caller at the board", func() {
+		second := retry(owner, gameID)
+		resp, _ := call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		rivalAttempt := retry(other, gameID)
+		Expect(attempts(owner, second)).To(Equal([]string{gameID, second}))
+		Expect(attempts(other, rivalAttempt)).To(Equal([]string{rivalAttempt}))
+	})
+})
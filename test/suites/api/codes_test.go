@@ -0,0 +1,41 @@
+package api_test
+
+import (
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Board codes", func() {
+	var apiKey string
+
+	BeforeEach(func() {
+		apiKey = signUp("coder")
+	})
+
+	It("creates games with the board of a code, answering 201 like the games created from scratch", func() {
+		resp, body := call(http.MethodPost, "/v1/api/games", apiKey, requests.GameCreateInput{Rows: 9, Cols: 9, Mines: 10})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var gameID string
+		decode(body, &gameID)
+		game, err := gameEngine.GetGame(gameID)
+		Expect(err).NotTo(HaveOccurred())
+		resp, body = call(http.MethodPost, "/v1/api/games/codes/"+game.BoardCode(), apiKey, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var copyID string
+		decode(body, &copyID)
+		copied, err := gameEngine.GetGame(copyID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copied.MineField).To(Equal(game.MineField))
+	})
+
+	It("refuses invalid codes", func() {
+		resp, _ := call(http.MethodPost, "/v1/api/games/codes/nope", apiKey, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		_, err := engine.NewGameFromCode("nope", "coder")
+		Expect(err).To(MatchError(engine.ErrInvalidBoardCode))
+	})
+})
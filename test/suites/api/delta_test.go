@@ -0,0 +1,59 @@
+package api_test
+
+import (
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Click responses", func() {
+	var alice, gameID string
+
+	BeforeEach(func() {
+		alice = signUp("clicker")
+		gameID = createGame(alice)
+	})
+
+	click := func(pos engine.Position, query string) (*http.Response, apiResponse) {
+		return call(http.MethodPatch, "/v1/api/games/"+gameID+query, alice,
+			requests.GameInput{Row: pos.Row, Col: pos.Col})
+	}
+
+	It("answers only the changed cells and the version of the game in delta mode", func() {
+		pos := findField(gameID, false)
+		resp, body := click(pos, "?response=delta")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var delta engine.Delta
+		decode(body, &delta)
+		game, err := gameEngine.GetGame(gameID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.GameID).To(Equal(gameID))
+		Expect(delta.Version).To(Equal(game.Version))
+		Expect(delta.Move.Row).To(Equal(pos.Row))
+		Expect(delta.Move.Col).To(Equal(pos.Col))
+		Expect(delta.Cells).NotTo(BeEmpty())
+		for _, cell := range delta.Cells {
+			Expect(game.MineField[cell.Row][cell.Col].Clicked).To(BeTrue())
+		}
+	})
+
+	It("answers a losing click like any other, in both modes", func() {
+		mine := findField(gameID, true)
+		resp, body := click(mine, "?response=delta")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var delta engine.Delta
+		decode(body, &delta)
+		Expect(delta.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+		Expect(delta.PreviousStatus).To(BeEquivalentTo(engine.GameStatusStarted))
+
+		gameID = createGame(alice)
+		resp, body = click(findField(gameID, true), "")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var game engine.Game
+		decode(body, &game)
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+	})
+})
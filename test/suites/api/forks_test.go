@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Forks and saves", func() {
+	var owner, other, gameID string
+
+	BeforeEach(func() {
+		owner, other = signUp("forker"), signUp("peeker")
+		gameID = createGame(owner)
+	})
+
+	It("lets the creator fork the game", func() {
+		resp, body := call(http.MethodPost, "/v1/api/games/"+gameID+"/fork", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var forkID string
+		decode(body, &forkID)
+		fork, err := gameEngine.GetGame(forkID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fork.ForkedFrom).To(Equal(gameID))
+		Expect(fork.Unranked).To(BeTrue())
+	})
+
+	It("lets other players fork only the games already finished", func() {
+		resp, _ := call(http.MethodPost, "/v1/api/games/"+gameID+"/fork", other, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/fork", other, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+	})
+
+	It("saves and restores the slots of the creator only", func() {
+		save := requests.GameSaveInput{Name: "opening"}
+		resp, _ := call(http.MethodPost, "/v1/api/games/"+gameID+"/saves", other, save)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/saves", owner, save)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/saves/opening/restore", other, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		resp, body := call(http.MethodPost, "/v1/api/games/"+gameID+"/saves/opening/restore", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var restoredID string
+		decode(body, &restoredID)
+		restored, err := gameEngine.GetGame(restoredID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.Rules.Practice).To(BeTrue())
+		Expect(restored.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+	})
+})
@@ -0,0 +1,262 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/handlers/graph"
+	"github.com/cmelgarejo/minesweeper-svc/web/middleware"
+	"github.com/fasthttp/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// catalogGroup is the group of the message catalog, the codes of its errors are offset by it
+const catalogGroup = 10000
+
+// graphError is an error of a GraphQL response, carrying the code of the catalog error
+type graphError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code int `json:"code"`
+	} `json:"extensions"`
+}
+
+// graphResponse is a GraphQL response
+type graphResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphError    `json:"errors"`
+}
+
+// graphMessage is a message of the graphql-ws protocol
+type graphMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+var _ = Describe("GraphQL", func() {
+	var alice string
+
+	BeforeEach(func() {
+		alice = signUp("grapher")
+	})
+
+	// send runs a GraphQL query as the user of the API key, with the headers given as name and value pairs
+	send := func(apiKey, q string, variables map[string]interface{}, headers ...string) (*http.Response, graphResponse) {
+		payload, err := json.Marshal(graph.QueryInput{Query: q, Variables: variables})
+		Expect(err).NotTo(HaveOccurred())
+		req, err := http.NewRequest(http.MethodPost, apiServer.URL+"/graphql", bytes.NewReader(payload))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(middleware.HeaderAPIKey, apiKey)
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var decoded graphResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&decoded)).To(Succeed())
+
+		return resp, decoded
+	}
+
+	// query runs a GraphQL query as the user of the API key
+	query := func(apiKey, q string, variables map[string]interface{}) graphResponse {
+		_, decoded := send(apiKey, q, variables)
+
+		return decoded
+	}
+
+	It("answers queries and mutations", func() {
+		resp := query(alice, `{ me { username stats { played } } }`, nil)
+		Expect(resp.Errors).To(BeEmpty())
+		Expect(string(resp.Data)).To(ContainSubstring("grapher"))
+
+		resp = query(alice, `mutation { createGame(input: {rows: 9, cols: 9, mines: 10, kernel: "bogus"}) { id } }`, nil)
+		Expect(resp.Errors).To(HaveLen(1))
+		Expect(resp.Errors[0].Extensions.Code).To(Equal(catalogGroup + codes.MsgCodeInvalidGameRules))
+
+		resp = query(alice, `mutation { createGame(input: {rows: 9, cols: 9, mines: 10}) { id status } }`, nil)
+		Expect(resp.Errors).To(BeEmpty())
+		var created struct {
+			CreateGame struct {
+				ID     string `json:"id"`
+				Status string `json:"status"`
+			} `json:"createGame"`
+		}
+		Expect(json.Unmarshal(resp.Data, &created)).To(Succeed())
+		gameID := created.CreateGame.ID
+		Expect(created.CreateGame.Status).To(Equal(engine.GameStatusCreated))
+
+		resp = query(alice, `mutation($id: ID!) { startGame(id: $id) { status } }`, map[string]interface{}{"id": gameID})
+		Expect(resp.Errors).To(BeEmpty())
+		mine := findField(gameID, true)
+		resp = query(alice, `mutation($id: ID!, $row: Int!, $col: Int!) { flag(id: $id, row: $row, col: $col) { cells { flagged } } }`,
+			map[string]interface{}{"id": gameID, "row": mine.Row, "col": mine.Col})
+		Expect(resp.Errors).To(BeEmpty())
+		Expect(string(resp.Data)).To(ContainSubstring(`"flagged":true`))
+		resp = query(alice, `mutation($id: ID!, $row: Int!, $col: Int!) { click(id: $id, row: $row, col: $col) { status } }`,
+			map[string]interface{}{"id": gameID, "row": 9, "col": 9})
+		Expect(resp.Errors).To(HaveLen(1))
+	})
+
+	It("resolves the games while their moves keep being played", func() {
+		gameID := createGame(alice)
+		mine := findField(gameID, true)
+		flags := 20
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			for i := 0; i < flags; i++ {
+				resp := query(alice, `mutation($id: ID!, $row: Int!, $col: Int!) { flag(id: $id, row: $row, col: $col) { version } }`,
+					map[string]interface{}{"id": gameID, "row": mine.Row, "col": mine.Col})
+				Expect(resp.Errors).To(BeEmpty())
+			}
+		}()
+		var resolved struct {
+			Game struct {
+				Moves []struct {
+					Seq int `json:"seq"`
+				} `json:"moves"`
+			} `json:"game"`
+		}
+		for read := true; read; {
+			select {
+			case <-done:
+				read = false
+			default:
+			}
+			resp := query(alice, `query($id: ID!) { game(id: $id) { version cells { flagged } moves { seq } } }`,
+				map[string]interface{}{"id": gameID})
+			Expect(resp.Errors).To(BeEmpty())
+			Expect(json.Unmarshal(resp.Data, &resolved)).To(Succeed())
+			for i, move := range resolved.Game.Moves {
+				Expect(move.Seq).To(Equal(i + 1))
+			}
+		}
+		Expect(resolved.Game.Moves).To(HaveLen(flags))
+	})
+
+	It("replays the mutations retried with the same idempotency key", func() {
+		gameID := createGame(alice)
+		mine := findField(gameID, true)
+		flag := `mutation($id: ID!, $row: Int!, $col: Int!) { flag(id: $id, row: $row, col: $col) { version cells { flagged } } }`
+		variables := map[string]interface{}{"id": gameID, "row": mine.Row, "col": mine.Col}
+		resp, first := send(alice, flag, variables, middleware.HeaderIdempotencyKey, "graph-flag")
+		Expect(first.Errors).To(BeEmpty())
+		Expect(resp.Header.Get(middleware.HeaderIdempotentReplayed)).To(BeEmpty())
+		Expect(string(first.Data)).To(ContainSubstring(`"flagged":true`))
+
+		// Played again, the flag would be taken off
+		resp, retried := send(alice, flag, variables, middleware.HeaderIdempotencyKey, "graph-flag")
+		Expect(resp.Header.Get(middleware.HeaderIdempotentReplayed)).To(Equal("true"))
+		Expect(retried).To(Equal(first))
+		game, err := gameEngine.GetGame(gameID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.MineField[mine.Row][mine.Col].Flagged).To(BeTrue())
+	})
+
+	It("keeps what users own to themselves", func() {
+		bob := signUp("peeker")
+		gameID := createGame(alice)
+		resp := query(bob, `query($id: ID!) { game(id: $id) { createdBy { username email } } }`,
+			map[string]interface{}{"id": gameID})
+		Expect(resp.Errors).To(HaveLen(1))
+		Expect(resp.Errors[0].Extensions.Code).To(Equal(catalogGroup + codes.MsgCodeGraphFieldDenied))
+		Expect(string(resp.Data)).To(ContainSubstring("grapher"))
+	})
+
+	It("refuses queries nested deeper than allowed", func() {
+		gameID := createGame(alice)
+		resp := query(alice, `query($id: ID!) { game(id: $id) { createdBy { games { createdBy { games {
+			createdBy { games { createdBy { id } } } } } } } } }`, map[string]interface{}{"id": gameID})
+		Expect(resp.Errors).NotTo(BeEmpty())
+		Expect(resp.Errors[0].Message).To(ContainSubstring(fmt.Sprint(graph.MaxDepth)))
+		Expect(string(resp.Data)).To(Or(BeEmpty(), Equal("null")))
+	})
+
+	Describe("over the socket", func() {
+		var conn *websocket.Conn
+
+		BeforeEach(func() {
+			u := url.URL{Scheme: "ws", Host: appAddr, Path: "/graphql",
+				RawQuery: url.Values{"apiKey": {alice}}.Encode()}
+			dialer := *websocket.DefaultDialer
+			dialer.Subprotocols = []string{graph.SocketProtocol}
+			var err error
+			conn, _, err = dialer.Dial(u.String(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conn.WriteJSON(graphMessage{Type: "connection_init"})).To(Succeed())
+			Expect(nextGraphMessage(conn, "").Type).To(Equal("connection_ack"))
+		})
+
+		AfterEach(func() {
+			conn.Close()
+		})
+
+		// subscribe starts a subscription to the events of the game
+		subscribe := func(id, gameID string) {
+			payload, err := json.Marshal(graph.QueryInput{Query: `subscription($id: ID!) { gameEvents(id: $id) { type } }`,
+				Variables: map[string]interface{}{"id": gameID}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conn.WriteJSON(graphMessage{ID: id, Type: "start", Payload: payload})).To(Succeed())
+		}
+
+		It("sends the events of the subscriptions", func() {
+			gameID := createGame(alice)
+			subscribe("events", gameID)
+			Expect(string(nextGraphMessage(conn, "events").Payload)).To(ContainSubstring(string(engine.EventPlayerJoined)))
+			mine := findField(gameID, true)
+			query(alice, `mutation($id: ID!, $row: Int!, $col: Int!) { flag(id: $id, row: $row, col: $col) { version } }`,
+				map[string]interface{}{"id": gameID, "row": mine.Row, "col": mine.Col})
+			Expect(string(nextGraphMessage(conn, "events").Payload)).To(ContainSubstring(string(engine.EventFlagChanged)))
+		})
+
+		It("runs up to so many subscriptions at once", func() {
+			gameID := createGame(alice)
+			for i := 0; i < graph.MaxSocketSubscriptions; i++ {
+				subscribe(fmt.Sprint("sub", i), gameID)
+				Expect(nextGraphMessage(conn, fmt.Sprint("sub", i)).Type).To(Equal("data"))
+			}
+			// Restarting a running subscription takes its place
+			subscribe("sub0", gameID)
+			Expect(nextGraphMessage(conn, "sub0").Type).To(Equal("data"))
+
+			subscribe("extra", gameID)
+			msg := nextGraphMessage(conn, "extra")
+			Expect(msg.Type).To(Equal("error"))
+			var qerr graphError
+			Expect(json.Unmarshal(msg.Payload, &qerr)).To(Succeed())
+			Expect(qerr.Extensions.Code).To(Equal(catalogGroup + codes.MsgCodeGraphTooManySubscriptions))
+
+			Expect(conn.WriteJSON(graphMessage{ID: "sub1", Type: "stop"})).To(Succeed())
+			Eventually(func() string {
+				subscribe("extra", gameID)
+				return nextGraphMessage(conn, "extra").Type
+			}).Should(Equal("data"))
+		})
+	})
+})
+
+// nextGraphMessage reads the next message of the GraphQL socket for the subscription of the ID, or the next one without
+// an ID when empty
+func nextGraphMessage(conn *websocket.Conn, id string) graphMessage {
+	ExpectWithOffset(1, conn.SetReadDeadline(time.Now().Add(5*time.Second))).To(Succeed())
+	for {
+		var msg graphMessage
+		ExpectWithOffset(1, conn.ReadJSON(&msg)).To(Succeed())
+		if msg.ID == id {
+			return msg
+		}
+	}
+}
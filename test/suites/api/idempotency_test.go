@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/middleware"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Idempotency keys", func() {
+	var alice, gameID, key string
+
+	// keys counts the keys used, to keep them unique across specs
+	var keys int
+
+	BeforeEach(func() {
+		alice = signUp("retrier")
+		gameID = createGame(alice)
+		keys++
+		key = fmt.Sprintf("retry-%d", keys)
+	})
+
+	click := func(clickType string, pos engine.Position, key string) (*http.Response, apiResponse) {
+		return call(http.MethodPatch, "/v1/api/games/"+gameID, alice,
+			requests.GameInput{Row: pos.Row, Col: pos.Col, ClickType: clickType}, middleware.HeaderIdempotencyKey, key)
+	}
+
+	version := func() uint64 {
+		game, err := gameEngine.GetGame(gameID)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+		return game.Version
+	}
+
+	// leave sets the key back in flight, as a request that never ended would have left it
+	leave := func(key string, lease time.Duration) {
+		Expect(db.Model(&models.IdempotencyKey{}).Where("key = ?", key).
+			Updates(map[string]interface{}{"status_code": 0, "expires_at": time.Now().Add(lease)}).Error).To(Succeed())
+	}
+
+	It("replays the response of a retried request without playing it again", func() {
+		pos := findField(gameID, false)
+		resp, first := click("click", pos, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get(middleware.HeaderIdempotentReplayed)).To(BeEmpty())
+		played := version()
+
+		resp, retried := click("click", pos, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get(middleware.HeaderIdempotentReplayed)).To(Equal("true"))
+		Expect(retried).To(Equal(first))
+		Expect(version()).To(Equal(played))
+	})
+
+	It("replays a losing click instead of playing it on the lost game", func() {
+		mine := findField(gameID, true)
+		resp, first := click("click", mine, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var game engine.Game
+		decode(first, &game)
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+
+		resp, retried := click("click", mine, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get(middleware.HeaderIdempotentReplayed)).To(Equal("true"))
+		Expect(retried).To(Equal(first))
+	})
+
+	It("rejects the key reused with a different request", func() {
+		resp, _ := click("flag", findField(gameID, true), key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = click("flag", findField(gameID, false), key)
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+	})
+
+	It("holds the key of a request in flight only while its lease lasts", func() {
+		mine := findField(gameID, true)
+		resp, _ := click("flag", mine, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		flagged := version()
+
+		leave(key, time.Minute)
+		resp, _ = click("flag", mine, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+		Expect(version()).To(Equal(flagged))
+
+		leave(key, -time.Second)
+		resp, _ = click("flag", mine, key)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get(middleware.HeaderIdempotentReplayed)).To(BeEmpty())
+		Expect(version()).To(BeNumerically(">", flagged))
+
+		stored := &models.IdempotencyKey{}
+		Expect(db.Where("key = ?", key).First(stored).Error).To(Succeed())
+		Expect(stored.Completed()).To(BeTrue())
+		Expect(stored.ExpiresAt).To(BeTemporally(">", time.Now().Add(time.Hour)))
+	})
+})
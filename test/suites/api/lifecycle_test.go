@@ -0,0 +1,71 @@
+package api_test
+
+import (
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game lifecycle", func() {
+	var owner, other, gameID string
+
+	BeforeEach(func() {
+		owner, other = signUp("quitter"), signUp("bystander")
+		gameID = createGame(owner)
+	})
+
+	It("lets only the creator forfeit the game, once", func() {
+		resp, _ := call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", other, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		resp, body := call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var game engine.Game
+		decode(body, &game)
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusForfeited))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+	})
+
+	It("soft deletes the game until it is restored", func() {
+		resp, _ := call(http.MethodDelete, "/v1/api/games/"+gameID, other, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		resp, _ = call(http.MethodDelete, "/v1/api/games/"+gameID, owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = call(http.MethodGet, "/v1/api/games/"+gameID, owner, nil)
+		Expect(resp.StatusCode).NotTo(Equal(http.StatusOK))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/restore", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = call(http.MethodGet, "/v1/api/games/"+gameID, owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("lets only the admins delete games for good", func() {
+		resp, _ := call(http.MethodDelete, "/v1/api/games/"+gameID+"?hard=true", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		admin := signUp("admin")
+		makeAdmin(admin)
+		resp, _ = call(http.MethodDelete, "/v1/api/games/"+gameID+"?hard=true", admin, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/restore", admin, nil)
+		Expect(resp.StatusCode).NotTo(Equal(http.StatusOK))
+	})
+
+	It("lists the stored games along the latest state of the ones in memory", func() {
+		admin := signUp("lister")
+		makeAdmin(admin)
+		deletedID := createGame(owner)
+		resp, _ := call(http.MethodDelete, "/v1/api/games/"+deletedID, owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, _ = call(http.MethodPost, "/v1/api/games/"+gameID+"/forfeit", owner, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		resp, body := call(http.MethodGet, "/v1/api/games", admin, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var list map[string]*engine.Game
+		decode(body, &list)
+		Expect(list).To(HaveKey(gameID))
+		Expect(list[gameID].Status).To(BeEquivalentTo(engine.GameStatusForfeited))
+		Expect(list).NotTo(HaveKey(deletedID))
+	})
+})
@@ -0,0 +1,85 @@
+package api_test
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Batch moves", func() {
+	var alice, gameID string
+
+	BeforeEach(func() {
+		alice = signUp("batcher")
+		gameID = createGame(alice)
+	})
+
+	play := func(moves ...requests.GameInput) (*http.Response, engine.MovesResult) {
+		resp, body := call(http.MethodPost, "/v1/api/games/"+gameID+"/moves", alice,
+			requests.GameMovesInput{Moves: moves})
+		var result engine.MovesResult
+		if resp.StatusCode == http.StatusOK {
+			decode(body, &result)
+		}
+
+		return resp, result
+	}
+
+	It("plays the moves in order, reporting the ones that failed and skipping the ones after the end", func() {
+		safe, mine := findField(gameID, false), findField(gameID, true)
+		resp, result := play(
+			requests.GameInput{Row: safe.Row, Col: safe.Col, ClickType: "flag"},
+			requests.GameInput{Row: 100, Col: 100},
+			requests.GameInput{Row: mine.Row, Col: mine.Col},
+			requests.GameInput{Row: safe.Row, Col: safe.Col},
+		)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(result.Moves).To(HaveLen(4))
+		Expect(result.Moves[0].Outcome).To(Equal(engine.MoveOutcomePlayed))
+		Expect(result.Moves[0].Delta.Cells).To(HaveLen(1))
+		Expect(result.Moves[1].Outcome).To(Equal(engine.MoveOutcomeFailed))
+		Expect(result.Moves[1].Error).NotTo(BeEmpty())
+		Expect(result.Moves[2].Outcome).To(Equal(engine.MoveOutcomePlayed))
+		Expect(result.Moves[3].Outcome).To(Equal(engine.MoveOutcomeSkipped))
+		Expect(result.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+		game, err := gameEngine.GetGame(gameID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Version).To(Equal(game.Version))
+	})
+
+	It("refuses empty batches and the ones with too many moves", func() {
+		resp, _ := play()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp, _ = play(make([]requests.GameInput, requests.MovesMaxCount+1)...)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("keeps every move of the batches sent at the same time", func() {
+		game, err := gameEngine.GetGame(gameID)
+		Expect(err).NotTo(HaveOccurred())
+		var wg sync.WaitGroup
+		for row := 0; row < game.Rows; row++ {
+			moves := make([]requests.GameInput, game.Cols)
+			for col := range moves {
+				moves[col] = requests.GameInput{Row: row, Col: col, ClickType: "flag"}
+			}
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				resp, _ := play(moves...)
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			}()
+		}
+		wg.Wait()
+		resp, body := call(http.MethodGet, "/v1/api/games/"+gameID, alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var played engine.Game
+		decode(body, &played)
+		Expect(played.History).To(HaveLen(game.Rows * game.Cols))
+	})
+})
@@ -0,0 +1,120 @@
+package api_test
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/handlers/games"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game resources", func() {
+	var alice string
+
+	BeforeEach(func() {
+		alice = signUp("resourcer")
+	})
+
+	// create creates a game on the v2 routes, returning its location
+	create := func() string {
+		resp, _ := call(http.MethodPost, games.GamesResourcePath, alice,
+			requests.GameCreateInput{Rows: 9, Cols: 9, Mines: 10})
+		ExpectWithOffset(1, resp.StatusCode).To(Equal(http.StatusCreated))
+		location := resp.Header.Get("Location")
+		ExpectWithOffset(1, location).To(HavePrefix(games.GamesResourcePath + "/"))
+
+		return location
+	}
+
+	It("creates, reads, starts and deletes games located by their route", func() {
+		location := create()
+		resp, body := call(http.MethodGet, location, alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var game engine.Game
+		decode(body, &game)
+		Expect(games.GamesResourcePath + "/" + game.ID).To(Equal(location))
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusCreated))
+
+		resp, body = call(http.MethodPost, location+"/start", alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		decode(body, &game)
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		resp, _ = call(http.MethodPost, location+"/start", alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+
+		resp, _ = call(http.MethodDelete, location, alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		resp, _ = call(http.MethodGet, location, alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("flags fields and takes the flags off", func() {
+		gameID := createGame(alice)
+		location := games.GamesResourcePath + "/" + gameID
+		mine := findField(gameID, true)
+		flag := requests.FlagInput{Row: mine.Row, Col: mine.Col}
+
+		resp, body := call(http.MethodPost, location+"/flags", alice, flag)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var delta engine.Delta
+		decode(body, &delta)
+		Expect(delta.Cells).To(HaveLen(1))
+		Expect(delta.Cells[0].Flagged).To(BeTrue())
+
+		resp, body = call(http.MethodDelete, location+"/flags", alice, flag)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		delta = engine.Delta{}
+		decode(body, &delta)
+		Expect(delta.Cells).To(Equal([]engine.CellDelta{{Row: mine.Row, Col: mine.Col}}))
+		Expect(delta.Move.Action).To(Equal("unflag"))
+		game, err := gameEngine.GetGame(gameID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.MineField[mine.Row][mine.Col].Flagged).To(BeFalse())
+		Expect(game.MineField[mine.Row][mine.Col].Clicked).To(BeFalse())
+
+		// The field can be revealed again, losing the game
+		resp, body = call(http.MethodPost, location+"/reveals", alice, requests.RevealInput{Row: mine.Row, Col: mine.Col})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		delta = engine.Delta{}
+		decode(body, &delta)
+		Expect(delta.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+		resp, _ = call(http.MethodDelete, location+"/flags", alice, flag)
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+	})
+
+	It("answers moves off the board and missing games", func() {
+		location := games.GamesResourcePath + "/" + createGame(alice)
+		resp, _ := call(http.MethodPost, location+"/reveals", alice, requests.RevealInput{Row: 9, Col: 0})
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		resp, _ = call(http.MethodDelete, location+"/flags", alice, requests.FlagInput{Row: -1, Col: 0})
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		missing := games.GamesResourcePath + "/missing"
+		resp, _ = call(http.MethodGet, missing, alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		resp, _ = call(http.MethodDelete, missing+"/flags", alice, requests.FlagInput{})
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("hands each request the params of its own route", func() {
+		locations := []string{create(), create(), create(), create()}
+		var wg sync.WaitGroup
+		for i := 0; i < 40; i++ {
+			location := locations[i%len(locations)]
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				resp, body := call(http.MethodGet, location, alice, nil)
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var game engine.Game
+				decode(body, &game)
+				Expect(games.GamesResourcePath + "/" + game.ID).To(Equal(location))
+			}()
+		}
+		wg.Wait()
+	})
+})
@@ -0,0 +1,101 @@
+package api_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/config"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	server "github.com/cmelgarejo/minesweeper-svc/web"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/loopcontext/msgcat"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var _ = Describe("Shutdown", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "shutdown")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	openDB := func() *gorm.DB {
+		gdb, err := gorm.Open(sqlite.Open(filepath.Join(dir, "games.db")),
+			&gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+		Expect(err).NotTo(HaveOccurred())
+
+		return gdb
+	}
+
+	It("stops serving, then stores the games held in memory and closes the db", func() {
+		gdb := openDB()
+		Expect(migrations.RunMigrations(gdb)).To(Succeed())
+		log := logger.New(false)
+		shutdownDB := &database.DB{Logger: log, DB: gdb}
+		svc := service.MineSweeperGameSvcImpl{}
+		engineSvc := svc.NewMineSweeperSvc(log, service.Config{WriteBehind: true}, repo.NewGameRepoSvc(shutdownDB))
+		catalog, err := msgcat.NewMessageCatalog(msgcat.Config{ResourcePath: "../../../resources/messages"})
+		Expect(err).NotTo(HaveOccurred())
+		cfg := &config.Config{Server: config.Server{ResponseContentType: common.AppTypeJSON, IdleTimeout: time.Second,
+			IdempotencyRetention: config.DefaultIdempotencyRetention, IdempotencyLease: config.DefaultIdempotencyLease}}
+		shutdownApp, err := server.InitFiberServer(context.Background(), cfg, log, &catalog, shutdownDB, engineSvc)
+		Expect(err).NotTo(HaveOccurred())
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			_ = shutdownApp.Listener(ln)
+		}()
+		url := "http://" + ln.Addr().String() + "/ping"
+		Eventually(func() error {
+			_, err := http.Get(url)
+			return err
+		}).Should(Succeed())
+
+		// A game played in batches only gets stored by the flushes
+		game, err := engineSvc.CreateGame(9, 9, 10, engine.Rules{Practice: true}, "alice")
+		Expect(err).NotTo(HaveOccurred())
+		gameStore := &models.Game{Rows: game.Rows, Cols: game.Cols, Mines: game.Mines}
+		gameStore.ID = game.ID
+		gameStore.UpdateGameState(game)
+		_, err = repo.NewGameRepoSvc(shutdownDB).UpsertGame(context.Background(), nil, gameStore)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = engineSvc.StartGame(context.Background(), game.ID)
+		Expect(err).NotTo(HaveOccurred())
+
+		server.Shutdown(log, time.Second, shutdownApp, nil, engineSvc, shutdownDB)
+		_, err = http.Get(url)
+		Expect(err).To(HaveOccurred())
+		sqlDB, err := gdb.DB()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sqlDB.Ping()).NotTo(Succeed())
+
+		stored := &models.Game{}
+		reopened := openDB()
+		Expect(reopened.First(stored, "id = ?", game.ID).Error).To(Succeed())
+		Expect(stored.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		reopenedDB, err := reopened.DB()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reopenedDB.Close()).To(Succeed())
+	})
+})
@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/fasthttp/websocket"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game sockets", func() {
+	var alice, bob string
+
+	BeforeEach(func() {
+		alice, bob = signUp("socketeer"), signUp("watcher")
+	})
+
+	// dial opens the socket of a game with the API key in the query, the way browsers do
+	dial := func(gameID, apiKey string) *websocket.Conn {
+		u := url.URL{Scheme: "ws", Host: appAddr, Path: "/v2/api/games/" + gameID + "/ws",
+			RawQuery: url.Values{"apiKey": {apiKey}}.Encode()}
+		conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		return conn
+	}
+
+	// next reads the next event of the socket of the given type
+	next := func(conn *websocket.Conn, eventType engine.EventType) engine.Event {
+		Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).To(Succeed())
+		for {
+			var e engine.Event
+			Expect(conn.ReadJSON(&e)).To(Succeed())
+			if e.Type == eventType {
+				return e
+			}
+		}
+	}
+
+	It("plays the moves sent and answers them with their events", func() {
+		gameID := createGame(alice)
+		conn := dial(gameID, alice)
+		defer conn.Close()
+		Expect(next(conn, engine.EventPlayerJoined).Player).To(ContainSubstring("socketeer"))
+		pos := findField(gameID, false)
+		Expect(conn.WriteJSON(requests.GameCommand{Type: requests.GameCommandClick,
+			GameInput: requests.GameInput{Row: pos.Row, Col: pos.Col}})).To(Succeed())
+		e := next(conn, engine.EventCellRevealed)
+		Expect(e.GameID).To(Equal(gameID))
+		Expect(e.Cells).NotTo(BeEmpty())
+		Expect(e.Cells[0].ClickedBy).To(ContainSubstring("socketeer"))
+	})
+
+	It("hides the counts of the fields other players revealed out of sight in fog games", func() {
+		resp, body := call(http.MethodPost, "/v1/api/games", alice, requests.GameCreateInput{Rows: 9, Cols: 9,
+			Mines: 10, Fog: &requests.Fog{Radius: 1, Memory: 1}})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		var gameID string
+		decode(body, &gameID)
+		resp, _ = call(http.MethodPost, "/v1/api/games/start/"+gameID, alice, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		aliceConn, bobConn := dial(gameID, alice), dial(gameID, bob)
+		defer aliceConn.Close()
+		defer bobConn.Close()
+		next(bobConn, engine.EventPlayerJoined)
+		pos := findField(gameID, false)
+		resp, _ = call(http.MethodPatch, "/v1/api/games/"+gameID, alice, requests.GameInput{Row: pos.Row, Col: pos.Col})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		for _, cell := range next(aliceConn, engine.EventCellRevealed).Cells {
+			Expect(cell.Fogged).To(BeFalse())
+		}
+		for _, cell := range next(bobConn, engine.EventCellRevealed).Cells {
+			Expect(cell.Fogged).To(BeTrue())
+			Expect(cell.AdjMines).To(BeZero())
+		}
+	})
+
+	It("refuses sockets without a valid API key", func() {
+		gameID := createGame(alice)
+		u := url.URL{Scheme: "ws", Host: appAddr, Path: "/v2/api/games/" + gameID + "/ws",
+			RawQuery: url.Values{"apiKey": {"nope"}}.Encode()}
+		_, resp, err := websocket.DefaultDialer.Dial(u.String(), nil)
+		Expect(err).To(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("takes the API key from the query only on the sockets and the event streams", func() {
+		gameID := createGame(alice)
+		resp, _ := call(http.MethodGet, "/v1/api/games/"+gameID+"?apiKey="+alice, "", nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+})
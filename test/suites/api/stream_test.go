@@ -0,0 +1,92 @@
+package api_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/handlers/games"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game event streams", func() {
+	var apiKey, gameID string
+
+	BeforeEach(func() {
+		apiKey = signUp("streamer")
+		gameID = createGame(apiKey)
+		// Flagging a corner leaves the game as it is but for the flag
+		resp, _ := call(http.MethodPatch, "/v1/api/games/"+gameID, apiKey,
+			requests.GameInput{ClickType: "flag"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	// stream opens the event stream of the game, resuming after lastEventID unless it is empty
+	stream := func(lastEventID string) (*http.Response, *bufio.Reader) {
+		req, err := http.NewRequest(http.MethodGet, "http://"+appAddr+"/v1/api/games/"+gameID+
+			"/events?apiKey="+apiKey, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if lastEventID != "" {
+			req.Header.Set(games.HeaderLastEventID, lastEventID)
+		}
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(HavePrefix("text/event-stream"))
+
+		return resp, bufio.NewReader(resp.Body)
+	}
+
+	// next reads the next event of the stream, skipping the keep-alive comments
+	next := func(r *bufio.Reader) engine.Event {
+		var id string
+		for {
+			line, err := r.ReadString('\n')
+			Expect(err).NotTo(HaveOccurred())
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				var e engine.Event
+				Expect(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e)).To(Succeed())
+				Expect(id).To(Equal(strconv.FormatUint(e.Seq, 10)))
+				return e
+			}
+		}
+	}
+
+	It("replays the events published after the last one received", func() {
+		resp, r := stream("1")
+		defer resp.Body.Close()
+		e := next(r)
+		Expect(e.Seq).To(BeEquivalentTo(2))
+		Expect(e.Type).To(Equal(engine.EventFlagChanged))
+		Expect(e.GameID).To(Equal(gameID))
+		// The live events follow the missed ones, starting with the player joining
+		Expect(next(r).Type).To(Equal(engine.EventPlayerJoined))
+	})
+
+	It("tells the subscribers resuming after unknown events to read the game again", func() {
+		resp, r := stream("1000")
+		defer resp.Body.Close()
+		Expect(next(r).Type).To(Equal(engine.EventResync))
+	})
+
+	It("refuses last event ids that are not numbers", func() {
+		req, err := http.NewRequest(http.MethodGet, "http://"+appAddr+"/v1/api/games/"+gameID+
+			"/events?lastEventId=last&apiKey="+apiKey, nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/config"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	server "github.com/cmelgarejo/minesweeper-svc/web"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/gofiber/adaptor/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/loopcontext/msgcat"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Client Suite")
+}
+
+var (
+	app        *fiber.App
+	gameEngine service.MineSweeperGameSvc
+	// apiServer serves the Fiber app through net/http
+	apiServer *httptest.Server
+	// streamURL is where the Fiber app listens itself, the event streams are only written by fasthttp
+	streamURL string
+)
+
+var _ = BeforeSuite(func() {
+	gdb, err := gorm.Open(sqlite.Open("file:client_suite?mode=memory&cache=shared"),
+		&gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	Expect(err).NotTo(HaveOccurred())
+	// Connections to a shared in-memory db lock each other out of its tables instead of waiting
+	sqlDB, err := gdb.DB()
+	Expect(err).NotTo(HaveOccurred())
+	sqlDB.SetMaxOpenConns(1)
+	Expect(migrations.RunMigrations(gdb)).To(Succeed())
+	log := logger.New(false)
+	db := &database.DB{Logger: log, DB: gdb}
+	catalog, err := msgcat.NewMessageCatalog(msgcat.Config{ResourcePath: "../../../resources/messages"})
+	Expect(err).NotTo(HaveOccurred())
+	cfg := &config.Config{Server: config.Server{ResponseContentType: common.AppTypeJSON, IdleTimeout: time.Second,
+		IdempotencyRetention: config.DefaultIdempotencyRetention, IdempotencyLease: config.DefaultIdempotencyLease}}
+	gamesSvc := service.MineSweeperGameSvcImpl{}
+	gameEngine = gamesSvc.NewMineSweeperSvc(log, service.Config{}, repo.NewGameRepoSvc(db))
+	app, err = server.InitFiberServer(context.Background(), cfg, log, &catalog, db, gameEngine)
+	Expect(err).NotTo(HaveOccurred())
+	apiServer = httptest.NewServer(adaptor.FiberApp(app))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	streamURL = "http://" + listener.Addr().String()
+	go func() {
+		defer GinkgoRecover()
+		Expect(app.Listener(listener)).To(Succeed())
+	}()
+})
+
+var _ = AfterSuite(func() {
+	if apiServer != nil {
+		apiServer.Close()
+	}
+	if app != nil {
+		gameEngine.CloseSubscriptions()
+		Expect(app.Shutdown()).To(Succeed())
+	}
+})
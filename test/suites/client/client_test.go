@@ -0,0 +1,239 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/client"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// droppingTransport drops the response of the first request to the path after the server handled it, like a
+// network failing on the way back
+type droppingTransport struct {
+	path            string
+	mu              sync.Mutex
+	dropped         bool
+	idempotencyKeys []string
+	replayed        bool
+}
+
+func (t *droppingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || req.URL.Path != t.path {
+		return resp, err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.idempotencyKeys = append(t.idempotencyKeys, req.Header.Get(client.HeaderIdempotencyKey))
+	t.replayed = resp.Header.Get(client.HeaderIdempotentReplayed) == "true"
+	if !t.dropped {
+		t.dropped = true
+		resp.Body.Close()
+		return nil, errors.New("connection reset by peer")
+	}
+
+	return resp, nil
+}
+
+// signUp creates a user and signs it in with a new client
+func signUp(ctx context.Context, baseURL, username string) client.Client {
+	c := client.NewClientSvc(client.Config{BaseURL: baseURL, RetryBackoff: time.Millisecond})
+	_, err := c.CreateUser(ctx, client.UserInput{
+		Credentials: client.Credentials{Username: username, Password: username},
+		Email:       username + "@minesweeper.svc",
+		Fullname:    "Player " + username,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	_, err = c.SignIn(ctx, client.Credentials{Username: username, Password: username})
+	Expect(err).NotTo(HaveOccurred())
+
+	return c
+}
+
+// flagged tells whether the delta has the field flagged
+func flagged(delta *client.Delta, row, col int) bool {
+	for _, cell := range delta.Cells {
+		if cell.Row == row && cell.Col == col {
+			return cell.Flagged
+		}
+	}
+
+	return false
+}
+
+var _ = Describe("Client", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Describe("users", func() {
+		It("signs up, signs in and reads the user", func() {
+			c := signUp(ctx, apiServer.URL, "alice")
+			Expect(c.APIKey()).NotTo(BeEmpty())
+			me, err := c.Me(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(me.Username).To(Equal("alice"))
+			user, err := c.GetUser(ctx, me.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Email).To(Equal("alice@minesweeper.svc"))
+			user, err = c.UpdateUser(ctx, me.ID, client.UserInput{Fullname: "Alice"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(user.Fullname).To(Equal("Alice"))
+		})
+
+		It("tells the errors by their catalog codes", func() {
+			c := client.NewClientSvc(client.Config{BaseURL: apiServer.URL, APIKey: "not-a-key"})
+			_, err := c.Me(ctx)
+			Expect(errors.Is(err, client.ErrUnauthorized)).To(BeTrue())
+			var apiErr *client.Error
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.StatusCode).To(Equal(http.StatusUnauthorized))
+			_, err = c.SignIn(ctx, client.Credentials{Username: "alice", Password: "wrong"})
+			Expect(errors.Is(err, client.ErrUnauthorized)).To(BeTrue())
+		})
+	})
+
+	Describe("games", func() {
+		var c client.Client
+
+		BeforeEach(func() {
+			c = client.NewClientSvc(client.Config{BaseURL: apiServer.URL, APIKey: migrations.AdminApiKey})
+		})
+
+		It("creates, starts and plays a game", func() {
+			game, err := c.CreateGame(ctx, client.GameCreateInput{Rows: 5, Cols: 5, Mines: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(game.Status).To(Equal(client.GameStatusCreated))
+			game, err = c.StartGame(ctx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(game.Status).To(Equal(client.GameStatusStarted))
+			delta, err := c.Flag(ctx, game.ID, client.FlagInput{Row: 0, Col: 0})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flagged(delta, 0, 0)).To(BeTrue())
+			delta, err = c.Unflag(ctx, game.ID, client.FlagInput{Row: 0, Col: 0})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(delta.Cells).To(HaveLen(1))
+			Expect(flagged(delta, 0, 0)).To(BeFalse())
+			delta, err = c.Flag(ctx, game.ID, client.FlagInput{Row: 0, Col: 0})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flagged(delta, 0, 0)).To(BeTrue())
+			delta, err = c.Click(ctx, game.ID, client.GameInput{Row: 0, Col: 1, ClickType: "flag"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(flagged(delta, 0, 1)).To(BeTrue())
+			_, err = c.Reveal(ctx, game.ID, client.RevealInput{Row: 4, Col: 4})
+			Expect(err).NotTo(HaveOccurred())
+			read, err := c.GetGame(ctx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read.Version).To(Equal(delta.Version + 1))
+			games, err := c.ListGames(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(games).To(HaveKey(game.ID))
+			mine, err := c.MyGames(ctx, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mine).NotTo(BeEmpty())
+			Expect(mine[0].ID).To(Equal(game.ID))
+		})
+
+		It("plays batches of moves", func() {
+			game, err := c.CreateGame(ctx, client.GameCreateInput{Rows: 5, Cols: 5, Mines: 3})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.StartGame(ctx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			result, err := c.PlayMoves(ctx, game.ID, client.GameMovesInput{Moves: []client.GameInput{
+				{Row: 0, Col: 0, ClickType: "flag"},
+				{Row: 1, Col: 1, ClickType: "flag"},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Moves).To(HaveLen(2))
+			_, err = c.PlayMoves(ctx, game.ID, client.GameMovesInput{})
+			Expect(errors.Is(err, client.ErrInvalidMoves)).To(BeTrue())
+		})
+
+		It("answers typed errors", func() {
+			_, err := c.CreateGame(ctx, client.GameCreateInput{Rows: 5, Cols: 5, Mines: 3, Kernel: "queen"})
+			Expect(errors.Is(err, client.ErrInvalidGameRules)).To(BeTrue())
+			_, err = c.GetGame(ctx, "missing")
+			Expect(errors.Is(err, client.ErrNotFound)).To(BeTrue())
+			game, err := c.CreateGame(ctx, client.GameCreateInput{Rows: 5, Cols: 5, Mines: 3})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.StartGame(ctx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.StartGame(ctx, game.ID)
+			var apiErr *client.Error
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.StatusCode).To(Equal(http.StatusConflict))
+			Expect(c.DeleteGame(ctx, game.ID)).To(Succeed())
+			player := signUp(ctx, apiServer.URL, "bob")
+			_, err = player.ListGames(ctx)
+			Expect(errors.Is(err, client.ErrUnauthorized)).To(BeTrue())
+		})
+
+		It("retries writes with the same idempotency key, getting the response of the first attempt", func() {
+			transport := &droppingTransport{path: "/v2/api/games"}
+			player := signUp(ctx, apiServer.URL, "carol")
+			flaky := client.NewClientSvc(client.Config{
+				BaseURL:      apiServer.URL,
+				APIKey:       player.APIKey(),
+				HTTPClient:   &http.Client{Transport: transport},
+				RetryBackoff: time.Millisecond,
+			})
+			game, err := flaky.CreateGame(ctx, client.GameCreateInput{Rows: 5, Cols: 5, Mines: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport.idempotencyKeys).To(HaveLen(2))
+			Expect(transport.idempotencyKeys[0]).NotTo(BeEmpty())
+			Expect(transport.idempotencyKeys[1]).To(Equal(transport.idempotencyKeys[0]))
+			Expect(transport.replayed).To(BeTrue())
+			games, err := player.MyGames(ctx, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(games).To(HaveLen(1))
+			Expect(games[0].ID).To(Equal(game.ID))
+		})
+	})
+
+	Describe("events", func() {
+		It("streams the events of a game and resumes after the last one read", func() {
+			c := client.NewClientSvc(client.Config{BaseURL: streamURL, APIKey: migrations.AdminApiKey})
+			game, err := c.CreateGame(ctx, client.GameCreateInput{Rows: 5, Cols: 5, Mines: 3})
+			Expect(err).NotTo(HaveOccurred())
+			streamCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			stream, err := c.Events(streamCtx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.StartGame(ctx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = c.Flag(ctx, game.ID, client.FlagInput{Row: 2, Col: 2})
+			Expect(err).NotTo(HaveOccurred())
+			var types []client.EventType
+			for len(types) == 0 || types[len(types)-1] != client.EventFlagChanged {
+				e, err := stream.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(e.GameID).To(Equal(game.ID))
+				types = append(types, e.Type)
+			}
+			Expect(types).To(ContainElement(client.EventGameStarted))
+			lastSeq := stream.LastSeq()
+			Expect(stream.Close()).To(Succeed())
+
+			_, err = c.Flag(ctx, game.ID, client.FlagInput{Row: 3, Col: 3})
+			Expect(err).NotTo(HaveOccurred())
+			stream, err = c.ResumeEvents(streamCtx, game.ID, lastSeq)
+			Expect(err).NotTo(HaveOccurred())
+			defer stream.Close()
+			var missed []client.Event
+			for len(missed) == 0 || missed[len(missed)-1].Type != client.EventFlagChanged {
+				e, err := stream.Next()
+				Expect(err).NotTo(HaveOccurred())
+				missed = append(missed, *e)
+			}
+			Expect(missed[0].Seq).To(BeNumerically(">", lastSeq))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package engine_test
+
+import (
+	"encoding/base64"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Board codes", func() {
+	It("creates the same board out of the code of a game", func() {
+		rules := engine.Rules{Kernel: engine.KernelKnight, Arcade: true, Fog: engine.NewFogRules(2, 3)}
+		game := engine.NewGame(9, 12, 20, rules, "alice")
+		copied, err := engine.NewGameFromCode(game.BoardCode(), "bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copied.ID).NotTo(Equal(game.ID))
+		Expect(copied.CreatedBy).To(Equal("bob"))
+		Expect(copied.Status).To(BeEquivalentTo(engine.GameStatusCreated))
+		Expect(copied.IsEndless()).To(BeFalse())
+		Expect(copied.Rules.Kernel).To(Equal(rules.Kernel))
+		Expect(copied.Rules.Arcade).To(BeTrue())
+		Expect(copied.Rules.Fog).To(Equal(rules.Fog))
+		Expect(copied.Seed).To(Equal(game.Seed))
+		Expect(copied.MineField).To(Equal(game.MineField))
+		Expect(copied.Difficulty).To(Equal(game.Difficulty))
+	})
+
+	It("keeps the seed of the board found for a tier", func() {
+		game := engine.NewGame(9, 9, 10, engine.Rules{Tier: "beginner"}, "alice")
+		copied, err := engine.NewGameFromCode(game.BoardCode(), "bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copied.MineField).To(Equal(game.MineField))
+	})
+
+	It("leaves the moves played out of the code", func() {
+		game := engine.NewGame(9, 9, 10, engine.Rules{}, "alice")
+		code := game.BoardCode()
+		Expect(game.Start()).To(Succeed())
+		_, err := game.Click("alice", engine.GameClickTypeFlag, 4, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.BoardCode()).To(Equal(code))
+	})
+
+	It("creates endless boards out of their seed", func() {
+		game := engine.NewGame(0, 0, 30, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		copied, err := engine.NewGameFromCode(game.BoardCode(), "bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copied.IsEndless()).To(BeTrue())
+		Expect(copied.Seed).To(Equal(game.Seed))
+		Expect(copied.Mines).To(Equal(30))
+	})
+
+	It("has no code for boards stored without a seed", func() {
+		game := newBoard(5, 5, engine.Rules{}, at(0, 0))
+		Expect(game.BoardCode()).To(BeEmpty())
+	})
+
+	It("refuses codes that are not board codes", func() {
+		for _, code := range []string{"", "not a code!", base64.RawURLEncoding.EncodeToString([]byte{9, 0, 0, 0})} {
+			_, err := engine.NewGameFromCode(code, "bob")
+			Expect(err).To(MatchError(engine.ErrInvalidBoardCode), "code %q", code)
+		}
+	})
+
+	It("refuses codes of another version of the generator", func() {
+		code := engine.NewGame(9, 9, 10, engine.Rules{}, "alice").BoardCode()
+		data, err := base64.RawURLEncoding.DecodeString(code)
+		Expect(err).NotTo(HaveOccurred())
+		// The rows, cols and mines of the board take a byte each, the generator version follows them
+		Expect(data[7]).To(BeEquivalentTo(engine.BoardGeneratorVersion))
+		data[7]++
+		_, err = engine.NewGameFromCode(base64.RawURLEncoding.EncodeToString(data), "bob")
+		Expect(err).To(MatchError(engine.ErrGeneratorVersion))
+	})
+})
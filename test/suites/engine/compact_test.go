@@ -0,0 +1,85 @@
+package engine_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compact board", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = newBoard(6, 7, engine.Rules{Arcade: true}, at(0, 0), at(2, 3), at(5, 6))
+		game.MineField[4][4].Item = engine.ItemShield
+		for _, click := range []struct {
+			player    string
+			clickType engine.ClickType
+			pos       engine.Position
+		}{
+			{"alice", engine.GameClickTypeNormal, at(0, 1)},
+			{"bob", engine.GameClickTypeFlag, at(0, 0)},
+			{"bob", engine.GameClickTypeNormal, at(1, 0)},
+		} {
+			_, err := game.Click(click.player, click.clickType, click.pos.Row, click.pos.Col)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("decodes the board it encodes", func() {
+		mineField, err := engine.DecodeBoard(engine.EncodeBoard(game.MineField))
+		Expect(err).NotTo(HaveOccurred())
+		// Reveal times are kept to the millisecond
+		for i := range game.MineField {
+			for j := range game.MineField[i] {
+				if at := game.MineField[i][j].RevealedAt; at != nil {
+					truncated := at.Truncate(time.Millisecond)
+					game.MineField[i][j].RevealedAt = &truncated
+					Expect(mineField[i][j].RevealedAt).NotTo(BeNil())
+					Expect(mineField[i][j].RevealedAt.Equal(truncated)).To(BeTrue())
+					mineField[i][j].RevealedAt = &truncated
+				}
+			}
+		}
+		Expect(mineField).To(Equal(game.MineField))
+	})
+
+	It("round-trips the game through JSON", func() {
+		b, err := json.Marshal(game.Compact())
+		Expect(err).NotTo(HaveOccurred())
+		var compact engine.CompactGame
+		Expect(json.Unmarshal(b, &compact)).To(Succeed())
+		decoded, err := compact.Expand()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.Validate()).To(Succeed())
+		Expect(decoded.MineField[0][0].Flagged).To(BeTrue())
+		Expect(decoded.MineField[1][0].ClickedBy).To(Equal("bob"))
+		Expect(decoded.MineField[4][4].Item).To(Equal(engine.ItemShield))
+	})
+
+	It("refuses boards over the maximum dimensions", func() {
+		mineField := make([][]engine.Field, engine.GameMaxRows+1)
+		for i := range mineField {
+			mineField[i] = make([]engine.Field, 1)
+		}
+		_, err := engine.DecodeBoard(engine.EncodeBoard(mineField))
+		Expect(err).To(MatchError(engine.ErrCorruptedBoard))
+	})
+
+	It("refuses truncated boards", func() {
+		data := engine.EncodeBoard(game.MineField)
+		_, err := engine.DecodeBoard(data[:len(data)/2])
+		Expect(err).To(MatchError(engine.ErrCorruptedBoard))
+	})
+
+	It("creates boards within the maximum dimensions", func() {
+		big := engine.NewGame(engine.GameMaxRows*2, engine.GameMaxCols*2, 100, engine.Rules{}, "alice")
+		Expect(big.Rows).To(Equal(engine.GameMaxRows))
+		Expect(big.Cols).To(Equal(engine.GameMaxCols))
+		_, err := engine.DecodeBoard(engine.EncodeBoard(big.MineField))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,125 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Deltas", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = newBoard(5, 5, engine.Rules{}, at(0, 4), at(4, 4))
+	})
+
+	// positions are the positions of the cells of a delta
+	positions := func(cells []engine.CellDelta) (list []engine.Position) {
+		for _, cell := range cells {
+			list = append(list, at(cell.Row, cell.Col))
+		}
+
+		return list
+	}
+
+	It("tells every field the flood fill revealed and nothing else", func() {
+		delta, err := game.Click("alice", engine.GameClickTypeNormal, 2, 0)
+		Expect(err).NotTo(HaveOccurred())
+		var opened []engine.Position
+		for i := range game.MineField {
+			for j := range game.MineField[i] {
+				if revealed(game, i, j) {
+					opened = append(opened, at(i, j))
+				}
+			}
+		}
+		Expect(len(opened)).To(BeNumerically(">", 1))
+		Expect(positions(delta.Cells)).To(ConsistOf(opened))
+		for _, cell := range delta.Cells {
+			Expect(cell.Revealed).To(BeTrue())
+			Expect(cell.ClickedBy).To(Equal("alice"))
+			Expect(cell.AdjMines).To(Equal(game.MineField[cell.Row][cell.Col].AdjCount))
+		}
+		Expect(delta.Version).To(Equal(game.Version))
+	})
+
+	It("tells only the flagged field of a flag, and no field of a move that changed none", func() {
+		delta, err := game.Click("alice", engine.GameClickTypeFlag, 0, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(Equal([]engine.CellDelta{{Row: 0, Col: 4, Flagged: true, ClickedBy: "alice"}}))
+		delta, err = game.Click("alice", engine.GameClickTypeNormal, 0, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(BeEmpty())
+		Expect(delta.Move.Row).To(Equal(0))
+	})
+
+	It("takes flags off, leaving the field to be revealed", func() {
+		_, err := game.Click("alice", engine.GameClickTypeFlag, 4, 3)
+		Expect(err).NotTo(HaveOccurred())
+		delta, err := game.Click("bob", engine.GameClickTypeUnflag, 4, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(Equal([]engine.CellDelta{{Row: 4, Col: 3}}))
+		Expect(delta.Move.Action).To(Equal("unflag"))
+		Expect(delta.Events("bob")[0].Type).To(Equal(engine.EventFlagChanged))
+		Expect(game.MineField[4][3].Clicked).To(BeFalse())
+		Expect(game.MineField[4][3].ClickedBy).To(BeEmpty())
+
+		delta, err = game.Click("bob", engine.GameClickTypeUnflag, 4, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(BeEmpty())
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 4, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revealed(game, 4, 3)).To(BeTrue())
+		delta, err = game.Click("alice", engine.GameClickTypeUnflag, 4, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(BeEmpty())
+		Expect(revealed(game, 4, 3)).To(BeTrue())
+	})
+
+	It("tells the mine and the status of a losing move", func() {
+		delta, err := game.Click("alice", engine.GameClickTypeNormal, 4, 4)
+		Expect(err).To(MatchError(engine.ErrDefeat))
+		Expect(delta.Cells).To(Equal([]engine.CellDelta{{Row: 4, Col: 4, Revealed: true, Mine: true,
+			AdjMines: game.MineField[4][4].AdjCount, ClickedBy: "alice"}}))
+		Expect(delta.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+		Expect(delta.PreviousStatus).To(BeEquivalentTo(engine.GameStatusStarted))
+		Expect(delta.Move.Result).To(Equal(engine.MoveResultDefeat))
+	})
+
+	It("keeps its move when the history changes afterwards", func() {
+		delta, err := game.Click("alice", engine.GameClickTypeFlag, 0, 4)
+		Expect(err).NotTo(HaveOccurred())
+		game.History[0].Player = "mallory"
+		_, err = game.Click("bob", engine.GameClickTypeFlag, 4, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Move.Player).To(Equal("alice"))
+		Expect(delta.Move.Seq).To(Equal(1))
+	})
+
+	It("tells the fields a forfeit revealed", func() {
+		_, err := game.Click("alice", engine.GameClickTypeFlag, 0, 4)
+		Expect(err).NotTo(HaveOccurred())
+		delta, err := game.Forfeit()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(HaveLen(23))
+		Expect(delta.Status).To(BeEquivalentTo(engine.GameStatusForfeited))
+		Expect(delta.PreviousStatus).To(BeEquivalentTo(engine.GameStatusStarted))
+	})
+
+	It("tells the fields revealed in endless boards", func() {
+		endless := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		endless.Seed = 42
+		Expect(endless.Start()).To(Succeed())
+		delta, err := endless.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(HaveLen(endless.Endless.Score))
+		Expect(delta.Cells).To(ConsistOf(endless.Cells("alice")))
+
+		_, err = endless.Click("alice", engine.GameClickTypeFlag, -40, -40)
+		Expect(err).NotTo(HaveOccurred())
+		delta, err = endless.Click("alice", engine.GameClickTypeUnflag, -40, -40)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(Equal([]engine.CellDelta{{Row: -40, Col: -40}}))
+		Expect(positions(endless.Cells("alice"))).NotTo(ContainElement(at(-40, -40)))
+	})
+})
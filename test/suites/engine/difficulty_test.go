@@ -0,0 +1,69 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Difficulty", func() {
+	It("parses the tier names, an empty one is no tier", func() {
+		tier, err := engine.ParseTier("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tier).To(BeEmpty())
+		tier, err = engine.ParseTier("expert")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tier).To(Equal("expert"))
+		_, err = engine.ParseTier("impossible")
+		Expect(err).To(MatchError(engine.ErrUnknownTier))
+	})
+
+	It("rates a board cleared by a single opening", func() {
+		game := newBoard(5, 5, engine.Rules{}, at(0, 0))
+		difficulty := game.Rate()
+		Expect(difficulty.BBBV).To(Equal(1))
+		Expect(difficulty.Openings).To(Equal(1))
+		Expect(difficulty.Unsolved).To(BeZero())
+		Expect(difficulty.Guesses).To(BeZero())
+		Expect(difficulty.Tier).To(Equal("beginner"))
+	})
+
+	It("guesses when stuck and carries on, counting the guesses", func() {
+		// The opening leaves a 50/50 between [0, 3] and [1, 3]
+		for _, mine := range []engine.Position{at(0, 3), at(1, 3)} {
+			difficulty := newBoard(2, 4, engine.Rules{}, mine).Rate()
+			Expect(difficulty.Unsolved).To(Equal(1), "mine at %v", mine)
+			Expect(difficulty.Guesses).To(Equal(1), "mine at %v", mine)
+		}
+		solved := newBoard(2, 4, engine.Rules{}, at(0, 3), at(1, 3)).Rate()
+		Expect(solved.Unsolved).To(BeZero())
+		Expect(solved.Guesses).To(BeZero())
+		Expect(newBoard(2, 4, engine.Rules{}, at(0, 3)).Rate().Rating).To(BeNumerically(">", solved.Rating))
+	})
+
+	It("counts each guess of a board with many forced guesses", func() {
+		// Columns 3 and 6 are a 50/50 each, and nothing tells where the mine of the second one is before the first
+		// is solved
+		difficulty := newBoard(2, 8, engine.Rules{}, at(0, 3), at(1, 6)).Rate()
+		Expect(difficulty.Unsolved).To(Equal(8))
+		Expect(difficulty.Guesses).To(BeNumerically(">=", 2))
+		Expect(difficulty.Rating).To(BeNumerically(">", newBoard(2, 4, engine.Rules{}, at(0, 3)).Rate().Rating))
+	})
+
+	It("doesn't rate endless boards", func() {
+		game := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		Expect(game.Rate()).To(BeNil())
+	})
+
+	It("generates boards in the requested tier", func() {
+		game := engine.NewGame(9, 9, 10, engine.Rules{Tier: "beginner"}, "alice")
+		Expect(game.Difficulty.Tier).To(Equal("beginner"))
+	})
+
+	It("gives up on tiers out of reach of the board size, keeping the last board rated", func() {
+		game := engine.NewGame(9, 9, 10, engine.Rules{Tier: "master"}, "alice")
+		Expect(game.Difficulty).NotTo(BeNil())
+		Expect(game.Difficulty.Tier).NotTo(Equal("master"))
+		Expect(game.Difficulty).To(Equal(game.Rate()))
+	})
+})
@@ -0,0 +1,109 @@
+package engine_test
+
+import (
+	"sync"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endless boards", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		game.Seed = 42
+		Expect(game.Start()).To(Succeed())
+	})
+
+	// mineNear finds a mine of the board of the game around [row, col], looking at it as a finished game does
+	mineNear := func(row, col int) engine.Position {
+		finished := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "bob")
+		finished.Seed = game.Seed
+		finished.Status = engine.GameStatusDefeat
+		view := finished.Viewport("bob", row, col, 10, 10)
+		for i := range view.Cells {
+			for j, cell := range view.Cells[i] {
+				if cell.State == engine.CellStateMine {
+					return at(row+i, col+j)
+				}
+			}
+		}
+		Fail("no mine found")
+
+		return engine.Position{}
+	}
+
+	It("is created without a mine field, with the default chunk mines", func() {
+		Expect(game.IsEndless()).To(BeTrue())
+		Expect(game.MineField).To(BeNil())
+		Expect(game.Endless.ChunkSize).To(Equal(engine.EndlessChunkSize))
+		Expect(game.Mines).To(Equal(engine.EndlessDefaultChunkMines))
+		Expect(game.Rate()).To(BeNil())
+	})
+
+	It("keeps the fields around the origin safe", func() {
+		delta, err := game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).NotTo(BeEmpty())
+		Expect(game.Endless.Score).To(BeNumerically(">", 0))
+		Expect(game.Endless.Score).To(BeNumerically("<=", 4096))
+	})
+
+	It("plays any coordinate, storing only the chunks touched", func() {
+		_, err := game.Click("alice", engine.GameClickTypeFlag, -100, 250)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Endless.Chunks).To(HaveLen(1))
+		Expect(game.Endless.Chunks).To(HaveKey("-7:15"))
+		Expect(game.Viewport("alice", -100, 250, 1, 1).Cells[0][0].State).To(Equal(engine.CellStateFlagged))
+		Expect(game.Validate()).To(Succeed())
+	})
+
+	It("generates the same board out of the same seed", func() {
+		other := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "bob")
+		other.Seed = game.Seed
+		Expect(other.Start()).To(Succeed())
+		for _, g := range []*engine.Game{game, other} {
+			_, err := g.Click(g.CreatedBy, engine.GameClickTypeNormal, 0, 0)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(other.Endless.Score).To(Equal(game.Endless.Score))
+		Expect(other.Viewport("bob", -10, -10, 20, 20)).To(Equal(game.Viewport("alice", -10, -10, 20, 20)))
+	})
+
+	It("is lost on a mine", func() {
+		mine := mineNear(40, 40)
+		_, err := game.Click("alice", engine.GameClickTypeNormal, mine.Row, mine.Col)
+		Expect(err).To(MatchError(engine.ErrDefeat))
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+		Expect(game.Code).NotTo(BeEmpty())
+	})
+
+	It("reads the board from many goroutines at once without writing to it", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		game.Status = engine.GameStatusDefeat
+		chunks := len(game.Endless.Chunks)
+		var wg sync.WaitGroup
+		views := make([]*engine.Viewport, 8)
+		for i := range views {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				views[i] = game.Viewport("alice", -40, -40, 80, 80)
+			}(i)
+		}
+		wg.Wait()
+		want := game.Viewport("alice", -40, -40, 80, 80)
+		for _, view := range views {
+			Expect(view).To(Equal(want))
+		}
+		Expect(game.Endless.Chunks).To(HaveLen(chunks))
+	})
+
+	It("refuses the click types it doesn't support", func() {
+		_, err := game.Click("alice", engine.GameClickTypeReveal, 0, 0)
+		Expect(err).To(MatchError(engine.ErrUnsupportedClick))
+	})
+})
@@ -0,0 +1,53 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEngine(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Engine Suite")
+}
+
+// newBoard builds a started classic game with the mines at the given positions, the counts of the fields
+// are the ones of the kernel of the rules
+func newBoard(rows, cols int, rules engine.Rules, mines ...engine.Position) *engine.Game {
+	if rules.Kernel == "" {
+		rules.Kernel = engine.KernelMoore
+	}
+	game := &engine.Game{
+		ID:            "game",
+		SchemaVersion: engine.StateSchemaVersion,
+		Rows:          rows,
+		Cols:          cols,
+		Rules:         rules,
+		Status:        engine.GameStatusStarted,
+		MineField:     make([][]engine.Field, rows),
+	}
+	for i := range game.MineField {
+		game.MineField[i] = make([]engine.Field, cols)
+	}
+	for _, mine := range mines {
+		game.MineField[mine.Row][mine.Col].Mine = true
+	}
+	game.Repair()
+	Expect(game.Validate()).To(Succeed())
+
+	return game
+}
+
+// revealed tells whether the field was revealed, flagged fields are not
+func revealed(game *engine.Game, row, col int) bool {
+	field := game.MineField[row][col]
+
+	return field.Clicked && !field.Flagged
+}
+
+// at is the position of the field at [row, col]
+func at(row, col int) engine.Position {
+	return engine.Position{Row: row, Col: col}
+}
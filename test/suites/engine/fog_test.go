@@ -0,0 +1,62 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fog", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		// Every safe field borders a mine, so no click opens more than its field
+		game = newBoard(3, 10, engine.Rules{Fog: engine.NewFogRules(1, 1)},
+			at(1, 0), at(1, 2), at(1, 4), at(1, 6), at(1, 8))
+	})
+
+	state := func(player string, row, col int) string {
+		return game.Project(player).Board.Cells[row][col].State
+	}
+
+	It("fills in the default radius and memory", func() {
+		Expect(engine.NewFogRules(0, 0)).To(Equal(&engine.FogRules{
+			Radius: engine.FogDefaultRadius,
+			Memory: engine.FogDefaultMemory,
+		}))
+	})
+
+	It("fades the reveals the player no longer remembers", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state("alice", 0, 0)).To(Equal(engine.CellStateRevealed))
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 0, 9)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state("alice", 0, 9)).To(Equal(engine.CellStateRevealed))
+		Expect(state("alice", 0, 0)).To(Equal(engine.CellStateRemembered))
+		Expect(game.Project("alice").Board.Cells[0][0].AdjCount).To(BeZero())
+	})
+
+	It("hides the counts of the reveals of other players", func() {
+		delta, err := game.Click("alice", engine.GameClickTypeNormal, 0, 9)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state("bob", 0, 9)).To(Equal(engine.CellStateRemembered))
+		Expect(game.DeltaFor("alice", delta).Cells[0].Fogged).To(BeFalse())
+		fogged := game.DeltaFor("bob", delta).Cells[0]
+		Expect(fogged.Fogged).To(BeTrue())
+		Expect(fogged.AdjMines).To(BeZero())
+		Expect(delta.Cells[0].AdjMines).To(Equal(1), "the delta itself is left untouched")
+		events := delta.Events("alice")
+		Expect(events).NotTo(BeEmpty())
+		Expect(game.EventFor("bob", events[0]).Cells[0].Fogged).To(BeTrue())
+	})
+
+	It("lifts once the game is over", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 9)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 1, 0)
+		Expect(err).To(MatchError(engine.ErrDefeat))
+		Expect(state("bob", 0, 9)).To(Equal(engine.CellStateRevealed))
+		Expect(state("bob", 1, 2)).To(Equal(engine.CellStateMine))
+	})
+})
@@ -0,0 +1,61 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Forks", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = newBoard(4, 4, engine.Rules{}, at(0, 0), at(3, 3))
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("copies the board and the moves into an unranked game of the caller", func() {
+		fork, err := game.Fork("bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fork.ID).NotTo(Equal(game.ID))
+		Expect(fork.ForkedFrom).To(Equal(game.ID))
+		Expect(fork.CreatedBy).To(Equal("bob"))
+		Expect(fork.Unranked).To(BeTrue())
+		// Compared through their encodings, the copied times lose their monotonic clock readings
+		Expect(engine.EncodeBoard(fork.MineField)).To(Equal(engine.EncodeBoard(game.MineField)))
+		Expect(fork.History).To(HaveLen(len(game.History)))
+		_, err = fork.Click("bob", engine.GameClickTypeFlag, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.MineField[0][0].Flagged).To(BeFalse(), "the original game is left untouched")
+	})
+
+	It("copies the game deeply, playing the copy leaves the game alone", func() {
+		copied := game.Copy()
+		Expect(copied).To(Equal(game))
+		_, err := copied.Click("alice", engine.GameClickTypeFlag, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.MineField[0][0].Flagged).To(BeFalse())
+		Expect(game.History).To(HaveLen(len(copied.History) - 1))
+
+		endless := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		Expect(endless.Start()).To(Succeed())
+		copied = endless.Copy()
+		_, err = copied.Click("alice", engine.GameClickTypeFlag, 100, 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endless.Endless.Chunks).To(BeEmpty())
+	})
+
+	It("continues lost games from right before the losing move", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).To(MatchError(engine.ErrDefeat))
+		fork, err := game.Fork("bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fork.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		Expect(fork.FinishedAt).To(BeNil())
+		Expect(fork.Code).To(BeEmpty())
+		Expect(fork.MineField[0][0].Clicked).To(BeFalse())
+		Expect(fork.History[len(fork.History)-1].Result).To(Equal(engine.MoveResultUndone))
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusDefeat))
+	})
+})
@@ -0,0 +1,124 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Arcade items", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = newBoard(5, 5, engine.Rules{Arcade: true}, at(0, 0), at(2, 2), at(4, 4))
+	})
+
+	// give puts an item in the inventory of the player
+	give := func(player string, item engine.Item) {
+		if game.Inventories == nil {
+			game.Inventories = map[string]engine.Inventory{}
+		}
+		if game.Inventories[player] == nil {
+			game.Inventories[player] = engine.Inventory{}
+		}
+		game.Inventories[player][item]++
+	}
+
+	lastMove := func() engine.Move {
+		return game.History[len(game.History)-1]
+	}
+
+	It("hides items under safe fields of new arcade boards", func() {
+		game := engine.NewGame(10, 10, 10, engine.Rules{Arcade: true}, "alice")
+		items := 0
+		for i := range game.MineField {
+			for _, field := range game.MineField[i] {
+				if field.Item != "" {
+					Expect(field.Mine).To(BeFalse())
+					items++
+				}
+			}
+		}
+		Expect(items).To(Equal(90 / 15))
+	})
+
+	It("awards the item of a revealed field", func() {
+		game.MineField[1][1].Item = engine.ItemRadar
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Inventories["alice"][engine.ItemRadar]).To(Equal(1))
+		Expect(game.MineField[1][1].Item).To(BeEmpty())
+		Expect(lastMove().Result).To(Equal(engine.MoveResultAwarded))
+	})
+
+	It("absorbs an explosion with a shield, flagging the mine", func() {
+		give("alice", engine.ItemShield)
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		Expect(game.MineField[0][0].Flagged).To(BeTrue())
+		Expect(game.Inventories["alice"][engine.ItemShield]).To(Equal(0))
+		Expect(lastMove().Result).To(Equal(engine.MoveResultAbsorbed))
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 2, 2)
+		Expect(err).To(MatchError(engine.ErrDefeat))
+	})
+
+	It("counts the mines around a field with a radar, revealing nothing", func() {
+		give("alice", engine.ItemRadar)
+		delta, err := game.Click("alice", engine.GameClickTypeRadar, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.Cells).To(BeEmpty())
+		Expect(lastMove().Result).To(Equal("2"))
+		Expect(game.Inventories["alice"][engine.ItemRadar]).To(Equal(0))
+	})
+
+	It("reveals a safe field with a detector", func() {
+		give("alice", engine.ItemDetector)
+		_, err := game.Click("alice", engine.GameClickTypeDetector, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revealed(game, 1, 1)).To(BeTrue())
+		Expect(lastMove().Result).To(Equal(engine.MoveResultSafe))
+	})
+
+	It("flags a mine with a detector instead of exploding", func() {
+		give("alice", engine.ItemDetector)
+		_, err := game.Click("alice", engine.GameClickTypeDetector, 2, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		Expect(game.MineField[2][2].Flagged).To(BeTrue())
+		Expect(lastMove().Result).To(Equal(engine.MoveResultMine))
+	})
+
+	It("keeps the detector when the field is already revealed", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		give("alice", engine.ItemDetector)
+		moves := len(game.History)
+		delta, err := game.Click("alice", engine.GameClickTypeDetector, 1, 1)
+		Expect(err).To(MatchError(engine.ErrRevealed))
+		Expect(delta).To(BeNil())
+		Expect(game.History).To(HaveLen(moves))
+		Expect(game.Inventories["alice"][engine.ItemDetector]).To(Equal(1))
+	})
+
+	It("keeps the detector when the field is flagged", func() {
+		_, err := game.Click("alice", engine.GameClickTypeFlag, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		give("alice", engine.ItemDetector)
+		moves := len(game.History)
+		delta, err := game.Click("alice", engine.GameClickTypeDetector, 1, 1)
+		Expect(err).To(MatchError(engine.ErrFlagged))
+		Expect(delta).To(BeNil())
+		Expect(game.History).To(HaveLen(moves))
+		Expect(game.Inventories["alice"][engine.ItemDetector]).To(Equal(1))
+		Expect(game.MineField[1][1].Flagged).To(BeTrue())
+	})
+
+	It("refuses items the player doesn't hold, or out of arcade mode", func() {
+		_, err := game.Click("alice", engine.GameClickTypeDetector, 1, 1)
+		Expect(err).To(MatchError(engine.ErrNoItem))
+		classic := newBoard(5, 5, engine.Rules{}, at(0, 0))
+		_, err = classic.Click("alice", engine.GameClickTypeRadar, 1, 1)
+		Expect(err).To(MatchError(engine.ErrNotArcade))
+	})
+})
@@ -0,0 +1,65 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Kernels", func() {
+	It("parses the kernel names, an empty one is Moore", func() {
+		kernel, err := engine.ParseKernel("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kernel).To(Equal(engine.KernelMoore))
+		kernel, err = engine.ParseKernel("knight")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kernel).To(Equal(engine.KernelKnight))
+		_, err = engine.ParseKernel("hexagonal")
+		Expect(err).To(MatchError(engine.ErrUnknownKernel))
+	})
+
+	It("falls back to Moore for games stored before kernels existed", func() {
+		Expect(engine.Kernel("").Offsets()).To(Equal(engine.KernelMoore.Offsets()))
+		Expect(engine.KernelManhattan2.Offsets()).To(HaveLen(12))
+	})
+
+	It("counts the mines a knight's jump away", func() {
+		game := newBoard(5, 5, engine.Rules{Kernel: engine.KernelKnight}, at(2, 2))
+		jumps := []engine.Position{at(0, 1), at(0, 3), at(1, 0), at(1, 4), at(3, 0), at(3, 4), at(4, 1), at(4, 3)}
+		for _, pos := range jumps {
+			Expect(game.MineField[pos.Row][pos.Col].AdjCount).To(Equal(1), "field %v", pos)
+		}
+		Expect(game.MineField[1][1].AdjCount).To(Equal(0))
+		Expect(game.MineField[2][3].AdjCount).To(Equal(0))
+	})
+
+	It("counts the mines within a Manhattan distance of 2", func() {
+		game := newBoard(5, 5, engine.Rules{Kernel: engine.KernelManhattan2}, at(2, 2))
+		Expect(game.MineField[0][2].AdjCount).To(Equal(1))
+		Expect(game.MineField[1][1].AdjCount).To(Equal(1))
+		Expect(game.MineField[0][1].AdjCount).To(Equal(0))
+	})
+
+	It("auto-reveals through the neighbours of the kernel", func() {
+		game := newBoard(5, 5, engine.Rules{Kernel: engine.KernelKnight}, at(2, 2))
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		// [0, 0] is empty for a knight, so the fields a jump away open too while the mine stays hidden
+		Expect(revealed(game, 1, 2)).To(BeTrue())
+		Expect(revealed(game, 2, 1)).To(BeTrue())
+		Expect(revealed(game, 2, 2)).To(BeFalse())
+	})
+
+	It("chords once the flags around a field match its count", func() {
+		game := newBoard(5, 5, engine.Rules{Kernel: engine.KernelMoore}, at(0, 0), at(4, 4))
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.MineField[1][1].AdjCount).To(Equal(1))
+		_, err = game.Click("alice", engine.GameClickTypeFlag, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = game.Click("alice", engine.GameClickTypeChord, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revealed(game, 0, 1)).To(BeTrue())
+		Expect(revealed(game, 1, 0)).To(BeTrue())
+	})
+})
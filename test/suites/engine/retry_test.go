@@ -0,0 +1,49 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retries", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = newBoard(4, 4, engine.Rules{}, at(0, 0), at(3, 3))
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 1, 1)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates a new attempt at the same board, without the moves played", func() {
+		retry := game.Retry("alice")
+		Expect(retry.ID).NotTo(Equal(game.ID))
+		Expect(retry.Status).To(BeEquivalentTo(engine.GameStatusCreated))
+		Expect(retry.History).To(BeEmpty())
+		Expect(retry.PreviousAttemptID).To(Equal(game.ID))
+		Expect(retry.FirstAttemptID).To(Equal(game.ID))
+		for i := range game.MineField {
+			for j, field := range retry.MineField[i] {
+				Expect(field.Mine).To(Equal(game.MineField[i][j].Mine))
+				Expect(field.AdjCount).To(Equal(game.MineField[i][j].AdjCount))
+				Expect(field.Clicked).To(BeFalse())
+			}
+		}
+		Expect(retry.Validate()).To(Succeed())
+	})
+
+	It("links every attempt to the first one", func() {
+		second := game.Retry("alice")
+		third := second.Retry("alice")
+		Expect(third.PreviousAttemptID).To(Equal(second.ID))
+		Expect(third.FirstAttemptID).To(Equal(game.ID))
+	})
+
+	It("summarizes the game as an attempt", func() {
+		attempt := game.Attempt()
+		Expect(attempt.ID).To(Equal(game.ID))
+		Expect(attempt.Moves).To(Equal(1))
+		Expect(attempt.Revealed).To(Equal(1))
+		Expect(attempt.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+	})
+})
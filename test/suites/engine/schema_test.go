@@ -0,0 +1,83 @@
+package engine_test
+
+import (
+	"encoding/json"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("State upgrades", func() {
+	// stateV0 is a state as stored before versioning: no rules, no status and fields without positions
+	stateV0 := func() map[string]interface{} {
+		state := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(`{
+			"id": "game", "rows": 2, "cols": 2, "mines": 1,
+			"mineField": [
+				[{"mine": true}, {"adjMines": 1}],
+				[{"adjMines": 1}, {"adjMines": 1, "clicked": true}]
+			]
+		}`), &state)).To(Succeed())
+
+		return state
+	}
+
+	// decode decodes an upgraded state the way the stored ones are
+	decode := func(state map[string]interface{}) *engine.Game {
+		b, err := json.Marshal(state)
+		Expect(err).NotTo(HaveOccurred())
+		var compact engine.CompactGame
+		Expect(json.Unmarshal(b, &compact)).To(Succeed())
+		game, err := compact.Expand()
+		Expect(err).NotTo(HaveOccurred())
+
+		return game
+	}
+
+	It("tells states stored before versioning apart", func() {
+		Expect(engine.StateVersion(stateV0())).To(Equal(0))
+		Expect(engine.StateVersion(map[string]interface{}{"schemaVersion": float64(1)})).To(Equal(1))
+	})
+
+	It("upgrades the first states to the compact board of a classic Moore game", func() {
+		state := stateV0()
+		upgraded, err := engine.UpgradeState(state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upgraded).To(BeTrue())
+		Expect(engine.StateVersion(state)).To(Equal(engine.StateSchemaVersion))
+		Expect(state).NotTo(HaveKey("mineField"))
+		game := decode(state)
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusCreated))
+		Expect(game.Rules.Kernel).To(Equal(engine.KernelMoore))
+		Expect(game.MineField[1][1].Position).To(Equal(at(1, 1)))
+		Expect(game.MineField[1][1].Clicked).To(BeTrue())
+		Expect(game.Validate()).To(Succeed())
+	})
+
+	It("counts the sizes and the mines out of the mine field of the first states", func() {
+		state := stateV0()
+		state["rows"], state["cols"], state["mines"] = float64(100), float64(0), float64(500)
+		_, err := engine.UpgradeState(state)
+		Expect(err).NotTo(HaveOccurred())
+		game := decode(state)
+		Expect(game.Rows).To(Equal(2))
+		Expect(game.Cols).To(Equal(2))
+		Expect(game.Mines).To(Equal(1))
+		Expect(game.Validate()).To(Succeed())
+	})
+
+	It("leaves current states alone", func() {
+		state := stateV0()
+		_, err := engine.UpgradeState(state)
+		Expect(err).NotTo(HaveOccurred())
+		upgraded, err := engine.UpgradeState(state)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upgraded).To(BeFalse())
+	})
+
+	It("refuses states newer than the engine", func() {
+		_, err := engine.UpgradeState(map[string]interface{}{"schemaVersion": float64(engine.StateSchemaVersion + 1)})
+		Expect(err).To(MatchError(engine.ErrUnknownSchemaVersion))
+	})
+})
@@ -0,0 +1,71 @@
+package engine_test
+
+import (
+	"errors"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("State validation", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		game = newBoard(4, 4, engine.Rules{}, at(0, 0), at(3, 3))
+	})
+
+	// issues lists the inconsistencies Validate finds in the game
+	issues := func() []string {
+		err := game.Validate()
+		Expect(err).To(MatchError(engine.ErrCorruptedState))
+		var stateErr *engine.StateError
+		Expect(errors.As(err, &stateErr)).To(BeTrue())
+		Expect(stateErr.GameID).To(Equal(game.ID))
+
+		return stateErr.Issues
+	}
+
+	It("lists every inconsistency of the derived fields", func() {
+		game.MineField[1][1].AdjCount = 5
+		game.MineField[2][2].Position = at(0, 0)
+		game.Mines = 7
+		Expect(issues()).To(ConsistOf(
+			"field [1, 1] counts 5 adjacent mines, has 1",
+			"field [2, 2] has position [0, 0]",
+			"mine field has 2 mines, expected 7",
+		))
+	})
+
+	It("refuses unknown statuses and kernels", func() {
+		game.Status = "paused"
+		game.Rules.Kernel = "hexagonal"
+		Expect(issues()).To(ConsistOf(`unknown status "paused"`, `unknown kernel "hexagonal"`))
+	})
+
+	It("repairs the derived fields", func() {
+		game.MineField[1][1].AdjCount = 5
+		game.MineField[2][2].Position = at(0, 0)
+		game.Mines = 7
+		game.Repair()
+		Expect(game.Validate()).To(Succeed())
+		Expect(game.Mines).To(Equal(2))
+		Expect(game.MineField[1][1].AdjCount).To(Equal(1))
+	})
+
+	It("can't repair boards with the wrong shape", func() {
+		game.MineField = game.MineField[:3]
+		game.Repair()
+		Expect(issues()).To(ConsistOf("mine field has 3 rows, expected 4"))
+	})
+
+	It("checks the chunks of endless boards", func() {
+		endless := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		Expect(endless.Start()).To(Succeed())
+		_, err := endless.Click("alice", engine.GameClickTypeFlag, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endless.Validate()).To(Succeed())
+		endless.Endless.ChunkSize = 0
+		Expect(endless.Validate()).To(MatchError(engine.ErrCorruptedState))
+	})
+})
@@ -0,0 +1,59 @@
+package engine_test
+
+import (
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Victory", func() {
+	var game *engine.Game
+
+	BeforeEach(func() {
+		// Clicking [0, 2] opens every safe field but [0, 0] and [2, 0], on both sides of the mine at [1, 0]
+		game = newBoard(3, 3, engine.Rules{Arcade: true}, at(1, 0))
+	})
+
+	It("is won by the move revealing the last safe field", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		delta, err := game.Click("alice", engine.GameClickTypeNormal, 2, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusVictory))
+		Expect(delta.Status).To(BeEquivalentTo(engine.GameStatusVictory))
+		Expect(game.FinishedAt).NotTo(BeNil())
+	})
+
+	It("doesn't count flagged safe fields as revealed", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 2)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = game.Click("alice", engine.GameClickTypeFlag, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 2, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+	})
+
+	It("is won by a detector revealing the last safe field", func() {
+		_, err := game.Click("alice", engine.GameClickTypeNormal, 0, 2)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = game.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		game.Inventories = map[string]engine.Inventory{"alice": {engine.ItemDetector: 1}}
+		_, err = game.Click("alice", engine.GameClickTypeDetector, 2, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusVictory))
+	})
+
+	It("is never won on endless boards", func() {
+		endless := engine.NewGame(0, 0, 0, engine.Rules{Type: engine.GameTypeEndless}, "alice")
+		Expect(endless.Start()).To(Succeed())
+		_, err := endless.Click("alice", engine.GameClickTypeNormal, 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endless.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+	})
+})
@@ -0,0 +1,71 @@
+package repo_test
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Game upserts", func() {
+	var (
+		ctx      = context.Background()
+		gameRepo repo.GameRepo
+	)
+
+	// store stores a new game, returning its stored columns
+	store := func(id string) *models.Game {
+		game := &models.Game{BaseModel: models.BaseModel{ID: id}, Rows: 9, Cols: 9, Mines: 10,
+			Status: string(engine.GameStatusStarted)}
+		_, err := gameRepo.UpsertGame(ctx, nil, game)
+		Expect(err).NotTo(HaveOccurred())
+
+		return game
+	}
+
+	BeforeEach(func() {
+		gameRepo = repo.NewGameRepoSvc(db)
+	})
+
+	It("updates the stored game", func() {
+		game := store("upsert-live")
+		game.Status = string(engine.GameStatusAbandoned)
+		_, err := gameRepo.UpsertGame(ctx, &game.ID, game)
+		Expect(err).NotTo(HaveOccurred())
+		stored, err := gameRepo.Read(ctx, game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Status).To(BeEquivalentTo(engine.GameStatusAbandoned))
+		Expect(stored.CreatedAt).NotTo(BeZero())
+	})
+
+	It("leaves soft deleted games deleted", func() {
+		game := store("upsert-deleted")
+		Expect(gameRepo.Delete(ctx, game.ID)).To(Succeed())
+		game.Status = string(engine.GameStatusAbandoned)
+		_, err := gameRepo.UpsertGame(ctx, &game.ID, game)
+		Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+		_, err = gameRepo.Read(ctx, game.ID)
+		Expect(err).To(MatchError(gorm.ErrRecordNotFound))
+		stored, err := gameRepo.ReadWithDeleted(ctx, game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		Expect(gameRepo.Restore(ctx, game.ID)).To(Succeed())
+		_, err = gameRepo.UpsertGame(ctx, &game.ID, game)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("doesn't store the states of soft deleted games", func() {
+		game := store("upsert-flushed")
+		Expect(gameRepo.Delete(ctx, game.ID)).To(Succeed())
+		game.Status = string(engine.GameStatusAbandoned)
+		Expect(gameRepo.UpdateGameStates(ctx, []*models.Game{game})).To(Succeed())
+		stored, err := gameRepo.ReadWithDeleted(ctx, game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+		Expect(stored.DeletedAt.Valid).To(BeTrue())
+	})
+})
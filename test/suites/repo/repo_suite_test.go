@@ -0,0 +1,33 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestRepo(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Repo Suite")
+}
+
+var db *database.DB
+
+var _ = BeforeSuite(func() {
+	gdb, err := gorm.Open(sqlite.Open("file:repo_suite?mode=memory&cache=shared"),
+		&gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	Expect(err).NotTo(HaveOccurred())
+	// Connections to a shared in-memory db lock each other out of its tables instead of waiting
+	sqlDB, err := gdb.DB()
+	Expect(err).NotTo(HaveOccurred())
+	sqlDB.SetMaxOpenConns(1)
+	Expect(migrations.RunMigrations(gdb)).To(Succeed())
+	db = &database.DB{Logger: logger.New(false), DB: gdb}
+})
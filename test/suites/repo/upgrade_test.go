@@ -0,0 +1,51 @@
+package repo_test
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game state upgrades", func() {
+	// storeV0 stores a game the way the first versions did, with only its mine field in the state and the
+	// sizes asked for in the columns, unclamped
+	storeV0 := func(id string) *models.Game {
+		state := models.JSONB{}
+		Expect(json.Unmarshal([]byte(`{"mineField": [[{"mine": true}, {"adjMines": 1}]]}`), &state)).To(Succeed())
+		game := &models.Game{BaseModel: models.BaseModel{ID: id}, Rows: 1000, Cols: 2, Mines: -1,
+			Status: string(engine.GameStatusStarted), GameState: state}
+		Expect(db.Create(game).Error).To(Succeed())
+
+		return game
+	}
+
+	It("rewrites the states behind the schema version, deleted games included", func() {
+		storeV0("upgrade-live")
+		deleted := storeV0("upgrade-deleted")
+		Expect(db.Delete(deleted).Error).To(Succeed())
+		gameRepo := repo.NewGameRepoSvc(db)
+		upgraded, err := gameRepo.UpgradeGameStates(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upgraded).To(BeNumerically(">=", 2))
+		for _, id := range []string{"upgrade-live", "upgrade-deleted"} {
+			stored := &models.Game{}
+			Expect(db.Unscoped().First(stored, "id = ?", id).Error).To(Succeed())
+			Expect(engine.StateVersion(stored.GameState)).To(Equal(engine.StateSchemaVersion), "game %s", id)
+			Expect([]int{stored.Rows, stored.Cols, stored.Mines}).To(Equal([]int{1, 2, 1}))
+			game, err := stored.GetGameState()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+			Expect(game.MineField[0][0].Mine).To(BeTrue())
+			Expect([]int{game.Rows, game.Cols, game.Mines}).To(Equal([]int{1, 2, 1}))
+			Expect(game.Validate()).To(Succeed())
+		}
+		upgraded, err = gameRepo.UpgradeGameStates(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(upgraded).To(BeZero())
+	})
+})
@@ -0,0 +1,80 @@
+package rpc_test
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+var _ = Describe("Game forks, saves and attempts", func() {
+	var alice, bob context.Context
+	var gameID string
+
+	BeforeEach(func() {
+		alice, bob = signUp("alice"), signUp("bob")
+		gameID = createGame(alice)
+		safe := findField(gameID, false)
+		_, err := gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, Row: int32(safe.Row), Col: int32(safe.Col)})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("forks the games, other players only once they finished", func() {
+		_, err := gameClient.ForkGame(bob, &pb.ForkGameRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+
+		fork, err := gameClient.ForkGame(alice, &pb.ForkGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fork.GetId()).NotTo(Equal(gameID))
+		Expect(fork.GetStatus()).To(Equal(engine.GameStatusStarted))
+
+		_, err = gameClient.Forfeit(alice, &pb.ForfeitRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		fork, err = gameClient.ForkGame(bob, &pb.ForkGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fork.GetCreatedBy()).To(HavePrefix("Player bob"))
+	})
+
+	It("saves the games in slots and restores them as practice games", func() {
+		_, err := gameClient.SaveGame(alice, &pb.SaveGameRequest{GameId: gameID, Name: "a/b"})
+		Expect(code(err)).To(Equal(grpccodes.InvalidArgument))
+		_, err = gameClient.SaveGame(bob, &pb.SaveGameRequest{GameId: gameID, Name: "corner"})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+
+		save, err := gameClient.SaveGame(alice, &pb.SaveGameRequest{GameId: gameID, Name: "corner"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(save.GetName()).To(Equal("corner"))
+		saves, err := gameClient.ListSaves(alice, &pb.ListSavesRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saves.GetSaves()).To(HaveLen(1))
+
+		_, err = gameClient.RestoreSave(bob, &pb.RestoreSaveRequest{GameId: gameID, Name: "corner"})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+		_, err = gameClient.RestoreSave(alice, &pb.RestoreSaveRequest{GameId: gameID, Name: "missing"})
+		Expect(code(err)).To(Equal(grpccodes.NotFound))
+		restored, err := gameClient.RestoreSave(alice, &pb.RestoreSaveRequest{GameId: gameID, Name: "corner"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.GetId()).NotTo(Equal(gameID))
+		game, err := gameEngine.GetGame(restored.GetId())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Rules.Practice).To(BeTrue())
+	})
+
+	It("retries the boards and lists the attempts at them", func() {
+		_, err := gameClient.RetryGame(bob, &pb.RetryGameRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+
+		retry, err := gameClient.RetryGame(alice, &pb.RetryGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retry.GetStatus()).To(Equal(engine.GameStatusCreated))
+		attempts, err := gameClient.ListAttempts(alice, &pb.ListAttemptsRequest{GameId: retry.GetId()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts.GetAttempts()).To(HaveLen(2))
+		Expect(attempts.GetAttempts()[0].GetId()).To(Equal(gameID))
+		Expect(attempts.GetAttempts()[0].GetMoves()).To(BeEquivalentTo(1))
+		Expect(attempts.GetAttempts()[1].GetPreviousAttemptId()).To(Equal(gameID))
+	})
+})
@@ -0,0 +1,223 @@
+package rpc_test
+
+import (
+	"context"
+	"io"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// code is the gRPC status code of an error
+func code(err error) grpccodes.Code {
+	return status.Code(err)
+}
+
+var _ = Describe("Game service", func() {
+	var alice context.Context
+
+	BeforeEach(func() {
+		alice = signUp("alice")
+	})
+
+	It("refuses calls without a valid API key", func() {
+		_, err := gameClient.GetGame(context.Background(), &pb.GetGameRequest{GameId: "missing"})
+		Expect(code(err)).To(Equal(grpccodes.Unauthenticated))
+	})
+
+	It("creates, reads and starts games", func() {
+		created, err := gameClient.CreateGame(alice, &pb.CreateGameRequest{Rows: 9, Cols: 9, Mines: 10})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created.GetStatus()).To(Equal(engine.GameStatusCreated))
+		stored := &models.Game{}
+		Expect(db.Where("id = ?", created.GetId()).First(stored).Error).To(Succeed())
+
+		read, err := gameClient.GetGame(alice, &pb.GetGameRequest{GameId: created.GetId()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read.GetId()).To(Equal(created.GetId()))
+		Expect(read.GetCells()).To(HaveLen(81))
+
+		started, err := gameClient.StartGame(alice, &pb.StartGameRequest{GameId: created.GetId()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(started.GetStatus()).To(Equal(engine.GameStatusStarted))
+		_, err = gameClient.StartGame(alice, &pb.StartGameRequest{GameId: created.GetId()})
+		Expect(code(err)).To(Equal(grpccodes.FailedPrecondition))
+
+		_, err = gameClient.GetGame(alice, &pb.GetGameRequest{GameId: "missing"})
+		Expect(code(err)).To(Equal(grpccodes.NotFound))
+	})
+
+	It("answers clicks with what they changed, losing ones included", func() {
+		gameID := createGame(alice)
+		safe := findField(gameID, false)
+		delta, err := gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, Row: int32(safe.Row), Col: int32(safe.Col)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.GetCells()).NotTo(BeEmpty())
+		Expect(delta.GetMove().GetPlayer()).To(HavePrefix("Player alice"))
+
+		_, err = gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, Row: 9, Col: 0})
+		Expect(code(err)).To(Equal(grpccodes.InvalidArgument))
+
+		mine := findField(gameID, true)
+		delta, err = gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, Row: int32(mine.Row), Col: int32(mine.Col)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(delta.GetStatus()).To(Equal(engine.GameStatusDefeat))
+		Expect(delta.GetPreviousStatus()).To(Equal(engine.GameStatusStarted))
+		_, err = gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, Row: int32(safe.Row), Col: int32(safe.Col)})
+		Expect(code(err)).To(Equal(grpccodes.FailedPrecondition))
+	})
+
+	It("plays batches of moves", func() {
+		gameID := createGame(alice)
+		_, err := gameClient.PlayMoves(alice, &pb.PlayMovesRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.InvalidArgument))
+
+		mine := findField(gameID, true)
+		result, err := gameClient.PlayMoves(alice, &pb.PlayMovesRequest{GameId: gameID, Moves: []*pb.PlannedMove{
+			{ClickType: pb.ClickType_CLICK_TYPE_FLAG, Row: int32(mine.Row), Col: int32(mine.Col)},
+			{Row: 9, Col: 9},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.GetMoves()).To(HaveLen(2))
+		Expect(result.GetMoves()[0].GetOutcome()).To(Equal("played"))
+		Expect(result.GetMoves()[1].GetOutcome()).To(Equal("failed"))
+		Expect(result.GetStatus()).To(Equal(engine.GameStatusStarted))
+	})
+
+	It("lets only the creators and the admins forfeit and delete games", func() {
+		gameID := createGame(alice)
+		bob := signUp("bob")
+		_, err := gameClient.Forfeit(bob, &pb.ForfeitRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+		_, err = gameClient.DeleteGame(bob, &pb.DeleteGameRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+		_, err = gameClient.DeleteGame(alice, &pb.DeleteGameRequest{GameId: gameID, Hard: true})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+
+		forfeited, err := gameClient.Forfeit(alice, &pb.ForfeitRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forfeited.GetStatus()).To(Equal(engine.GameStatusForfeited))
+		_, err = gameClient.Forfeit(alice, &pb.ForfeitRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.FailedPrecondition))
+
+		_, err = gameClient.DeleteGame(alice, &pb.DeleteGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = gameClient.GetGame(alice, &pb.GetGameRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.NotFound))
+
+		makeAdmin(bob)
+		_, err = gameClient.DeleteGame(bob, &pb.DeleteGameRequest{GameId: gameID, Hard: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.Unscoped().Where("id = ?", gameID).First(&models.Game{}).Error).To(HaveOccurred())
+	})
+
+	It("lists, views and rates the games", func() {
+		gameID := createGame(alice)
+		created, err := gameClient.CreateGame(alice, &pb.CreateGameRequest{Rows: 9, Cols: 9, Mines: 10})
+		Expect(err).NotTo(HaveOccurred())
+		list, err := gameClient.ListGames(alice, &pb.ListGamesRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.GetGames()).To(HaveLen(2))
+		list, err = gameClient.ListGames(alice, &pb.ListGamesRequest{Status: engine.GameStatusCreated})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.GetGames()).To(HaveLen(1))
+		Expect(list.GetGames()[0].GetId()).To(Equal(created.GetId()))
+
+		viewport, err := gameClient.ViewGame(alice, &pb.ViewGameRequest{GameId: gameID, Row: 7, Col: 7, Rows: 4, Cols: 4})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(viewport.GetCells()).To(HaveLen(4))
+		Expect(viewport.GetCells()[0].GetCells()[0].GetState()).To(Equal(engine.CellStateHidden))
+		Expect(viewport.GetCells()[3].GetCells()[3].GetState()).To(Equal(engine.CellStateVoid))
+
+		difficulty, err := gameClient.GetDifficulty(alice, &pb.GetDifficultyRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(difficulty.GetBbbv()).To(BeNumerically(">", 0))
+		Expect(difficulty.GetTier()).NotTo(BeEmpty())
+		endless, err := gameClient.CreateGame(alice, &pb.CreateGameRequest{Type: string(engine.GameTypeEndless)})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = gameClient.GetDifficulty(alice, &pb.GetDifficultyRequest{GameId: endless.GetId()})
+		Expect(code(err)).To(Equal(grpccodes.FailedPrecondition))
+	})
+
+	It("lists the moves to other players only once the game finished", func() {
+		gameID := createGame(alice)
+		mine := findField(gameID, true)
+		_, err := gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, ClickType: pb.ClickType_CLICK_TYPE_FLAG,
+			Row: int32(mine.Row), Col: int32(mine.Col)})
+		Expect(err).NotTo(HaveOccurred())
+		moves, err := gameClient.ListMoves(alice, &pb.ListMovesRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(moves.GetMoves()).To(HaveLen(1))
+		Expect(moves.GetMoves()[0].GetRow()).To(BeEquivalentTo(mine.Row))
+
+		bob := signUp("bob")
+		_, err = gameClient.ListMoves(bob, &pb.ListMovesRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+		_, err = gameClient.Forfeit(alice, &pb.ForfeitRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		moves, err = gameClient.ListMoves(bob, &pb.ListMovesRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(moves.GetMoves()).NotTo(BeEmpty())
+	})
+
+	It("restores the deleted games", func() {
+		gameID := createGame(alice)
+		_, err := gameClient.DeleteGame(alice, &pb.DeleteGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = gameClient.RestoreGame(signUp("bob"), &pb.RestoreGameRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.PermissionDenied))
+		restored, err := gameClient.RestoreGame(alice, &pb.RestoreGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.GetId()).To(Equal(gameID))
+		_, err = gameClient.GetGame(alice, &pb.GetGameRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("tells the corrupted game states apart", func() {
+		gameID := createGame(alice)
+		gameStore := &models.Game{}
+		Expect(db.Where("id = ?", gameID).First(gameStore).Error).To(Succeed())
+		game, err := gameStore.GetGameState()
+		Expect(err).NotTo(HaveOccurred())
+		game.Mines++
+		gameStore.UpdateGameState(game)
+		Expect(db.Model(gameStore).Update("game_state", gameStore.GameState).Error).To(Succeed())
+		gameEngine.RemoveGame(gameID)
+
+		_, err = gameClient.GetGame(alice, &pb.GetGameRequest{GameId: gameID})
+		Expect(code(err)).To(Equal(grpccodes.Internal))
+		Expect(status.Convert(err).Message()).To(ContainSubstring("Corrupted game state"))
+	})
+
+	It("streams the events of a game", func() {
+		gameID := createGame(alice)
+		ctx, cancel := context.WithCancel(alice)
+		defer cancel()
+		stream, err := gameClient.StreamEvents(ctx, &pb.StreamEventsRequest{GameId: gameID})
+		Expect(err).NotTo(HaveOccurred())
+		joined, err := stream.Recv()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(joined.GetType()).To(Equal(string(engine.EventPlayerJoined)))
+
+		mine := findField(gameID, true)
+		_, err = gameClient.Click(alice, &pb.ClickRequest{GameId: gameID, ClickType: pb.ClickType_CLICK_TYPE_FLAG,
+			Row: int32(mine.Row), Col: int32(mine.Col)})
+		Expect(err).NotTo(HaveOccurred())
+		flagged, err := stream.Recv()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(flagged.GetType()).To(Equal(string(engine.EventFlagChanged)))
+		Expect(flagged.GetCells()).To(HaveLen(1))
+		Expect(flagged.GetCells()[0].GetFlagged()).To(BeTrue())
+
+		cancel()
+		Eventually(func() error {
+			_, err := stream.Recv()
+			return err
+		}).ShouldNot(Or(Succeed(), Equal(io.EOF)))
+	})
+})
@@ -0,0 +1,137 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	"github.com/loopcontext/msgcat"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestRPC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RPC Suite")
+}
+
+var (
+	db         *database.DB
+	gameEngine service.MineSweeperGameSvc
+	server     *grpc.Server
+	conn       *grpc.ClientConn
+	authClient pb.AuthServiceClient
+	gameClient pb.GameServiceClient
+)
+
+var _ = BeforeSuite(func() {
+	gdb, err := gorm.Open(sqlite.Open("file:rpc_suite?mode=memory&cache=shared"),
+		&gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	Expect(err).NotTo(HaveOccurred())
+	// Connections to a shared in-memory db lock each other out of its tables instead of waiting
+	sqlDB, err := gdb.DB()
+	Expect(err).NotTo(HaveOccurred())
+	sqlDB.SetMaxOpenConns(1)
+	Expect(migrations.RunMigrations(gdb)).To(Succeed())
+	log := logger.New(false)
+	db = &database.DB{Logger: log, DB: gdb}
+	catalog, err := msgcat.NewMessageCatalog(msgcat.Config{ResourcePath: "../../../resources/messages"})
+	Expect(err).NotTo(HaveOccurred())
+	gamesSvc := service.MineSweeperGameSvcImpl{}
+	gameEngine = gamesSvc.NewMineSweeperSvc(log, service.Config{}, repo.NewGameRepoSvc(db))
+	server = rpc.NewServer(*log, catalog, repo.NewAuthRepoSvc(db, *log, catalog), repo.NewGameRepoSvc(db), gameEngine)
+	listener := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	conn, err = grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	Expect(err).NotTo(HaveOccurred())
+	authClient = pb.NewAuthServiceClient(conn)
+	gameClient = pb.NewGameServiceClient(conn)
+})
+
+var _ = AfterSuite(func() {
+	if conn != nil {
+		Expect(conn.Close()).To(Succeed())
+	}
+	if server != nil {
+		// Event streams never end on their own
+		gameEngine.CloseSubscriptions()
+		server.Stop()
+	}
+})
+
+// users counts the users signed up, to keep their names unique across specs
+var users int
+
+// signUp creates a user and signs it in, returning the context of its calls
+func signUp(name string) context.Context {
+	users++
+	username := fmt.Sprintf("%s%d", name, users)
+	_, err := authClient.CreateUser(context.Background(), &pb.CreateUserRequest{
+		Username: username,
+		Password: username,
+		Email:    username + "@minesweeper.svc",
+		Fullname: "Player " + username,
+	})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	signedIn, err := authClient.SignIn(context.Background(), &pb.SignInRequest{Username: username, Password: username})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	return metadata.AppendToOutgoingContext(context.Background(), rpc.MetadataAPIKey, signedIn.GetApiKey())
+}
+
+// makeAdmin grants the user of the context the admin role
+func makeAdmin(ctx context.Context) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	key := &models.UserAPIKey{}
+	Expect(db.Where("api_key = ?", md.Get(rpc.MetadataAPIKey)[0]).First(key).Error).To(Succeed())
+	Expect(db.Model(&models.User{}).Where("id = ?", key.UserID).Update("admin", true).Error).To(Succeed())
+}
+
+// createGame creates a started game as the user of the context, returning its ID
+func createGame(ctx context.Context) string {
+	game, err := gameClient.CreateGame(ctx, &pb.CreateGameRequest{Rows: 9, Cols: 9, Mines: 10})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	_, err = gameClient.StartGame(ctx, &pb.StartGameRequest{GameId: game.GetId()})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	return game.GetId()
+}
+
+// findField finds a field of the game with a mine, or without one
+func findField(gameID string, mine bool) engine.Position {
+	game, err := gameEngine.GetGame(gameID)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	for i := range game.MineField {
+		for j, field := range game.MineField[i] {
+			if field.Mine == mine {
+				return engine.Position{Row: i, Col: j}
+			}
+		}
+	}
+	Fail("the game has no such field")
+
+	return engine.Position{}
+}
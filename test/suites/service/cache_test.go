@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game cache", func() {
+	It("evicts the least recently used games, storing the ones changed", func() {
+		svc := newService(service.Config{CacheSize: 2, WriteBehind: true})
+		first, second := newGame(svc), newGame(svc)
+		pos := safeField(first)
+		_, _, err := svc.Click(context.Background(), first.ID, "alice", engine.GameClickTypeFlag, pos.Row, pos.Col)
+		Expect(err).NotTo(HaveOccurred())
+		// Using the second game leaves the first one as the least recently used
+		_, err = svc.GetGame(second.ID)
+		Expect(err).NotTo(HaveOccurred())
+		newGame(svc)
+		_, err = svc.GetGame(first.ID)
+		Expect(err).To(MatchError(service.ErrGameNotFound))
+		_, err = svc.GetGame(second.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() bool {
+			return storedGame(first.ID).MineField[pos.Row][pos.Col].Flagged
+		}).Should(BeTrue())
+	})
+
+	It("evicts the games not used for longer than the ttl", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		svc := newService(service.Config{CacheTTL: 20 * time.Millisecond, ReapInterval: 10 * time.Millisecond})
+		game := newGame(svc)
+		svc.StartWorkers(ctx)
+		// Getting the game would keep it in memory, the list leaves it alone
+		Eventually(func() map[string]*engine.Game {
+			games, _ := svc.GetGameList()
+			return games
+		}).ShouldNot(HaveKey(game.ID))
+	})
+})
+
+var _ = Describe("Idle games reaper", func() {
+	It("abandons the idle games, in memory and stored", func() {
+		svc := newService(service.Config{IdleTimeout: 10 * time.Millisecond})
+		cached := newGame(svc)
+		// A game started by another instance is only stored
+		stored := newGame(newService(service.Config{}))
+		time.Sleep(20 * time.Millisecond)
+		abandoned, err := svc.AbandonIdleGames(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abandoned).To(ContainElements(cached.ID, stored.ID))
+		cached, err = svc.GetGame(cached.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached.Status).To(BeEquivalentTo(engine.GameStatusAbandoned))
+		for _, id := range []string{cached.ID, stored.ID} {
+			Expect(storedGame(id).Status).To(BeEquivalentTo(engine.GameStatusAbandoned), "game %s", id)
+		}
+	})
+
+	It("leaves the games being played alone", func() {
+		svc := newService(service.Config{IdleTimeout: time.Hour})
+		game := newGame(svc)
+		abandoned, err := svc.AbandonIdleGames(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(abandoned).NotTo(ContainElement(game.ID))
+		game, err = svc.GetGame(game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusStarted))
+	})
+
+	It("abandons games in between their moves", func() {
+		svc := newService(service.Config{IdleTimeout: time.Nanosecond})
+		game := newGame(svc)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < game.Rows; i++ {
+				// Moves after the game was abandoned are refused, the ones before it are kept whole
+				_, _, _ = svc.Click(context.Background(), game.ID, "alice", engine.GameClickTypeFlag, i, 0)
+			}
+		}()
+		_, err := svc.AbandonIdleGames(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		wg.Wait()
+		game, err = svc.GetGame(game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(game.Status).To(BeEquivalentTo(engine.GameStatusAbandoned))
+		Expect(game.Validate()).To(Succeed())
+	})
+})
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Game events", func() {
+	var (
+		svc  service.MineSweeperGameSvc
+		game *engine.Game
+	)
+
+	BeforeEach(func() {
+		svc = newService(service.Config{})
+		game = newRulesGame(svc, engine.Rules{Fog: engine.NewFogRules(1, 1)})
+	})
+
+	// reveal reveals a safe field of the game for alice, returning how many events it published
+	reveal := func() int {
+		pos := safeField(game)
+		_, delta, err := svc.Click(context.Background(), game.ID, "alice", engine.GameClickTypeNormal, pos.Row, pos.Col)
+		Expect(err).NotTo(HaveOccurred())
+
+		return len(delta.Events("alice"))
+	}
+
+	It("hands every subscriber the events as its player is allowed to see them", func() {
+		aliceSub, bobSub := svc.Subscribe(game.ID, "alice"), svc.Subscribe(game.ID, "bob")
+		defer svc.Unsubscribe(aliceSub)
+		defer svc.Unsubscribe(bobSub)
+		events := reveal()
+		for i := 0; i < events; i++ {
+			aliceEvent, bobEvent := <-aliceSub.Events, <-bobSub.Events
+			Expect(bobEvent.Seq).To(Equal(aliceEvent.Seq))
+			for j, cell := range bobEvent.Cells {
+				Expect(aliceEvent.Cells[j].Fogged).To(BeFalse())
+				if cell.Revealed && !cell.Mine {
+					Expect(cell.Fogged).To(BeTrue())
+					Expect(cell.AdjMines).To(BeZero())
+				}
+			}
+		}
+	})
+
+	It("projects the events missed by the subscribers resuming", func() {
+		reveal()
+		sub, missed, err := svc.Resume(context.Background(), game.ID, "bob", 1)
+		Expect(err).NotTo(HaveOccurred())
+		defer svc.Unsubscribe(sub)
+		Expect(missed).NotTo(BeEmpty())
+		for _, e := range missed {
+			for _, cell := range e.Cells {
+				if cell.Revealed && !cell.Mine {
+					Expect(cell.Fogged).To(BeTrue())
+				}
+			}
+		}
+	})
+
+	It("projects the missed events of the games no longer held in memory", func() {
+		svc = newService(service.Config{CacheSize: 1})
+		game = newRulesGame(svc, engine.Rules{Fog: engine.NewFogRules(1, 1)})
+		reveal()
+		// The game gets evicted to make room for another one
+		newGame(svc)
+		sub, missed, err := svc.Resume(context.Background(), game.ID, "bob", 1)
+		Expect(err).NotTo(HaveOccurred())
+		defer svc.Unsubscribe(sub)
+		Expect(missed).NotTo(BeEmpty())
+		for _, e := range missed {
+			for _, cell := range e.Cells {
+				if cell.Revealed && !cell.Mine {
+					Expect(cell.Fogged).To(BeTrue())
+				}
+			}
+		}
+	})
+
+	It("refuses to resume the events of games that don't exist", func() {
+		_, _, err := svc.Resume(context.Background(), "missing", "bob", 1)
+		Expect(err).To(HaveOccurred())
+	})
+})
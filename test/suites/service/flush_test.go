@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Write-behind", func() {
+	var (
+		ctx  = context.Background()
+		svc  service.MineSweeperGameSvc
+		game *engine.Game
+	)
+
+	// durable sets how soon the changes of the game get stored
+	durable := func(game *engine.Game, durability engine.Durability) *engine.Game {
+		game, err := svc.UpdateGame(ctx, game.ID, func(game *engine.Game) error {
+			game.Durability = durability
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		return game
+	}
+
+	BeforeEach(func() {
+		svc = newService(service.Config{WriteBehind: true, FlushBatch: 2})
+		game = durable(newGame(svc), engine.DurabilityBatch)
+	})
+
+	// flag flags a safe field of the game, returning the field
+	flag := func(game *engine.Game) engine.Position {
+		pos := safeField(game)
+		_, _, err := svc.Click(ctx, game.ID, "alice", engine.GameClickTypeFlag, pos.Row, pos.Col)
+		Expect(err).NotTo(HaveOccurred())
+
+		return pos
+	}
+
+	It("stores the moves of batch games in the next flush", func() {
+		pos := flag(game)
+		Expect(storedGame(game.ID).MineField[pos.Row][pos.Col].Flagged).To(BeFalse())
+		flushed, err := svc.Flush(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(flushed).To(Equal(1))
+		Expect(storedGame(game.ID).MineField[pos.Row][pos.Col].Flagged).To(BeTrue())
+		flushed, err = svc.Flush(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(flushed).To(BeZero())
+	})
+
+	It("stores the changed games in batches", func() {
+		games := []*engine.Game{game, newGame(svc), newGame(svc)}
+		for i, g := range games[1:] {
+			games[i+1] = durable(g, engine.DurabilityBatch)
+		}
+		var flagged []engine.Position
+		for _, g := range games {
+			flagged = append(flagged, flag(g))
+		}
+		flushed, err := svc.Flush(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(flushed).To(Equal(len(games)))
+		for i, g := range games {
+			Expect(storedGame(g.ID).MineField[flagged[i].Row][flagged[i].Col].Flagged).To(BeTrue())
+		}
+	})
+
+	It("stores every move of the games asking for it", func() {
+		game = durable(game, engine.DurabilityMove)
+		pos := flag(game)
+		Expect(storedGame(game.ID).MineField[pos.Row][pos.Col].Flagged).To(BeTrue())
+	})
+
+	It("stores the games as they are in between their moves", func() {
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			// Flagging a field twice takes the flag off
+			for i := 0; i < 10000; i++ {
+				_, _, err := svc.Click(ctx, game.ID, "alice", engine.GameClickTypeFlag, i%game.Rows, game.Cols-1)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}()
+		for flushing := true; flushing; {
+			select {
+			case <-done:
+				flushing = false
+			default:
+			}
+			_, err := svc.Flush(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(storedGame(game.ID).Validate()).To(Succeed())
+		}
+		game, err := svc.GetGame(game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storedGame(game.ID).Version).To(Equal(game.Version))
+	})
+})
@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Concurrent moves", func() {
+	ctx := context.Background()
+
+	// flags are the moves flagging the fields of a row of the game
+	flags := func(game *engine.Game, row int) (moves []engine.PlannedMove) {
+		for col := 0; col < game.Cols; col++ {
+			moves = append(moves, engine.PlannedMove{ClickType: engine.GameClickTypeFlag, Row: row, Col: col})
+		}
+
+		return moves
+	}
+
+	It("stores every batch played at the same time in a game", func() {
+		svc := newService(service.Config{})
+		game := newGame(svc)
+		var wg sync.WaitGroup
+		for row := 0; row < game.Rows; row++ {
+			wg.Add(1)
+			go func(moves []engine.PlannedMove) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, result, err := svc.PlayMoves(ctx, game.ID, "alice", moves)
+				Expect(err).NotTo(HaveOccurred())
+				for _, move := range result.Moves {
+					Expect(move.Outcome).To(Equal(engine.MoveOutcomePlayed))
+				}
+			}(flags(game, row))
+		}
+		wg.Wait()
+		stored := storedGame(game.ID)
+		Expect(stored.History).To(HaveLen(game.Rows * game.Cols))
+		for i := range stored.MineField {
+			for j, field := range stored.MineField[i] {
+				Expect(field.Flagged).To(BeTrue(), "field [%d, %d]", i, j)
+			}
+		}
+	})
+
+	It("plays the moves of every instance on the stored state", func() {
+		svc, other := newService(service.Config{}), newService(service.Config{})
+		game := newGame(svc)
+		_, _, err := other.PlayMoves(ctx, game.ID, "bob", flags(game, 0))
+		Expect(err).NotTo(HaveOccurred())
+		played, _, err := svc.PlayMoves(ctx, game.ID, "alice", flags(game, 1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(played.History).To(HaveLen(2 * game.Cols))
+		Expect(storedGame(game.ID).History).To(HaveLen(2 * game.Cols))
+	})
+
+	It("hands out copies of the games, read while their moves are played", func() {
+		svc := newService(service.Config{})
+		game := newGame(svc)
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			for row := 0; row < game.Rows; row++ {
+				_, _, err := svc.PlayMoves(ctx, game.ID, "alice", flags(game, row))
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}()
+		for reading := true; reading; {
+			select {
+			case <-done:
+				reading = false
+			default:
+			}
+			read, err := svc.LoadGame(ctx, game.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(read.Validate()).To(Succeed())
+			Expect(read.History).To(HaveLen(int(read.Version) - 1))
+		}
+		read, err := svc.GetGame(game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		read.MineField[0][0].Flagged = false
+		read.History = nil
+		read, err = svc.GetGame(game.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read.MineField[0][0].Flagged).To(BeTrue())
+		Expect(read.History).To(HaveLen(game.Rows * game.Cols))
+	})
+
+	It("keeps playing the games removed while they are being played", func() {
+		svc := newService(service.Config{})
+		game := newGame(svc)
+		var wg sync.WaitGroup
+		for row := 0; row < game.Rows; row++ {
+			wg.Add(1)
+			go func(moves []engine.PlannedMove) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, _, err := svc.PlayMoves(ctx, game.ID, "alice", moves)
+				Expect(err).NotTo(HaveOccurred())
+			}(flags(game, row))
+			svc.RemoveGame(game.ID)
+		}
+		wg.Wait()
+		Expect(storedGame(game.ID).History).To(HaveLen(game.Rows * game.Cols))
+	})
+})
@@ -1,13 +1,95 @@
 package service_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/database/migrations"
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 func TestService(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Service Suite")
 }
+
+var (
+	log      *logger.Logger
+	db       *database.DB
+	gameRepo repo.GameRepo
+)
+
+var _ = BeforeSuite(func() {
+	gdb, err := gorm.Open(sqlite.Open("file:service_suite?mode=memory&cache=shared"),
+		&gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	Expect(err).NotTo(HaveOccurred())
+	// Connections to a shared in-memory db lock each other out of its tables instead of waiting
+	sqlDB, err := gdb.DB()
+	Expect(err).NotTo(HaveOccurred())
+	sqlDB.SetMaxOpenConns(1)
+	Expect(migrations.RunMigrations(gdb)).To(Succeed())
+	log = logger.New(false)
+	db = &database.DB{Logger: log, DB: gdb}
+	gameRepo = repo.NewGameRepoSvc(db)
+})
+
+// newService creates a minesweeper game service storing its games in the suite db
+func newService(cfg service.Config) service.MineSweeperGameSvc {
+	svc := service.MineSweeperGameSvcImpl{}
+
+	return svc.NewMineSweeperSvc(log, cfg, gameRepo)
+}
+
+// newGame creates a started game in the service and stores it, the way the handlers do
+func newGame(svc service.MineSweeperGameSvc) *engine.Game {
+	return newRulesGame(svc, engine.Rules{})
+}
+
+// newRulesGame creates a started game played with the rules, like newGame
+func newRulesGame(svc service.MineSweeperGameSvc, rules engine.Rules) *engine.Game {
+	game, err := svc.CreateGame(9, 9, 10, rules, "alice")
+	Expect(err).NotTo(HaveOccurred())
+	gameStore := &models.Game{Rows: game.Rows, Cols: game.Cols, Mines: game.Mines}
+	gameStore.ID = game.ID
+	gameStore.UpdateGameState(game)
+	_, err = gameRepo.UpsertGame(context.Background(), nil, gameStore)
+	Expect(err).NotTo(HaveOccurred())
+	game, err = svc.StartGame(context.Background(), game.ID)
+	Expect(err).NotTo(HaveOccurred())
+
+	return game
+}
+
+// storedGame reads the state of a game as stored
+func storedGame(gameID string) *engine.Game {
+	gameStore, err := gameRepo.Read(context.Background(), gameID)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	game, err := gameStore.GetGameState()
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	return game
+}
+
+// safeField finds a field without a mine that wasn't revealed yet
+func safeField(game *engine.Game) engine.Position {
+	for i := range game.MineField {
+		for j, field := range game.MineField[i] {
+			if !field.Mine && !field.Clicked {
+				return engine.Position{Row: i, Col: j}
+			}
+		}
+	}
+	Fail("the game has no safe field left")
+
+	return engine.Position{}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/cmelgarejo/minesweeper-svc/database"
 	"github.com/loopcontext/msgcat"
@@ -13,6 +14,12 @@ var (
 	defaultResponseContentType = "application/json"
 	ErrDBMissingDSN            = fmt.Errorf("Missing DSN")
 	ErrDBMissingPORT           = fmt.Errorf("Missing PORT")
+	defaultShutdownTimeout     = 30 * time.Second
+	defaultHTTPIdleTimeout     = 10 * time.Second
+	// DefaultIdempotencyRetention is how long a key and its response are kept
+	DefaultIdempotencyRetention = 24 * time.Hour
+	// DefaultIdempotencyLease is how long a key is held for a request still being handled
+	DefaultIdempotencyLease = time.Minute
 )
 
 type Config struct {
@@ -20,18 +27,40 @@ type Config struct {
 	Server         Server
 	MessageCatalog msgcat.Config
 	DB             database.DBConfig
+	Game           Game
+}
+
+// Game configures the game service, the durations and sizes left at zero take the defaults of the service
+type Game struct {
+	RepairState   bool          // recompute the derived fields of inconsistent game states instead of refusing them
+	IdleTimeout   time.Duration // started games not played for longer than this get abandoned, 0 never abandons them
+	CacheSize     int           // games kept in memory
+	CacheTTL      time.Duration // games not used for longer than this get evicted
+	ReapInterval  time.Duration // how often idle games get abandoned and expired ones evicted
+	WriteBehind   bool          // store the changes of the games in batches instead of on every move
+	FlushInterval time.Duration // how often the changed games get stored in write-behind mode
+	FlushBatch    int           // games stored in each transaction
 }
 
 type Server struct {
-	Host                string
-	Port                int
-	ResponseContentType string
+	Host                 string
+	Port                 int
+	ResponseContentType  string
+	ShutdownTimeout      time.Duration // how long in-flight requests get to finish when the server stops
+	IdleTimeout          time.Duration // keep-alive connections idle for longer get closed, shutting down waits for them
+	IdempotencyRetention time.Duration // how long the responses of requests sent with an Idempotency-Key are replayed
+	IdempotencyLease     time.Duration // how long the key of a request is held while it's handled, in case it never ends
+	GRPCPort             int           // port of the gRPC server, zero leaves it off
 }
 
 func (srvcfg *Server) BuildServerAddr() string {
 	return srvcfg.Host + ":" + strconv.Itoa(srvcfg.Port)
 }
 
+func (srvcfg *Server) BuildGRPCAddr() string {
+	return srvcfg.Host + ":" + strconv.Itoa(srvcfg.GRPCPort)
+}
+
 func InitConfig() (*Config, error) {
 	port, err := strconv.Atoi(os.Getenv("PORT"))
 	if err != nil {
@@ -41,6 +70,56 @@ func InitConfig() (*Config, error) {
 	if dsn == "" {
 		return nil, ErrDBMissingDSN
 	}
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if err != nil {
+		return nil, err
+	}
+	httpIdleTimeout, err := durationEnv("HTTP_IDLE_TIMEOUT", defaultHTTPIdleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyRetention, err := durationEnv("IDEMPOTENCY_RETENTION", DefaultIdempotencyRetention)
+	if err != nil {
+		return nil, err
+	}
+	idempotencyLease, err := durationEnv("IDEMPOTENCY_LEASE", DefaultIdempotencyLease)
+	if err != nil {
+		return nil, err
+	}
+	var grpcPort int
+	if p := os.Getenv("GRPC_PORT"); p != "" {
+		if grpcPort, err = strconv.Atoi(p); err != nil {
+			return nil, fmt.Errorf("Invalid GRPC_PORT: %w", err)
+		}
+	}
+	gameIdleTimeout, err := durationEnv("GAME_IDLE_TIMEOUT", 0)
+	if err != nil {
+		return nil, err
+	}
+	gameCacheTTL, err := durationEnv("GAME_CACHE_TTL", 0)
+	if err != nil {
+		return nil, err
+	}
+	gameReapInterval, err := durationEnv("GAME_REAP_INTERVAL", 0)
+	if err != nil {
+		return nil, err
+	}
+	gameFlushInterval, err := durationEnv("GAME_FLUSH_INTERVAL", 0)
+	if err != nil {
+		return nil, err
+	}
+	var gameFlushBatch int
+	if batch := os.Getenv("GAME_FLUSH_BATCH"); batch != "" {
+		if gameFlushBatch, err = strconv.Atoi(batch); err != nil {
+			return nil, fmt.Errorf("Invalid GAME_FLUSH_BATCH: %w", err)
+		}
+	}
+	var gameCacheSize int
+	if size := os.Getenv("GAME_CACHE_SIZE"); size != "" {
+		if gameCacheSize, err = strconv.Atoi(size); err != nil {
+			return nil, fmt.Errorf("Invalid GAME_CACHE_SIZE: %w", err)
+		}
+	}
 	responseContentType := os.Getenv("RESPONSE_CONTENT_TYPE")
 	if responseContentType == "" {
 		responseContentType = defaultResponseContentType
@@ -48,9 +127,14 @@ func InitConfig() (*Config, error) {
 	return &Config{
 		Debug: os.Getenv("DEBUG") == "TRUE",
 		Server: Server{
-			Host:                os.Getenv("HOST"),
-			Port:                port,
-			ResponseContentType: responseContentType,
+			Host:                 os.Getenv("HOST"),
+			Port:                 port,
+			ResponseContentType:  responseContentType,
+			ShutdownTimeout:      shutdownTimeout,
+			IdleTimeout:          httpIdleTimeout,
+			IdempotencyRetention: idempotencyRetention,
+			IdempotencyLease:     idempotencyLease,
+			GRPCPort:             grpcPort,
 		},
 		MessageCatalog: msgcat.Config{
 			CtxLanguageKey: msgcat.ContextKey(os.Getenv("MESSAGE_CATALOG_LANGUAGE_KEY")),
@@ -61,5 +145,29 @@ func InitConfig() (*Config, error) {
 			Automigrate: os.Getenv("DB_AUTOMIGRATE") == "TRUE",
 			Debug:       os.Getenv("DEBUG") == "TRUE",
 		},
+		Game: Game{
+			RepairState:   os.Getenv("GAME_REPAIR_STATE") == "TRUE",
+			IdleTimeout:   gameIdleTimeout,
+			CacheSize:     gameCacheSize,
+			CacheTTL:      gameCacheTTL,
+			ReapInterval:  gameReapInterval,
+			WriteBehind:   os.Getenv("GAME_WRITE_BEHIND") == "TRUE",
+			FlushInterval: gameFlushInterval,
+			FlushBatch:    gameFlushBatch,
+		},
 	}, nil
 }
+
+// durationEnv parses a duration (like 30m or 24h) out of an environment variable, fallback when it is not set
+func durationEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s: %w", name, err)
+	}
+
+	return d, nil
+}
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"net/http"
+	"net/url"
 
 	swagger "github.com/arsmn/fiber-swagger/v2"
 	"github.com/cmelgarejo/minesweeper-svc/database"
@@ -12,6 +14,7 @@ import (
 	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
 	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
 	"github.com/cmelgarejo/minesweeper-svc/web/handlers/games"
+	"github.com/cmelgarejo/minesweeper-svc/web/handlers/graph"
 	"github.com/cmelgarejo/minesweeper-svc/web/handlers/ping"
 	"github.com/cmelgarejo/minesweeper-svc/web/handlers/users"
 	"github.com/cmelgarejo/minesweeper-svc/web/middleware"
@@ -22,6 +25,8 @@ import (
 	fiberlogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+	"github.com/gofiber/websocket/v2"
 	"github.com/loopcontext/msgcat"
 )
 
@@ -35,28 +40,32 @@ import (
 // @host minesweeper-svc.herokuapp.com
 // @schemes https
 // @BasePath /
-func InitFiberServer(cfg *config.Config, log *logger.Logger,
+func InitFiberServer(ctx context.Context, cfg *config.Config, log *logger.Logger,
 	catalog *msgcat.MessageCatalog, db *database.DB, gameEngineSvc service.MineSweeperGameSvc) (app *fiber.App, err error) {
-	app = fiber.New()
+	app = fiber.New(fiber.Config{
+		IdleTimeout: cfg.Server.IdleTimeout,
+	})
 	app.Use(recover.New())
 	app.Use(requestid.New())
 	app.Use(fiberlogger.New(fiberlogger.Config{
-		Format: "${time} - ${ip} - ${pid} - ${locals:requestid} - ${ua} - ${status} ${method} ${path}​ [url: ${url}]​​ \n",
+		Format:     "${time} - ${ip} - ${pid} - ${locals:requestid} - ${ua} - ${status} ${method} ${path}​ [url: ${url}]​​ \n",
+		CustomTags: map[string]fiberlogger.LogFunc{fiberlogger.TagURL: logURL},
 	}))
 
 	app.Use("/swagger", swagger.Handler) // swagger
 
-	err = setupRoutes(app, cfg, log, *catalog, db, gameEngineSvc)
+	err = setupRoutes(ctx, app, cfg, log, *catalog, db, gameEngineSvc)
 
 	return app, err
 }
 
-func setupRoutes(app *fiber.App, cfg *config.Config, log *logger.Logger, catalog msgcat.MessageCatalog, db *database.DB, gameEngineSvc service.MineSweeperGameSvc) (err error) {
+func setupRoutes(ctx context.Context, app *fiber.App, cfg *config.Config, log *logger.Logger, catalog msgcat.MessageCatalog, db *database.DB, gameEngineSvc service.MineSweeperGameSvc) (err error) {
 	// Default handler
 	pingHandler := adaptor.HTTPHandlerFunc(ping.Ping)
 	// Repos
 	authRepo := repo.NewAuthRepoSvc(db, *log, catalog)
 	gameRepo := repo.NewGameRepoSvc(db)
+	idempotencyRepo := repo.NewIdempotencyRepoSvc(db)
 	// HTTP server's request and response service
 	requestHelperSvc := common.NewRequestHelperSvc(*log, catalog)
 	responseHelperSvc := common.NewResponseHelperSvc(*log, catalog, cfg.Server.ResponseContentType)
@@ -65,36 +74,114 @@ func setupRoutes(app *fiber.App, cfg *config.Config, log *logger.Logger, catalog
 	// Auth middleware
 	authMiddleware := middleware.NewAuthMiddlewareSvc(*log, catalog, authRepo, responseHelperSvc)
 	apiKeyMiddleware := HTTPMiddleware(authMiddleware.CheckUserAPIKey)
+	// Idempotency middleware replays the responses of the retried mutating requests
+	idempotencyMiddleware := middleware.NewIdempotencyMiddlewareSvc(*log, catalog, idempotencyRepo, responseHelperSvc,
+		cfg.Server.IdempotencyRetention, cfg.Server.IdempotencyLease)
+	idempotencyMiddleware.StartWorkers(ctx)
 	// Game
 	gameHandler := games.NewGameHandlerSvc(*log, catalog, gameRepo, gameEngineSvc, authSvc, requestHelperSvc, responseHelperSvc)
 	gameCreate := adaptor.HTTPHandlerFunc(gameHandler.Create)
+	gameCreateFromCode := adaptor.HTTPHandlerFunc(gameHandler.CreateFromCode)
 	gameRead := adaptor.HTTPHandlerFunc(gameHandler.Read)
+	gameView := adaptor.HTTPHandlerFunc(gameHandler.View)
+	gameDifficulty := adaptor.HTTPHandlerFunc(gameHandler.Difficulty)
 	gameClick := adaptor.HTTPHandlerFunc(gameHandler.Click)
+	gameMoves := adaptor.HTTPHandlerFunc(gameHandler.Moves)
+	gameFork := adaptor.HTTPHandlerFunc(gameHandler.Fork)
+	gameSave := adaptor.HTTPHandlerFunc(gameHandler.Save)
+	gameListSaves := adaptor.HTTPHandlerFunc(gameHandler.ListSaves)
+	gameRestoreSave := adaptor.HTTPHandlerFunc(gameHandler.RestoreSave)
+	gameRetry := adaptor.HTTPHandlerFunc(gameHandler.Retry)
+	gameAttempts := adaptor.HTTPHandlerFunc(gameHandler.Attempts)
+	gameForfeit := adaptor.HTTPHandlerFunc(gameHandler.Forfeit)
+	gameAbandon := adaptor.HTTPHandlerFunc(gameHandler.Abandon)
+	gameDelete := adaptor.HTTPHandlerFunc(gameHandler.Delete)
+	gameRestore := adaptor.HTTPHandlerFunc(gameHandler.Restore)
 	gameList := adaptor.HTTPHandlerFunc(gameHandler.List)
 	gameStart := adaptor.HTTPHandlerFunc(gameHandler.Start)
+	gameSocket := websocket.New(gameHandler.Socket)
+	gameResourceCreate := HTTPHandler(gameHandler.CreateGame)
+	gameResourceRead := HTTPHandler(gameHandler.GetGame)
+	gameResourceDelete := HTTPHandler(gameHandler.DeleteGame)
+	gameResourceStart := HTTPHandler(gameHandler.StartGame)
+	gameResourceReveal := HTTPHandler(gameHandler.Reveal)
+	gameResourceFlag := HTTPHandler(gameHandler.Flag)
+	gameResourceUnflag := HTTPHandler(gameHandler.Unflag)
+	gameResourceMoves := HTTPHandler(gameHandler.PlayMoves)
+	gameStream := gameHandler.Stream
 	// Game
-	authHandler := users.NewUserHandlerSvc(*log, catalog, authRepo, requestHelperSvc, responseHelperSvc)
+	authHandler := users.NewUserHandlerSvc(*log, catalog, authRepo, authSvc, requestHelperSvc, responseHelperSvc)
 	authCreate := adaptor.HTTPHandlerFunc(authHandler.Create)
 	authRead := adaptor.HTTPHandlerFunc(authHandler.Read)
 	authUpdate := adaptor.HTTPHandlerFunc(authHandler.Update)
 	authSignIn := adaptor.HTTPHandlerFunc(authHandler.SignIn)
+	authMe := HTTPHandler(authHandler.Me)
+	// Graph
+	graphHandler := graph.NewGraphHandlerSvc(*log, catalog, authRepo, gameRepo, gameEngineSvc, authSvc,
+		requestHelperSvc, responseHelperSvc)
+	graphQuery := adaptor.HTTPHandlerFunc(graphHandler.Query)
+	graphSocket := websocket.New(graphHandler.Socket, websocket.Config{Subprotocols: []string{graph.SocketProtocol}})
 
 	app.Get("/", pingHandler)
 	app.Get("/v1", pingHandler)
 	app.Get("/ping", pingHandler)
 	app.Get("/health", pingHandler)
 
+	// WebSocket upgrades and event streams are routed ahead of the groups, they take the API key from the
+	// query too as browsers can't set headers on them
+	for _, games := range []string{"/v1/api/games", "/v2/api/games"} {
+		app.Get(games+"/:id/ws", queryAPIKey, apiKeyMiddleware, gameSocket)
+		app.Get(games+"/:id/events", queryAPIKey, apiKeyMiddleware, gameStream)
+	}
+	app.Get("/graphql", queryAPIKey, apiKeyMiddleware, graphSocket)
+
 	// Middleware makes sure only authorized users are allowed to use these resources
-	api := app.Group("/v1/api", apiKeyMiddleware)
+	api := app.Group("/v1/api", apiKeyMiddleware, idempotencyMiddleware.CheckIdempotencyKey)
 
 	// Game
 	gameRoute := api.Group("/games")
 	gameRoute.Post("/", gameCreate)
 	gameRoute.Get("/", gameList)
+	gameRoute.Post("/codes/:code", gameCreateFromCode)
 	gameRoute.Get("/:id", gameRead)
+	gameRoute.Get("/:id/view", gameView)
+	gameRoute.Get("/:id/difficulty", gameDifficulty)
 	gameRoute.Patch("/:id", gameClick)
+	gameRoute.Post("/:id/moves", gameMoves)
+	gameRoute.Post("/:id/fork", gameFork)
+	gameRoute.Post("/:id/saves", gameSave)
+	gameRoute.Get("/:id/saves", gameListSaves)
+	gameRoute.Post("/:id/saves/:name/restore", gameRestoreSave)
+	gameRoute.Post("/:id/retry", gameRetry)
+	gameRoute.Get("/:id/attempts", gameAttempts)
+	gameRoute.Post("/abandon", gameAbandon)
+	gameRoute.Post("/:id/forfeit", gameForfeit)
+	gameRoute.Delete("/:id", gameDelete)
+	gameRoute.Post("/:id/restore", gameRestore)
 	gameRoute.Post("/start/:id", gameStart)
 
+	// Resource routes, params are taken from the routes
+	apiV2 := app.Group("/v2/api", apiKeyMiddleware, idempotencyMiddleware.CheckIdempotencyKey)
+
+	// Game
+	gameResource := apiV2.Group("/games")
+	gameResource.Post("/", gameResourceCreate)
+	gameResource.Get("/:id", gameResourceRead)
+	gameResource.Delete("/:id", gameResourceDelete)
+	gameResource.Post("/:id/start", gameResourceStart)
+	gameResource.Post("/:id/reveals", gameResourceReveal)
+	gameResource.Post("/:id/flags", gameResourceFlag)
+	gameResource.Delete("/:id/flags", gameResourceUnflag)
+	gameResource.Post("/:id/moves", gameResourceMoves)
+
+	// User
+	userResource := apiV2.Group("/users")
+	userResource.Get("/me", authMe)
+
+	// Graph, subscriptions go over a WebSocket upgrade of the same path, routed with the streams. Mutations
+	// sent with an Idempotency-Key are made safe to retry like the REST writes
+	app.Post("/graphql", apiKeyMiddleware, idempotencyMiddleware.CheckIdempotencyKey, graphQuery)
+
 	apiAuth := app.Group("/v1/auth")
 	// Auth
 	apiAuth.Post("/signIn", authSignIn)
@@ -106,6 +193,46 @@ func setupRoutes(app *fiber.App, cfg *config.Config, log *logger.Logger, catalog
 	return err
 }
 
+// queryAPIKey takes the API key of WebSocket upgrades and event streams from the apiKey query parameter,
+// browsers can't set headers on them
+func queryAPIKey(c *fiber.Ctx) error {
+	if c.Get(middleware.HeaderAPIKey) == "" {
+		c.Request().Header.Set(middleware.HeaderAPIKey, c.Query(QueryAPIKey))
+	}
+
+	return c.Next()
+}
+
+// QueryAPIKey is the query parameter holding the API key of WebSocket upgrades and event streams
+const QueryAPIKey = "apiKey"
+
+// logURL logs the URL of the request without the API key it may carry in its query
+func logURL(output fiberlogger.Buffer, c *fiber.Ctx, _ *fiberlogger.Data, _ string) (int, error) {
+	u, err := url.Parse(c.OriginalURL())
+	if err != nil || !u.Query().Has(QueryAPIKey) {
+		return output.WriteString(c.OriginalURL())
+	}
+	query := u.Query()
+	query.Set(QueryAPIKey, "REDACTED")
+	u.RawQuery = query.Encode()
+
+	return output.WriteString(u.String())
+}
+
+// HTTPHandler wraps a net/http handler to a fiber handler, handing it the params of the route, see
+// common.RouteParam. The params are copied as fiber reuses their memory once the handler returns.
+func HTTPHandler(h http.HandlerFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		params := make(map[string]string, len(c.Route().Params))
+		for _, param := range c.Route().Params {
+			params[param] = fiberutils.CopyString(c.Params(param))
+		}
+		return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h(w, common.WithRouteParams(r, params))
+		})(c)
+	}
+}
+
 // HTTPMiddleware wraps net/http middleware to fiber middleware
 func HTTPMiddleware(mw func(http.Handler) http.Handler) fiber.Handler {
 	return func(c *fiber.Ctx) error {
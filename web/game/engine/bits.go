@@ -0,0 +1,20 @@
+package engine
+
+// Helpers for bit-packed planes, one bit per field in row-major order
+
+// newPlane returns a plane able to hold n bits
+func newPlane(n int) []byte {
+	return make([]byte, (n+7)/8)
+}
+
+func getBit(plane []byte, i int) bool {
+	return plane[i/8]&(1<<uint(i%8)) != 0
+}
+
+func setBit(plane []byte, i int, value bool) {
+	if value {
+		plane[i/8] |= 1 << uint(i%8)
+	} else {
+		plane[i/8] &^= 1 << uint(i%8)
+	}
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrInvalidBoardCode = errors.New("Invalid board code")
+	ErrGeneratorVersion = errors.New("Board code made by another version of the board generator")
+)
+
+// BoardGeneratorVersion is the version of the board generator, bump it whenever the same seed would
+// lay out a different board, so the seeded board codes of the previous version get refused
+const BoardGeneratorVersion = 1
+
+// boardCodeVersion is the first byte of every board code, bump it when the layout of the codes changes
+const boardCodeVersion = 2
+
+// Flags of the rules carried by a board code
+const (
+	boardCodeArcade = 1 << iota
+	boardCodeFog
+)
+
+// codeTypes and codeKernels index the game types and kernels in the board codes, never reorder them
+var (
+	codeTypes   = []GameType{GameTypeClassic, GameTypeEndless}
+	codeKernels = []Kernel{KernelMoore, KernelKnight, KernelManhattan2}
+)
+
+// BoardCode returns a short URL-safe code that encodes the board of the game: its rules, its dimensions
+// and the seed and generator version it is laid out from. The moves played are not part of the code,
+// a game created from it starts from scratch. Boards stored before they had a seed have no code.
+func (g *Game) BoardCode() string {
+	if g.Seed == 0 {
+		return ""
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteByte(boardCodeVersion)
+	gameType := GameTypeClassic
+	if g.IsEndless() {
+		gameType = GameTypeEndless
+	}
+	buf.WriteByte(byte(indexOf(len(codeTypes), func(i int) bool { return codeTypes[i] == gameType })))
+	kernel := g.Rules.Kernel
+	if kernel == "" {
+		kernel = KernelMoore
+	}
+	buf.WriteByte(byte(indexOf(len(codeKernels), func(i int) bool { return codeKernels[i] == kernel })))
+	var flags byte
+	if g.Rules.Arcade {
+		flags |= boardCodeArcade
+	}
+	if g.Rules.Fog != nil {
+		flags |= boardCodeFog
+	}
+	buf.WriteByte(flags)
+	if g.Rules.Fog != nil {
+		putUvarint(buf, uint64(g.Rules.Fog.Radius))
+		putUvarint(buf, uint64(g.Rules.Fog.Memory))
+	}
+	putUvarint(buf, uint64(g.Rows))
+	putUvarint(buf, uint64(g.Cols))
+	putUvarint(buf, uint64(g.Mines))
+	buf.WriteByte(BoardGeneratorVersion)
+	putVarint(buf, g.Seed)
+
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes())
+}
+
+// NewGameFromCode creates a game with the board encoded in a board code
+func NewGameFromCode(code, createdBy string) (*Game, error) {
+	data, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return nil, ErrInvalidBoardCode
+	}
+	r := bytes.NewReader(data)
+	header := make([]byte, 4)
+	if _, err = io.ReadFull(r, header); err != nil || header[0] != boardCodeVersion {
+		return nil, ErrInvalidBoardCode
+	}
+	gameType, kernel, flags := int(header[1]), int(header[2]), header[3]
+	if gameType >= len(codeTypes) || kernel >= len(codeKernels) {
+		return nil, ErrInvalidBoardCode
+	}
+	rules := Rules{
+		Type:   codeTypes[gameType],
+		Kernel: codeKernels[kernel],
+		Arcade: flags&boardCodeArcade != 0,
+	}
+	if flags&boardCodeFog != 0 {
+		radius, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, ErrInvalidBoardCode
+		}
+		memory, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, ErrInvalidBoardCode
+		}
+		rules.Fog = NewFogRules(int(radius), int(memory))
+	}
+	dims := make([]int, 3)
+	for i := range dims {
+		v, err := binary.ReadUvarint(r)
+		if err != nil || v > GameMaxRows*GameMaxCols {
+			return nil, ErrInvalidBoardCode
+		}
+		dims[i] = int(v)
+	}
+	rows, cols, mines := dims[0], dims[1], dims[2]
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, ErrInvalidBoardCode
+	}
+	if version != BoardGeneratorVersion {
+		return nil, ErrGeneratorVersion
+	}
+	seed, err := binary.ReadVarint(r)
+	if err != nil || seed == 0 || mines < 1 {
+		return nil, ErrInvalidBoardCode
+	}
+	if rules.Type == GameTypeEndless {
+		if mines > EndlessMaxChunkMines {
+			return nil, ErrInvalidBoardCode
+		}
+		game := newEndlessGame(mines, rules, createdBy)
+		game.Seed = seed
+
+		return game, nil
+	}
+	if rows < GameMinRows || cols < GameMinCols || rows > GameMaxRows || cols > GameMaxCols || mines > rows*cols {
+		return nil, ErrInvalidBoardCode
+	}
+
+	return newClassicGame(rows, cols, mines, rules, seed, createdBy), nil
+}
+
+// finish ends the game, finished games show the code of their board
+func (g *Game) finish(status GameStatus) {
+	g.Status = status
+	now := time.Now()
+	g.FinishedAt = &now
+	g.Code = g.BoardCode()
+}
+
+func indexOf(n int, match func(i int) bool) int {
+	for i := 0; i < n; i++ {
+		if match(i) {
+			return i
+		}
+	}
+
+	return 0
+}
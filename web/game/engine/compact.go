@@ -0,0 +1,284 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrCorruptedBoard = errors.New("Corrupted compact board")
+)
+
+// EncodingCompact is the name of the compact board encoding, as requested by the clients
+const EncodingCompact = "compact"
+
+// compactBoardVersion is the first byte of every compact board, bump it when the layout changes
+const compactBoardVersion = 1
+
+// compactItems indexes the arcade items in the compact boards, 0 is no item
+var compactItems = []Item{"", ItemRadar, ItemShield, ItemDetector}
+
+// EncodeBoard packs a mine field into the compact board encoding, all of it in row-major order:
+//
+//	version byte, uvarint rows, uvarint cols
+//	mine, clicked and flagged bit planes
+//	adjacent counts, run-length encoded as (uvarint run, byte count) pairs
+//	uvarint amount of players, then each player as uvarint length and bytes
+//	who clicked each field, run-length encoded as (uvarint run, uvarint player index + 1) pairs
+//	arcade items, run-length encoded as (uvarint run, uvarint item index) pairs
+//	reveal times of the clicked fields, as zig-zag varint millisecond deltas from the previous one
+func EncodeBoard(mineField [][]Field) []byte {
+	rows, cols := len(mineField), 0
+	if rows > 0 {
+		cols = len(mineField[0])
+	}
+	fields := make([]Field, 0, rows*cols)
+	for i := range mineField {
+		fields = append(fields, mineField[i]...)
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteByte(compactBoardVersion)
+	putUvarint(buf, uint64(rows))
+	putUvarint(buf, uint64(cols))
+	mines, clicked, flagged := newPlane(len(fields)), newPlane(len(fields)), newPlane(len(fields))
+	for i, field := range fields {
+		setBit(mines, i, field.Mine)
+		setBit(clicked, i, field.Clicked)
+		setBit(flagged, i, field.Flagged)
+	}
+	buf.Write(mines)
+	buf.Write(clicked)
+	buf.Write(flagged)
+
+	players := map[string]uint64{}
+	var names []string
+	for _, field := range fields {
+		if _, found := players[field.ClickedBy]; field.ClickedBy != "" && !found {
+			names = append(names, field.ClickedBy)
+			players[field.ClickedBy] = uint64(len(names))
+		}
+	}
+	writeRuns(buf, fields, func(f Field) uint64 { return uint64(f.AdjCount) }, func(v uint64) {
+		buf.WriteByte(byte(v))
+	})
+	putUvarint(buf, uint64(len(names)))
+	for _, name := range names {
+		putUvarint(buf, uint64(len(name)))
+		buf.WriteString(name)
+	}
+	writeRuns(buf, fields, func(f Field) uint64 { return players[f.ClickedBy] }, func(v uint64) {
+		putUvarint(buf, v)
+	})
+	writeRuns(buf, fields, func(f Field) uint64 { return uint64(itemIndex(f.Item)) }, func(v uint64) {
+		putUvarint(buf, v)
+	})
+	var last int64
+	for _, field := range fields {
+		if !field.Clicked {
+			continue
+		}
+		var at int64
+		if field.RevealedAt != nil {
+			at = field.RevealedAt.UnixNano() / int64(time.Millisecond)
+		}
+		putVarint(buf, at-last)
+		last = at
+	}
+
+	return buf.Bytes()
+}
+
+// DecodeBoard unpacks a mine field out of the compact board encoding
+func DecodeBoard(data []byte) (mineField [][]Field, err error) {
+	r := bytes.NewReader(data)
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, ErrCorruptedBoard
+	}
+	if version != compactBoardVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrCorruptedBoard, version)
+	}
+	rows, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, ErrCorruptedBoard
+	}
+	cols, err := binary.ReadUvarint(r)
+	if err != nil || rows > GameMaxRows || cols > GameMaxCols {
+		return nil, ErrCorruptedBoard
+	}
+	n := int(rows * cols)
+	fields := make([]Field, n)
+	planes := make([][]byte, 3)
+	for p := range planes {
+		planes[p] = newPlane(n)
+		if _, err = io.ReadFull(r, planes[p]); err != nil {
+			return nil, ErrCorruptedBoard
+		}
+	}
+	for i := range fields {
+		fields[i].Mine = getBit(planes[0], i)
+		fields[i].Clicked = getBit(planes[1], i)
+		fields[i].Flagged = getBit(planes[2], i)
+		fields[i].Position = Position{Row: i / int(cols), Col: i % int(cols)}
+	}
+	err = readRuns(r, n, func() (uint64, error) {
+		b, err := r.ReadByte()
+		return uint64(b), err
+	}, func(i int, v uint64) error {
+		fields[i].AdjCount = int(v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil || count > uint64(n) {
+		return nil, ErrCorruptedBoard
+	}
+	names := make([]string, count)
+	for i := range names {
+		length, err := binary.ReadUvarint(r)
+		if err != nil || length > uint64(r.Len()) {
+			return nil, ErrCorruptedBoard
+		}
+		name := make([]byte, length)
+		if _, err = io.ReadFull(r, name); err != nil {
+			return nil, ErrCorruptedBoard
+		}
+		names[i] = string(name)
+	}
+	err = readRuns(r, n, func() (uint64, error) { return binary.ReadUvarint(r) }, func(i int, v uint64) error {
+		if v > uint64(len(names)) {
+			return ErrCorruptedBoard
+		}
+		if v > 0 {
+			fields[i].ClickedBy = names[v-1]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = readRuns(r, n, func() (uint64, error) { return binary.ReadUvarint(r) }, func(i int, v uint64) error {
+		if v >= uint64(len(compactItems)) {
+			return ErrCorruptedBoard
+		}
+		fields[i].Item = compactItems[v]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var last int64
+	for i := range fields {
+		if !fields[i].Clicked {
+			continue
+		}
+		delta, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, ErrCorruptedBoard
+		}
+		last += delta
+		if last != 0 {
+			at := time.Unix(0, last*int64(time.Millisecond))
+			fields[i].RevealedAt = &at
+		}
+	}
+	mineField = make([][]Field, rows)
+	for i := range mineField {
+		mineField[i] = fields[i*int(cols) : (i+1)*int(cols)]
+	}
+
+	return mineField, nil
+}
+
+// CompactGame is a game with its mine field in the compact board encoding
+type CompactGame struct {
+	*Game
+	MineField [][]Field `json:"mineField,omitempty"` // hides the mine field of the game
+	Board     []byte    `json:"board"`               // compact board, base64 encoded in JSON
+}
+
+// Compact returns the game with its mine field in the compact board encoding
+func (g *Game) Compact() *CompactGame {
+	compact := &CompactGame{Game: g}
+	if g.MineField != nil {
+		compact.Board = EncodeBoard(g.MineField)
+	}
+
+	return compact
+}
+
+// Expand returns the game with its mine field decoded out of the compact board
+func (c *CompactGame) Expand() (*Game, error) {
+	if c.Game == nil {
+		return nil, ErrCorruptedBoard
+	}
+	if c.Board != nil {
+		mineField, err := DecodeBoard(c.Board)
+		if err != nil {
+			return nil, err
+		}
+		c.Game.MineField = mineField
+	}
+
+	return c.Game, nil
+}
+
+func itemIndex(item Item) int {
+	for i, known := range compactItems {
+		if known == item {
+			return i
+		}
+	}
+
+	return 0
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	buf.Write(b[:binary.PutUvarint(b, v)])
+}
+
+func putVarint(buf *bytes.Buffer, v int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	buf.Write(b[:binary.PutVarint(b, v)])
+}
+
+// writeRuns run-length encodes a value of the fields as (uvarint run, value) pairs
+func writeRuns(buf *bytes.Buffer, fields []Field, value func(Field) uint64, put func(uint64)) {
+	for i := 0; i < len(fields); {
+		v, run := value(fields[i]), 1
+		for i+run < len(fields) && value(fields[i+run]) == v {
+			run++
+		}
+		putUvarint(buf, uint64(run))
+		put(v)
+		i += run
+	}
+}
+
+// readRuns decodes n values run-length encoded by writeRuns
+func readRuns(r *bytes.Reader, n int, get func() (uint64, error), set func(i int, v uint64) error) error {
+	for i := 0; i < n; {
+		run, err := binary.ReadUvarint(r)
+		if err != nil || run == 0 || run > uint64(n-i) {
+			return ErrCorruptedBoard
+		}
+		v, err := get()
+		if err != nil {
+			return ErrCorruptedBoard
+		}
+		for end := i + int(run); i < end; i++ {
+			if err = set(i, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrUnknownTier    = errors.New("Unknown difficulty tier")
+	ErrTierNotReached = errors.New("Could not generate a board in the requested difficulty tier")
+	ErrNotRateable    = errors.New("Endless boards can't be rated")
+)
+
+const (
+	// TierMaxAttempts is how many boards get generated at most looking for one in the requested tier
+	TierMaxAttempts = 10
+	// TierMaxFields bounds the fields rated looking for a tier, large boards get fewer attempts
+	TierMaxFields = 10000
+	// guessPenalty is what each guess the solver needs adds to the rating
+	guessPenalty = 20
+)
+
+// Tier is a range of difficulty ratings, Min inclusive and Max exclusive
+type Tier struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// Tiers games are filed into, ordered by difficulty
+var Tiers = []Tier{
+	{Name: "beginner", Min: 0, Max: 60},
+	{Name: "intermediate", Min: 60, Max: 250},
+	{Name: "expert", Min: 250, Max: 800},
+	{Name: "master", Min: 800, Max: math.MaxFloat64},
+}
+
+// ParseTier validates the name of a tier, an empty name means no tier
+func ParseTier(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, tier := range Tiers {
+		if tier.Name == name {
+			return name, nil
+		}
+	}
+
+	return "", ErrUnknownTier
+}
+
+// Difficulty rates how hard a board is
+type Difficulty struct {
+	BBBV     int     `json:"3bv"`      // minimum clicks needed to clear the board
+	Unsolved int     `json:"unsolved"` // safe fields left when a deductive solver first had to guess
+	Guesses  int     `json:"guesses"`  // guesses the solver needed to clear the board
+	Openings int     `json:"openings"` // areas without adjacent mines, each opened with one click
+	Density  float64 `json:"density"`  // mines per field
+	Rating   float64 `json:"rating"`
+	Tier     string  `json:"tier"`
+}
+
+// Rate rates the difficulty of the board layout, regardless of the moves played. The rating grows with
+// the 3BV weighted by the mine density, adds a penalty for each guess the solver is forced to make and
+// gets harder the fewer openings the board has. Endless boards can't be rated.
+func (g *Game) Rate() *Difficulty {
+	if g.IsEndless() || len(g.MineField) == 0 {
+		return nil
+	}
+	d := &Difficulty{
+		Density: float64(g.Mines) / float64(g.Rows*g.Cols),
+	}
+	d.BBBV, d.Openings = g.bbbv()
+	d.Unsolved, d.Guesses = newSolver(g).solve()
+	d.Rating = (float64(d.BBBV)*(1+2*d.Density) + float64(guessPenalty*d.Guesses)) * (1 + 0.5/float64(1+d.Openings))
+	d.Rating = math.Round(d.Rating*100) / 100
+	for _, tier := range Tiers {
+		if d.Rating >= tier.Min && d.Rating < tier.Max {
+			d.Tier = tier.Name
+			break
+		}
+	}
+
+	return d
+}
+
+// bbbv returns the Bechtel's Board Benchmark Value: each opening counts once, plus every numbered field
+// that no opening reveals. It also returns the amount of openings.
+func (g *Game) bbbv() (bbbv, openings int) {
+	marked := make([][]bool, g.Rows)
+	for i := range marked {
+		marked[i] = make([]bool, g.Cols)
+	}
+	for i := 0; i < g.Rows; i++ {
+		for j := 0; j < g.Cols; j++ {
+			field := g.MineField[i][j]
+			if marked[i][j] || field.Mine || field.AdjCount != 0 {
+				continue
+			}
+			openings++
+			queue := []Position{{i, j}}
+			marked[i][j] = true
+			for len(queue) > 0 {
+				pos := queue[0]
+				queue = queue[1:]
+				if g.MineField[pos.Row][pos.Col].AdjCount != 0 {
+					continue
+				}
+				for _, n := range g.neighbours(pos.Row, pos.Col) {
+					if !marked[n.Row][n.Col] && !g.MineField[n.Row][n.Col].Mine {
+						marked[n.Row][n.Col] = true
+						queue = append(queue, n)
+					}
+				}
+			}
+		}
+	}
+	bbbv = openings
+	for i := 0; i < g.Rows; i++ {
+		for j := 0; j < g.Cols; j++ {
+			if !marked[i][j] && !g.MineField[i][j].Mine {
+				bbbv++
+			}
+		}
+	}
+
+	return bbbv, openings
+}
+
+// solver plays a board deducing what it can from the revealed numbers, and guessing when it can't
+type solver struct {
+	game      *Game
+	revealed  [][]bool
+	mines     [][]bool // mines the solver deduced
+	safeLeft  int      // safe fields not revealed yet
+	minesLeft int      // mines not deduced yet
+}
+
+func newSolver(g *Game) *solver {
+	s := &solver{
+		game:      g,
+		revealed:  make([][]bool, g.Rows),
+		mines:     make([][]bool, g.Rows),
+		safeLeft:  g.Rows*g.Cols - g.Mines,
+		minesLeft: g.Mines,
+	}
+	for i := 0; i < g.Rows; i++ {
+		s.revealed[i] = make([]bool, g.Cols)
+		s.mines[i] = make([]bool, g.Cols)
+	}
+
+	return s
+}
+
+// solve plays the board from an opening, as a first click that is always safe would, until it is cleared.
+// It returns how many safe fields were left when it first got stuck, and how many guesses it took.
+func (s *solver) solve() (unsolved, guesses int) {
+	if s.safeLeft < 1 {
+		return 0, 0
+	}
+	s.open()
+	unsolved = -1
+	for s.safeLeft > 0 {
+		if s.deduce() {
+			continue
+		}
+		if unsolved < 0 {
+			unsolved = s.safeLeft
+		}
+		s.guess()
+		guesses++
+	}
+	if unsolved < 0 {
+		unsolved = 0
+	}
+
+	return unsolved, guesses
+}
+
+// guess plays the unknown field least likely to be a mine, by the revealed numbers bordering it or by the
+// mines left when no number does. The mines are only looked at once it is picked: a safe field is revealed,
+// a mine is marked, as the player learns it either way.
+func (s *solver) guess() {
+	odds := make(map[Position]float64)
+	for _, c := range s.constraints() {
+		p := float64(c.Mines) / float64(len(c.Unknown))
+		for _, f := range c.Unknown {
+			if q, found := odds[f]; !found || p > q {
+				odds[f] = p
+			}
+		}
+	}
+	var unknown []Position
+	for i := 0; i < s.game.Rows; i++ {
+		for j := 0; j < s.game.Cols; j++ {
+			if !s.revealed[i][j] && !s.mines[i][j] {
+				unknown = append(unknown, Position{i, j})
+			}
+		}
+	}
+	best, bestOdds := unknown[0], math.MaxFloat64
+	for _, f := range unknown {
+		p, found := odds[f]
+		if !found {
+			p = float64(s.minesLeft) / float64(len(unknown))
+		}
+		if p < bestOdds {
+			best, bestOdds = f, p
+		}
+	}
+	if s.game.MineField[best.Row][best.Col].Mine {
+		s.mines[best.Row][best.Col] = true
+		s.minesLeft--
+	} else {
+		s.reveal(best.Row, best.Col)
+	}
+}
+
+// open reveals the safe field with the lowest count, an opening if the board has any, as the first click
+func (s *solver) open() {
+	best, bestCount := Position{-1, -1}, math.MaxInt32
+	for i := 0; i < s.game.Rows; i++ {
+		for j := 0; j < s.game.Cols; j++ {
+			field := s.game.MineField[i][j]
+			if !field.Mine && field.AdjCount < bestCount {
+				best, bestCount = Position{i, j}, field.AdjCount
+			}
+		}
+	}
+	s.reveal(best.Row, best.Col)
+}
+
+// reveal opens a safe field, flooding through the empty ones like the game does
+func (s *solver) reveal(row, col int) {
+	queue := []Position{{row, col}}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		if s.revealed[pos.Row][pos.Col] {
+			continue
+		}
+		s.revealed[pos.Row][pos.Col] = true
+		s.safeLeft--
+		if s.game.MineField[pos.Row][pos.Col].AdjCount == 0 {
+			queue = append(queue, s.game.neighbours(pos.Row, pos.Col)...)
+		}
+	}
+}
+
+// constraint says that Mines of the Unknown fields are mines
+type constraint struct {
+	Unknown []Position
+	Mines   int
+}
+
+// constraints builds a constraint out of each revealed number bordering unknown fields
+func (s *solver) constraints() []constraint {
+	var list []constraint
+	for i := 0; i < s.game.Rows; i++ {
+		for j := 0; j < s.game.Cols; j++ {
+			if !s.revealed[i][j] || s.game.MineField[i][j].AdjCount == 0 {
+				continue
+			}
+			c := constraint{Mines: s.game.MineField[i][j].AdjCount}
+			for _, n := range s.game.neighbours(i, j) {
+				if s.mines[n.Row][n.Col] {
+					c.Mines--
+				} else if !s.revealed[n.Row][n.Col] {
+					c.Unknown = append(c.Unknown, n)
+				}
+			}
+			if len(c.Unknown) > 0 {
+				list = append(list, c)
+			}
+		}
+	}
+
+	return list
+}
+
+// deduce applies the single point rules, the subset rule and the global mine count,
+// it returns false when nothing could be deduced
+func (s *solver) deduce() bool {
+	progress := false
+	list := s.constraints()
+	for _, c := range list {
+		progress = s.settle(c.Unknown, c.Mines) || progress
+	}
+	if progress {
+		return true
+	}
+	for a := range list {
+		for b := range list {
+			if a == b || len(list[a].Unknown) >= len(list[b].Unknown) {
+				continue
+			}
+			rest, ok := difference(list[b].Unknown, list[a].Unknown)
+			if ok {
+				progress = s.settle(rest, list[b].Mines-list[a].Mines) || progress
+			}
+		}
+		if progress {
+			return true
+		}
+	}
+	var unknown []Position
+	for i := 0; i < s.game.Rows; i++ {
+		for j := 0; j < s.game.Cols; j++ {
+			if !s.revealed[i][j] && !s.mines[i][j] {
+				unknown = append(unknown, Position{i, j})
+			}
+		}
+	}
+
+	return s.settle(unknown, s.minesLeft)
+}
+
+// settle marks the fields as safe when none is a mine, or as mines when all of them are
+func (s *solver) settle(fields []Position, mines int) bool {
+	if len(fields) == 0 || (mines != 0 && mines != len(fields)) {
+		return false
+	}
+	progress := false
+	for _, f := range fields {
+		if s.revealed[f.Row][f.Col] || s.mines[f.Row][f.Col] {
+			continue
+		}
+		progress = true
+		if mines == 0 {
+			s.reveal(f.Row, f.Col)
+		} else {
+			s.mines[f.Row][f.Col] = true
+			s.minesLeft--
+		}
+	}
+
+	return progress
+}
+
+// difference returns the fields of b that are not in a, ok is false when a is not a subset of b
+func difference(b, a []Position) (rest []Position, ok bool) {
+	in := make(map[Position]bool, len(b))
+	for _, p := range b {
+		in[p] = true
+	}
+	for _, p := range a {
+		if !in[p] {
+			return nil, false
+		}
+		delete(in, p)
+	}
+	for _, p := range b {
+		if in[p] {
+			rest = append(rest, p)
+		}
+	}
+
+	return rest, true
+}
@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"errors"
+)
+
+var (
+	ErrUnknownDurability = errors.New("Unknown durability")
+)
+
+// Durability says how soon the changes of a game get stored when the game service writes behind
+type Durability string
+
+const (
+	DurabilityMove  Durability = "move"  // every move is stored before it is answered
+	DurabilityBatch Durability = "batch" // moves are stored in the next batch of changes
+)
+
+// ParseDurability validates a durability, an empty one means the default of the game
+func ParseDurability(name string) (Durability, error) {
+	switch d := Durability(name); d {
+	case "", DurabilityMove, DurabilityBatch:
+		return d, nil
+	default:
+		return "", ErrUnknownDurability
+	}
+}
+
+// StoreDurability returns the durability of the game, unless it was set ranked games store every
+// move and unranked ones store them in batches
+func (g *Game) StoreDurability() Durability {
+	if g.Durability != "" {
+		return g.Durability
+	}
+	if g.Unranked || g.Rules.Practice {
+		return DurabilityBatch
+	}
+
+	return DurabilityMove
+}
@@ -0,0 +1,296 @@
+package engine
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+)
+
+var (
+	ErrUnsupportedClick = errors.New("Click type not supported by this game type")
+)
+
+// Game types
+const (
+	GameTypeClassic GameType = "classic"
+	GameTypeEndless GameType = "endless"
+)
+
+// Endless boards parameters
+const (
+	EndlessChunkSize         = 16
+	EndlessDefaultChunkMines = 40
+	EndlessMaxChunkMines     = EndlessChunkSize * EndlessChunkSize / 2
+	// endlessMaxReveal caps the fields a single click can open, so an opening can't run forever
+	endlessMaxReveal = 4096
+)
+
+type GameType string
+
+// Chunk is a square piece of an endless board that has been touched by the players
+type Chunk struct {
+	Row     int    `json:"row"`     // chunk row, fields [Row*size, (Row+1)*size)
+	Col     int    `json:"col"`     // chunk col, fields [Col*size, (Col+1)*size)
+	Mines   []byte `json:"mines"`   // bit-packed mine plane
+	Clicked []byte `json:"clicked"` // bit-packed revealed plane
+	Flagged []byte `json:"flagged"` // bit-packed flag plane
+}
+
+// EndlessBoard is an infinite board split in chunks generated from the game seed,
+// only the chunks touched by a reveal or a flag are stored
+type EndlessBoard struct {
+	ChunkSize  int               `json:"chunkSize"`
+	ChunkMines int               `json:"chunkMines"`
+	Chunks     map[string]*Chunk `json:"chunks"`
+	Score      int               `json:"score"` // safe fields revealed
+	// mine planes of chunks not touched yet generated by the move being played, to count mines across a
+	// chunk edge. It only lives as long as the move, which holds the lock of the game, so reading the board
+	// never writes to it.
+	generated map[string][]byte
+}
+
+// IsEndless tells whether the game is played in an endless board
+func (g *Game) IsEndless() bool {
+	return g.Rules.Type == GameTypeEndless
+}
+
+func newEndlessGame(chunkMines int, rules Rules, createdBy string) *Game {
+	if chunkMines < 1 || chunkMines > EndlessMaxChunkMines {
+		chunkMines = EndlessDefaultChunkMines
+	}
+	rules.Arcade = false
+	id, _ := utils.GenerateGUID()
+
+	return &Game{
+		ID:            id,
+		SchemaVersion: StateSchemaVersion,
+		Mines:         chunkMines,
+		Rules:         rules,
+		Seed:          time.Now().UnixNano(),
+		Status:        GameStatusCreated,
+		Endless: &EndlessBoard{
+			ChunkSize:  EndlessChunkSize,
+			ChunkMines: chunkMines,
+			Chunks:     make(map[string]*Chunk),
+		},
+		CreatedAt: time.Now(),
+		CreatedBy: createdBy,
+	}
+}
+
+func chunkKey(row, col int) string {
+	return strconv.Itoa(row) + ":" + strconv.Itoa(col)
+}
+
+// floorDiv divides rounding towards negative infinity, so negative fields land in negative chunks
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+
+	return q
+}
+
+// locate returns the chunk a field belongs to, and the index of the field inside the chunk planes
+func (b *EndlessBoard) locate(row, col int) (chunkRow, chunkCol, index int) {
+	chunkRow, chunkCol = floorDiv(row, b.ChunkSize), floorDiv(col, b.ChunkSize)
+	index = (row-chunkRow*b.ChunkSize)*b.ChunkSize + (col - chunkCol*b.ChunkSize)
+
+	return
+}
+
+// chunkSeed derives the seed of a chunk out of the game seed and the chunk coordinates
+func chunkSeed(seed int64, row, col int) int64 {
+	buf := make([]byte, 24)
+	binary.LittleEndian.PutUint64(buf, uint64(seed))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(int64(row)))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(int64(col)))
+	h := fnv.New64a()
+	_, _ = h.Write(buf)
+
+	return int64(h.Sum64())
+}
+
+// generateMines deterministically places the mines of a chunk, the fields around the origin are always safe
+func (b *EndlessBoard) generateMines(seed int64, chunkRow, chunkCol int) []byte {
+	size := b.ChunkSize
+	mines := newPlane(size * size)
+	rnd := rand.New(rand.NewSource(chunkSeed(seed, chunkRow, chunkCol)))
+	for placed := 0; placed < b.ChunkMines; {
+		i := rnd.Intn(size * size)
+		row, col := chunkRow*size+i/size, chunkCol*size+i%size
+		if getBit(mines, i) || (row >= -1 && row <= 1 && col >= -1 && col <= 1) {
+			continue
+		}
+		setBit(mines, i, true)
+		placed++
+	}
+
+	return mines
+}
+
+// minePlane returns the mine plane of a chunk, whether it has been touched or not. The planes of the chunks
+// not touched are a function of the seed and the chunk, they are only kept while a move is played.
+func (b *EndlessBoard) minePlane(seed int64, chunkRow, chunkCol int) []byte {
+	key := chunkKey(chunkRow, chunkCol)
+	if chunk, found := b.Chunks[key]; found {
+		return chunk.Mines
+	}
+	if plane, found := b.generated[key]; found {
+		return plane
+	}
+	plane := b.generateMines(seed, chunkRow, chunkCol)
+	if b.generated != nil {
+		b.generated[key] = plane
+	}
+
+	return plane
+}
+
+// touch returns the chunk of a field, storing it in the board the first time it is reached
+func (b *EndlessBoard) touch(seed int64, row, col int) (*Chunk, int) {
+	chunkRow, chunkCol, index := b.locate(row, col)
+	key := chunkKey(chunkRow, chunkCol)
+	if chunk, found := b.Chunks[key]; found {
+		return chunk, index
+	}
+	if b.Chunks == nil {
+		b.Chunks = make(map[string]*Chunk)
+	}
+	size := b.ChunkSize * b.ChunkSize
+	chunk := &Chunk{
+		Row:     chunkRow,
+		Col:     chunkCol,
+		Mines:   b.minePlane(seed, chunkRow, chunkCol),
+		Clicked: newPlane(size),
+		Flagged: newPlane(size),
+	}
+	b.Chunks[key] = chunk
+	delete(b.generated, key)
+
+	return chunk, index
+}
+
+// field returns the stored chunk of a field, nil when the chunk was never touched
+func (b *EndlessBoard) field(row, col int) (*Chunk, int) {
+	chunkRow, chunkCol, index := b.locate(row, col)
+
+	return b.Chunks[chunkKey(chunkRow, chunkCol)], index
+}
+
+func (g *Game) endlessMine(row, col int) bool {
+	chunkRow, chunkCol, index := g.Endless.locate(row, col)
+
+	return getBit(g.Endless.minePlane(g.Seed, chunkRow, chunkCol), index)
+}
+
+func (g *Game) endlessAdjCount(row, col int) int {
+	count := 0
+	for _, off := range g.Rules.Kernel.Offsets() {
+		if g.endlessMine(row+off.Row, col+off.Col) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (g *Game) endlessClicked(row, col int) bool {
+	chunk, index := g.Endless.field(row, col)
+
+	return chunk != nil && getBit(chunk.Clicked, index)
+}
+
+func (g *Game) endlessFlagged(row, col int) bool {
+	chunk, index := g.Endless.field(row, col)
+
+	return chunk != nil && getBit(chunk.Flagged, index)
+}
+
+// clickEndless plays a click in an endless board, fields can have any coordinate, even negative ones. The
+// mine planes of the chunks the move reaches without touching them are generated once for the move.
+func (g *Game) clickEndless(clickedBy string, clickType ClickType, row, col int) error {
+	g.Endless.generated = make(map[string][]byte)
+	defer func() {
+		g.Endless.generated = nil
+	}()
+
+	return g.playEndless(clickedBy, clickType, row, col)
+}
+
+func (g *Game) playEndless(clickedBy string, clickType ClickType, row, col int) error {
+	switch clickType {
+	case GameClickTypeFlag:
+		if !g.endlessClicked(row, col) {
+			g.changing(row, col)
+			chunk, index := g.Endless.touch(g.Seed, row, col)
+			setBit(chunk.Flagged, index, true)
+		}
+	case GameClickTypeUnflag:
+		if g.endlessFlagged(row, col) {
+			g.changing(row, col)
+			chunk, index := g.Endless.field(row, col)
+			setBit(chunk.Flagged, index, false)
+		}
+	case GameClickTypeNormal:
+		if g.endlessClicked(row, col) || g.endlessFlagged(row, col) {
+			return nil
+		}
+		if g.endlessMine(row, col) {
+			g.finish(GameStatusDefeat)
+			return ErrDefeat
+		}
+		g.endlessReveal(row, col)
+	case GameClickTypeChord:
+		if !g.endlessClicked(row, col) {
+			return nil
+		}
+		flags := 0
+		for _, off := range g.Rules.Kernel.Offsets() {
+			if g.endlessFlagged(row+off.Row, col+off.Col) {
+				flags++
+			}
+		}
+		if flags == 0 || flags != g.endlessAdjCount(row, col) {
+			return nil
+		}
+		for _, off := range g.Rules.Kernel.Offsets() {
+			if err := g.playEndless(clickedBy, GameClickTypeNormal, row+off.Row, col+off.Col); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrUnsupportedClick
+	}
+
+	return nil
+}
+
+// endlessReveal opens a safe field and floods through the empty ones, chunks get generated as the
+// opening reaches their edges
+func (g *Game) endlessReveal(row, col int) {
+	queue := []Position{{row, col}}
+	for revealed := 0; len(queue) > 0 && revealed < endlessMaxReveal; {
+		pos := queue[0]
+		queue = queue[1:]
+		if g.endlessClicked(pos.Row, pos.Col) || g.endlessFlagged(pos.Row, pos.Col) || g.endlessMine(pos.Row, pos.Col) {
+			continue
+		}
+		g.changing(pos.Row, pos.Col)
+		chunk, index := g.Endless.touch(g.Seed, pos.Row, pos.Col)
+		setBit(chunk.Clicked, index, true)
+		g.Endless.Score++
+		revealed++
+		if g.endlessAdjCount(pos.Row, pos.Col) == 0 {
+			for _, off := range g.Rules.Kernel.Offsets() {
+				queue = append(queue, Position{pos.Row + off.Row, pos.Col + off.Col})
+			}
+		}
+	}
+}
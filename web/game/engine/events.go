@@ -0,0 +1,231 @@
+package engine
+
+import (
+	"time"
+)
+
+// Types of the events of a game
+const (
+	EventCellRevealed EventType = "cell.revealed"
+	EventFlagChanged  EventType = "flag.changed"
+	EventGameStarted  EventType = "game.started"
+	EventGameFinished EventType = "game.finished"
+	EventPlayerJoined EventType = "player.joined"
+	EventResync       EventType = "game.resync" // events were missed, the game has to be read again
+)
+
+type EventType string
+
+// CellDelta is the new state of a field that changed, the content of a field is only told once revealed
+type CellDelta struct {
+	Row       int    `json:"row"`
+	Col       int    `json:"col"`
+	Revealed  bool   `json:"revealed"`
+	Flagged   bool   `json:"flagged"`
+	Mine      bool   `json:"mine,omitempty"`
+	AdjMines  int    `json:"adjMines,omitempty"`
+	Fogged    bool   `json:"fogged,omitempty"` // revealed out of sight of the player, its count is hidden
+	ClickedBy string `json:"clickedBy,omitempty"`
+}
+
+// Event is something that happened in a game, sent to whoever is watching it
+type Event struct {
+	Seq     uint64      `json:"seq"` // position of the event in the events of the game, starting at 1
+	Type    EventType   `json:"type"`
+	GameID  string      `json:"gameID"`
+	Version uint64      `json:"version,omitempty"` // version of the game after the event
+	Player  string      `json:"player,omitempty"`  // who caused the event
+	Cells   []CellDelta `json:"cells,omitempty"`
+	Status  GameStatus  `json:"status,omitempty"`
+	At      time.Time   `json:"at"`
+}
+
+// Delta is what changed in a game, like the fields and the status a move changed
+type Delta struct {
+	GameID         string      `json:"gameID"`
+	Version        uint64      `json:"version"` // version of the game after the changes
+	Move           *Move       `json:"move,omitempty"`
+	Cells          []CellDelta `json:"cells"`
+	Status         GameStatus  `json:"status"`
+	PreviousStatus GameStatus  `json:"previousStatus,omitempty"` // status before the changes, when they changed it
+}
+
+// change is a field a move changed, with the state it had before
+type change struct {
+	Position
+	clicked, flagged bool
+}
+
+// track starts collecting the fields the game changes, until its delta is taken
+func (g *Game) track() {
+	g.changes = []change{}
+}
+
+// changing records a field about to change, while the game is tracking its changes
+func (g *Game) changing(row, col int) {
+	if g.changes == nil {
+		return
+	}
+	clicked, flagged := g.fieldState(row, col)
+	g.changes = append(g.changes, change{Position{row, col}, clicked, flagged})
+}
+
+func (g *Game) fieldState(row, col int) (clicked, flagged bool) {
+	if g.IsEndless() {
+		return g.endlessClicked(row, col), g.endlessFlagged(row, col)
+	}
+
+	return g.MineField[row][col].Clicked, g.MineField[row][col].Flagged
+}
+
+// delta returns the fields and the status that changed since the game started tracking its changes, and
+// stops tracking them. Fields left as they were are not told.
+func (g *Game) delta(previous GameStatus) *Delta {
+	delta := &Delta{
+		GameID:  g.ID,
+		Version: g.Version,
+		Cells:   make([]CellDelta, 0, len(g.changes)),
+		Status:  g.Status,
+	}
+	seen := make(map[Position]bool, len(g.changes))
+	for _, c := range g.changes {
+		if seen[c.Position] {
+			continue
+		}
+		seen[c.Position] = true
+		clicked, flagged := g.fieldState(c.Row, c.Col)
+		if clicked == c.clicked && flagged == c.flagged {
+			continue
+		}
+		if g.IsEndless() {
+			delta.Cells = append(delta.Cells, g.endlessDelta(c.Row, c.Col, clicked, flagged))
+		} else {
+			delta.Cells = append(delta.Cells, fieldDelta(g.MineField[c.Row][c.Col]))
+		}
+	}
+	g.changes = nil
+	if g.Status != previous {
+		delta.PreviousStatus = previous
+	}
+
+	return delta
+}
+
+func fieldDelta(field Field) CellDelta {
+	delta := CellDelta{
+		Row:       field.Position.Row,
+		Col:       field.Position.Col,
+		Revealed:  field.Clicked && !field.Flagged,
+		Flagged:   field.Flagged,
+		ClickedBy: field.ClickedBy,
+	}
+	if delta.Revealed {
+		delta.Mine = field.Mine
+		delta.AdjMines = field.AdjCount
+	}
+
+	return delta
+}
+
+func (g *Game) endlessDelta(row, col int, clicked, flagged bool) CellDelta {
+	delta := CellDelta{Row: row, Col: col, Revealed: clicked, Flagged: flagged}
+	if clicked {
+		delta.AdjMines = g.endlessAdjCount(row, col)
+	}
+
+	return delta
+}
+
+// Cells returns every field of classic boards, or the fields played of endless boards, as the player is
+// allowed to see them
+func (g *Game) Cells(player string) (cells []CellDelta) {
+	if g.IsEndless() {
+		size := g.Endless.ChunkSize * g.Endless.ChunkSize
+		for _, chunk := range g.Endless.Chunks {
+			for i := 0; i < size; i++ {
+				clicked, flagged := getBit(chunk.Clicked, i), getBit(chunk.Flagged, i)
+				if !clicked && !flagged {
+					continue
+				}
+				row := chunk.Row*g.Endless.ChunkSize + i/g.Endless.ChunkSize
+				col := chunk.Col*g.Endless.ChunkSize + i%g.Endless.ChunkSize
+				cells = append(cells, g.endlessDelta(row, col, clicked, flagged))
+			}
+		}
+		return g.fogCells(player, cells)
+	}
+	for i := range g.MineField {
+		for _, field := range g.MineField[i] {
+			cells = append(cells, fieldDelta(field))
+		}
+	}
+
+	return g.fogCells(player, cells)
+}
+
+// Events returns the events of the changes the player made
+func (d *Delta) Events(player string) (events []Event) {
+	now := time.Now()
+	var revealed, flagged []CellDelta
+	for _, cell := range d.Cells {
+		if cell.Revealed {
+			revealed = append(revealed, cell)
+		} else {
+			flagged = append(flagged, cell)
+		}
+	}
+	event := Event{GameID: d.GameID, Version: d.Version, Player: player, At: now}
+	if len(revealed) > 0 {
+		e := event
+		e.Type, e.Cells = EventCellRevealed, revealed
+		events = append(events, e)
+	}
+	if len(flagged) > 0 {
+		e := event
+		e.Type, e.Cells = EventFlagChanged, flagged
+		events = append(events, e)
+	}
+	if d.PreviousStatus != "" {
+		e := event
+		e.Type, e.Status = EventGameFinished, d.Status
+		if d.Status == GameStatusStarted {
+			e.Type = EventGameStarted
+		}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+// EventFor returns the event as the player is allowed to see it, in fog games the counts of the fields
+// out of sight of the player are hidden
+func (g *Game) EventFor(player string, e Event) Event {
+	e.Cells = g.fogCells(player, e.Cells)
+
+	return e
+}
+
+// DeltaFor returns the delta as the player is allowed to see it, like EventFor
+func (g *Game) DeltaFor(player string, d *Delta) *Delta {
+	view := *d
+	view.Cells = g.fogCells(player, d.Cells)
+
+	return &view
+}
+
+func (g *Game) fogCells(player string, cells []CellDelta) []CellDelta {
+	if g.Rules.Fog == nil || len(cells) == 0 {
+		return cells
+	}
+	visible := g.visibility(player)
+	fogged := make([]CellDelta, len(cells))
+	for i, cell := range cells {
+		if cell.Revealed && !cell.Mine && !visible(cell.Row, cell.Col) {
+			cell.AdjMines = 0
+			cell.Fogged = true
+		}
+		fogged[i] = cell
+	}
+
+	return fogged
+}
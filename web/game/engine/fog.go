@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"time"
+)
+
+// Fog defaults
+const (
+	FogDefaultRadius = 2
+	FogDefaultMemory = 3
+)
+
+// FogRules limits what the players see: only the fields around their last reveals show their numbers,
+// older revealed areas fade back to remembered, with their counts hidden
+type FogRules struct {
+	Radius int `json:"radius"` // fields around a reveal the player can see
+	Memory int `json:"memory"` // how many of the player's last reveals light the board
+}
+
+// NewFogRules returns the fog rules, using the defaults for the missing values
+func NewFogRules(radius, memory int) *FogRules {
+	if radius < 1 {
+		radius = FogDefaultRadius
+	}
+	if memory < 1 {
+		memory = FogDefaultMemory
+	}
+
+	return &FogRules{Radius: radius, Memory: memory}
+}
+
+// GameView is what a player sees of a game
+type GameView struct {
+	ID         string     `json:"id"`
+	Rows       int        `json:"rows"`
+	Cols       int        `json:"cols"`
+	Mines      int        `json:"mines"`
+	Rules      Rules      `json:"rules"`
+	Status     GameStatus `json:"status"`
+	Version    uint64     `json:"version"`
+	Board      *Viewport  `json:"board"`
+	History    []Move     `json:"history,omitempty"`
+	Inventory  Inventory  `json:"inventory,omitempty"` // arcade items of the player
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	CreatedBy  string     `json:"createdBy"`
+	Code       string     `json:"code,omitempty"` // board code, shown once the game is finished
+	Unranked   bool       `json:"unranked,omitempty"`
+}
+
+// Project returns the game as seen by the player, classic games show the whole board while endless
+// games show a window around the last reveal of the player
+func (g *Game) Project(player string) *GameView {
+	view := &GameView{
+		ID:         g.ID,
+		Rows:       g.Rows,
+		Cols:       g.Cols,
+		Mines:      g.Mines,
+		Rules:      g.Rules,
+		Status:     g.Status,
+		Version:    g.Version,
+		History:    g.History,
+		Inventory:  g.Inventories[player],
+		StartedAt:  g.StartedAt,
+		FinishedAt: g.FinishedAt,
+		CreatedAt:  g.CreatedAt,
+		CreatedBy:  g.CreatedBy,
+		Code:       g.Code,
+		Unranked:   g.Unranked,
+	}
+	if g.IsEndless() {
+		var center Position
+		if reveals := g.lastReveals(player, 1); len(reveals) > 0 {
+			center = Position{reveals[0].Row, reveals[0].Col}
+		}
+		view.Board = g.Viewport(player, center.Row-ViewportDefaultSize/2, center.Col-ViewportDefaultSize/2, 0, 0)
+	} else {
+		view.Board = g.Viewport(player, 0, 0, g.Rows, g.Cols)
+	}
+
+	return view
+}
+
+// lastReveals returns the last n moves of the player that revealed fields, the most recent first
+func (g *Game) lastReveals(player string, n int) []Move {
+	reveals := make([]Move, 0, n)
+	for i := len(g.History) - 1; i >= 0 && len(reveals) < n; i-- {
+		move := g.History[i]
+		if move.Player != player {
+			continue
+		}
+		switch move.Action {
+		case ClickType(GameClickTypeNormal).String(), ClickType(GameClickTypeChord).String(),
+			ClickType(GameClickTypeDetector).String():
+			reveals = append(reveals, move)
+		}
+	}
+
+	return reveals
+}
+
+// visibility returns whether the player can see the number of a revealed field. Fields are visible
+// when they are within the fog radius of one of the last reveals of the player, or when one of those
+// reveals opened them. Without fog, or once the game is over, everything is visible.
+func (g *Game) visibility(player string) func(row, col int) bool {
+	if g.Rules.Fog == nil || (!g.IsActive() && g.Status != GameStatusCreated) {
+		return func(row, col int) bool { return true }
+	}
+	reveals := g.lastReveals(player, g.Rules.Fog.Memory)
+	radius := g.Rules.Fog.Radius
+
+	return func(row, col int) bool {
+		for _, move := range reveals {
+			if abs(move.Row-row) <= radius && abs(move.Col-col) <= radius {
+				return true
+			}
+		}
+		if len(reveals) > 0 && !g.IsEndless() {
+			revealedAt := g.MineField[row][col].RevealedAt
+			oldest := reveals[len(reveals)-1].At
+			return revealedAt != nil && !revealedAt.Before(oldest)
+		}
+
+		return false
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
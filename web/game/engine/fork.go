@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+)
+
+// Copy returns a deep copy of the game, to read it once the lock it is played under is released
+func (g *Game) Copy() *Game {
+	c := *g
+	c.changes = nil
+	if g.Rules.Fog != nil {
+		fog := *g.Rules.Fog
+		c.Rules.Fog = &fog
+	}
+	if g.MineField != nil {
+		c.MineField = make([][]Field, len(g.MineField))
+		for i, row := range g.MineField {
+			c.MineField[i] = append([]Field(nil), row...)
+		}
+	}
+	if g.Endless != nil {
+		endless := *g.Endless
+		endless.generated = nil
+		endless.Chunks = make(map[string]*Chunk, len(g.Endless.Chunks))
+		for key, chunk := range g.Endless.Chunks {
+			endless.Chunks[key] = &Chunk{
+				Row:     chunk.Row,
+				Col:     chunk.Col,
+				Mines:   append([]byte(nil), chunk.Mines...),
+				Clicked: append([]byte(nil), chunk.Clicked...),
+				Flagged: append([]byte(nil), chunk.Flagged...),
+			}
+		}
+		c.Endless = &endless
+	}
+	if g.Difficulty != nil {
+		difficulty := *g.Difficulty
+		c.Difficulty = &difficulty
+	}
+	if g.History != nil {
+		c.History = append([]Move(nil), g.History...)
+	}
+	if g.Inventories != nil {
+		c.Inventories = make(map[string]Inventory, len(g.Inventories))
+		for player, inventory := range g.Inventories {
+			c.Inventories[player] = make(Inventory, len(inventory))
+			for item, count := range inventory {
+				c.Inventories[player][item] = count
+			}
+		}
+	}
+
+	return &c
+}
+
+// Fork copies the game into a new unranked game owned by createdBy: the board, the moves and the time
+// played so far. Forks of lost games continue from right before the losing move, to try another one.
+func (g *Game) Fork(createdBy string) (*Game, error) {
+	b, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	var fork Game
+	if err = json.Unmarshal(b, &fork); err != nil {
+		return nil, err
+	}
+	fork.ID, _ = utils.GenerateGUID()
+	fork.ForkedFrom = g.ID
+	fork.PreviousAttemptID, fork.FirstAttemptID = "", ""
+	fork.Unranked = true
+	fork.CreatedBy = createdBy
+	fork.CreatedAt = time.Now()
+	if g.StartedAt != nil {
+		// The timer keeps running from the time played in the original game
+		end := time.Now()
+		if g.FinishedAt != nil {
+			end = *g.FinishedAt
+		}
+		startedAt := time.Now().Add(-end.Sub(*g.StartedAt))
+		fork.StartedAt = &startedAt
+	}
+	if fork.Status == GameStatusDefeat {
+		fork.undoDefeat()
+	}
+
+	return &fork, nil
+}
+
+// undoDefeat reopens a lost game, hiding again the mine that exploded
+func (g *Game) undoDefeat() {
+	g.Status = GameStatusStarted
+	g.FinishedAt = nil
+	g.Code = ""
+	for i := len(g.History) - 1; i >= 0; i-- {
+		move := g.History[i]
+		if move.Result != MoveResultDefeat {
+			continue
+		}
+		g.History[i].Result = MoveResultUndone
+		if !g.IsEndless() && move.Row >= 0 && move.Row < g.Rows && move.Col >= 0 && move.Col < g.Cols {
+			g.hideExploded(move.Row, move.Col)
+		}
+		break
+	}
+}
+
+// hideExploded hides the mine a defeat revealed, chords explode on one of the neighbours of the move
+func (g *Game) hideExploded(row, col int) {
+	candidates := append([]Position{{row, col}}, g.neighbours(row, col)...)
+	for _, pos := range candidates {
+		field := &g.MineField[pos.Row][pos.Col]
+		if field.Mine && field.Clicked && !field.Flagged {
+			field.Clicked = false
+			field.ClickedBy = ""
+			field.RevealedAt = nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"time"
+)
+
+// Results of a move recorded in the game history
+const (
+	MoveResultDefeat   = "defeat"
+	MoveResultAwarded  = "awarded"
+	MoveResultAbsorbed = "absorbed"
+	MoveResultMine     = "mine"
+	MoveResultSafe     = "safe"
+	MoveResultUndone   = "undone" // losing move taken back by forking the game
+)
+
+// Move is an entry of the game history
+type Move struct {
+	Seq    int       `json:"seq"`              // position of the move in the history, starting at 1
+	Player string    `json:"player"`           // who played the move
+	Action string    `json:"action"`           // click type name, or the item event
+	Row    int       `json:"row"`              // row of the field the move was played on
+	Col    int       `json:"col"`              // col of the field the move was played on
+	Item   Item      `json:"item,omitempty"`   // item used or awarded by the move
+	Result string    `json:"result,omitempty"` // outcome of the move, if any
+	At     time.Time `json:"at"`
+}
+
+// String returns the name of the click type, as used by the API and the history
+func (ct ClickType) String() string {
+	switch ct {
+	case GameClickTypeNormal:
+		return "click"
+	case GameClickTypeFlag:
+		return "flag"
+	case GameClickTypeUnflag:
+		return "unflag"
+	case GameClickTypeReveal:
+		return "reveal"
+	case GameClickTypeChord:
+		return "chord"
+	case GameClickTypeRadar:
+		return string(ItemRadar)
+	case GameClickTypeDetector:
+		return string(ItemDetector)
+	default:
+		return "unknown"
+	}
+}
+
+// record appends a move to the game history and returns its index
+func (g *Game) record(player, action string, row, col int, item Item, result string) int {
+	g.History = append(g.History, Move{
+		Seq:    len(g.History) + 1,
+		Player: player,
+		Action: action,
+		Row:    row,
+		Col:    col,
+		Item:   item,
+		Result: result,
+		At:     time.Now(),
+	})
+
+	return len(g.History) - 1
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+)
+
+var (
+	ErrNotArcade = errors.New("Items are only available in arcade mode")
+	ErrNoItem    = errors.New("Item not available in the player inventory")
+	ErrRevealed  = errors.New("Field already revealed")
+	ErrFlagged   = errors.New("Field flagged, its flag has to be taken off first")
+)
+
+// Item is a power-up awarded in arcade mode
+type Item string
+
+const (
+	ItemRadar    Item = "radar"    // reveals the mine count of a 3x3 area
+	ItemShield   Item = "shield"   // absorbs one explosion
+	ItemDetector Item = "detector" // safely tests one field
+)
+
+// arcadeItemRatio is the amount of safe fields for each item placed in the board
+const arcadeItemRatio = 15
+
+var arcadeItems = []Item{ItemRadar, ItemShield, ItemDetector}
+
+// Inventory counts the items a player holds
+type Inventory map[Item]int
+
+// placeItems hides arcade items under random safe fields
+func (g *Game) placeItems(rnd *rand.Rand) {
+	safe := g.Rows*g.Cols - g.Mines
+	if safe < 1 {
+		return
+	}
+	items := safe / arcadeItemRatio
+	if items < 1 {
+		items = 1
+	}
+	for placed := 0; placed < items; {
+		row, col := rnd.Intn(g.Rows), rnd.Intn(g.Cols)
+		field := &g.MineField[row][col]
+		if field.Mine || field.Item != "" {
+			continue
+		}
+		field.Item = arcadeItems[rnd.Intn(len(arcadeItems))]
+		placed++
+	}
+}
+
+// awardItem moves the item hidden under a revealed field to the player inventory
+func (g *Game) awardItem(player string, row, col int) {
+	item := g.MineField[row][col].Item
+	if item == "" {
+		return
+	}
+	g.MineField[row][col].Item = ""
+	if g.Inventories == nil {
+		g.Inventories = make(map[string]Inventory)
+	}
+	if g.Inventories[player] == nil {
+		g.Inventories[player] = make(Inventory)
+	}
+	g.Inventories[player][item]++
+	g.record(player, "award", row, col, item, MoveResultAwarded)
+}
+
+// consumeItem takes an item out of the player inventory, it returns false when the player has none
+func (g *Game) consumeItem(player string, item Item) bool {
+	if g.Inventories[player][item] < 1 {
+		return false
+	}
+	g.Inventories[player][item]--
+
+	return true
+}
+
+// absorbExplosion spends a shield of the player to survive clicking a mine, the mine gets flagged
+func (g *Game) absorbExplosion(player string, row, col int) bool {
+	if !g.Rules.Arcade || !g.consumeItem(player, ItemShield) {
+		return false
+	}
+	g.changing(row, col)
+	g.MineField[row][col].Clicked = true
+	g.MineField[row][col].Flagged = true
+	g.MineField[row][col].ClickedBy = player
+	g.record(player, string(ItemShield), row, col, ItemShield, MoveResultAbsorbed)
+
+	return true
+}
+
+// useItem plays an item of the player inventory on a field
+func (g *Game) useItem(player string, clickType ClickType, row, col int) error {
+	if !g.Rules.Arcade {
+		return ErrNotArcade
+	}
+	item := Item(clickType.String())
+	// Detecting a revealed field tells nothing and a flagged one can't be revealed, the detector is kept
+	if item == ItemDetector && g.MineField[row][col].Flagged {
+		return ErrFlagged
+	}
+	if item == ItemDetector && g.MineField[row][col].Clicked {
+		return ErrRevealed
+	}
+	if !g.consumeItem(player, item) {
+		return ErrNoItem
+	}
+	switch item {
+	case ItemRadar:
+		mines := 0
+		for i := row - 1; i <= row+1; i++ {
+			for j := col - 1; j <= col+1; j++ {
+				if i >= 0 && i < g.Rows && j >= 0 && j < g.Cols && g.MineField[i][j].Mine {
+					mines++
+				}
+			}
+		}
+		g.record(player, string(item), row, col, item, strconv.Itoa(mines))
+	case ItemDetector:
+		field := &g.MineField[row][col]
+		if !field.Mine {
+			g.record(player, string(item), row, col, item, MoveResultSafe)
+			return g.click(player, GameClickTypeNormal, row, col)
+		}
+		g.changing(row, col)
+		field.Clicked = true
+		field.Flagged = true
+		field.ClickedBy = player
+		g.record(player, string(item), row, col, item, MoveResultMine)
+	}
+
+	return nil
+}
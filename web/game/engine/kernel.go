@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"errors"
+)
+
+var (
+	ErrUnknownKernel = errors.New("Unknown neighbourhood kernel")
+)
+
+// Kernel names the neighbourhood used to count mines, auto-reveal and chord fields
+type Kernel string
+
+const (
+	KernelMoore      Kernel = "moore"      // the 8 surrounding fields, classic minesweeper
+	KernelKnight     Kernel = "knight"     // the 8 fields a chess knight can jump to
+	KernelManhattan2 Kernel = "manhattan2" // every field within a Manhattan distance of 2
+)
+
+var kernelOffsets = map[Kernel][]Position{
+	KernelMoore: {
+		{-1, -1}, {-1, 0}, {-1, 1},
+		{0, -1}, {0, 1},
+		{1, -1}, {1, 0}, {1, 1},
+	},
+	KernelKnight: {
+		{-2, -1}, {-2, 1},
+		{-1, -2}, {-1, 2},
+		{1, -2}, {1, 2},
+		{2, -1}, {2, 1},
+	},
+	KernelManhattan2: {
+		{-2, 0},
+		{-1, -1}, {-1, 0}, {-1, 1},
+		{0, -2}, {0, -1}, {0, 1}, {0, 2},
+		{1, -1}, {1, 0}, {1, 1},
+		{2, 0},
+	},
+}
+
+// ParseKernel returns the kernel with the given name, an empty name means the classic Moore kernel
+func ParseKernel(name string) (Kernel, error) {
+	if name == "" {
+		return KernelMoore, nil
+	}
+	if _, found := kernelOffsets[Kernel(name)]; !found {
+		return "", ErrUnknownKernel
+	}
+
+	return Kernel(name), nil
+}
+
+// Offsets returns the relative positions of the fields adjacent to a field,
+// games stored before kernels existed have no kernel and fall back to Moore
+func (k Kernel) Offsets() []Position {
+	if offsets, found := kernelOffsets[k]; found {
+		return offsets
+	}
+
+	return kernelOffsets[KernelMoore]
+}
+
+// neighbours returns the positions inside the board adjacent to [row, col] for the game kernel
+func (g *Game) neighbours(row, col int) []Position {
+	offsets := g.Rules.Kernel.Offsets()
+	positions := make([]Position, 0, len(offsets))
+	for _, off := range offsets {
+		r, c := row+off.Row, col+off.Col
+		if r >= 0 && r < g.Rows && c >= 0 && c < g.Cols {
+			positions = append(positions, Position{r, c})
+		}
+	}
+
+	return positions
+}
+
+// countAdjacentMines sets the AdjCount of every field that is not a mine
+func (g *Game) countAdjacentMines() {
+	for i := 0; i < g.Rows; i++ {
+		for j := 0; j < g.Cols; j++ {
+			g.MineField[i][j].AdjCount = 0
+			if g.MineField[i][j].Mine {
+				continue
+			}
+			for _, n := range g.neighbours(i, j) {
+				if g.MineField[n.Row][n.Col].Mine {
+					g.MineField[i][j].AdjCount++
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"time"
+)
+
+// Statuses of games ended without a victory or a defeat
+const (
+	GameStatusForfeited = "forfeited" // the player gave up, the board gets revealed
+	GameStatusAbandoned = "abandoned" // nobody played the game for too long
+)
+
+// IsFinished tells whether the game reached a terminal status
+func (g *Game) IsFinished() bool {
+	switch g.Status {
+	case GameStatusVictory, GameStatusDefeat, GameStatusForfeited, GameStatusAbandoned:
+		return true
+	}
+
+	return false
+}
+
+// Forfeit gives up the game and reveals the whole board, it returns the fields and the status it changed
+func (g *Game) Forfeit() (*Delta, error) {
+	if g.IsFinished() {
+		return nil, ErrNotActive
+	}
+	status := g.Status
+	g.track()
+	now := time.Now()
+	for i := range g.MineField {
+		for j := range g.MineField[i] {
+			field := &g.MineField[i][j]
+			if !field.Clicked && !field.Mine {
+				g.changing(i, j)
+				field.Clicked = true
+				field.RevealedAt = &now
+			}
+		}
+	}
+	g.finish(GameStatusForfeited)
+	g.Version++
+
+	return g.delta(status), nil
+}
+
+// Abandon ends a game nobody is playing anymore, the board is left as it was
+func (g *Game) Abandon() error {
+	if g.IsFinished() {
+		return ErrNotActive
+	}
+	g.finish(GameStatusAbandoned)
+	g.Version++
+
+	return nil
+}
+
+// LastActivity returns when the game was last played: its last move, its start or its creation
+func (g *Game) LastActivity() time.Time {
+	if len(g.History) > 0 {
+		return g.History[len(g.History)-1].At
+	}
+	if g.StartedAt != nil {
+		return *g.StartedAt
+	}
+
+	return g.CreatedAt
+}
+
+// IsIdle tells whether a started game has not been played for longer than timeout
+func (g *Game) IsIdle(timeout time.Duration) bool {
+	return g.Status == GameStatusStarted && time.Since(g.LastActivity()) > timeout
+}
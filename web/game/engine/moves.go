@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"errors"
+)
+
+// Outcomes of the moves of a batch
+const (
+	MoveOutcomePlayed  = "played"
+	MoveOutcomeFailed  = "failed"  // the move couldn't be played, the next ones still are
+	MoveOutcomeSkipped = "skipped" // a previous move ended the game
+)
+
+// PlannedMove is a move to play as part of a batch
+type PlannedMove struct {
+	ClickType ClickType
+	Row       int
+	Col       int
+}
+
+// MoveOutcome is what a move of a batch did
+type MoveOutcome struct {
+	Index   int    `json:"index"` // position of the move in the batch
+	Outcome string `json:"outcome"`
+	Delta   *Delta `json:"delta,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MovesResult is what a batch of moves did to a game
+type MovesResult struct {
+	GameID  string        `json:"gameID"`
+	Version uint64        `json:"version"`
+	Status  GameStatus    `json:"status"`
+	Moves   []MoveOutcome `json:"moves"`
+}
+
+// PlayMoves plays the moves of the player in order, until one of them ends the game and the ones left
+// get skipped. Moves that can't be played are reported and the batch goes on.
+func (g *Game) PlayMoves(player string, moves []PlannedMove) *MovesResult {
+	result := &MovesResult{GameID: g.ID, Moves: make([]MoveOutcome, len(moves))}
+	for i, move := range moves {
+		outcome := &result.Moves[i]
+		outcome.Index = i
+		if g.IsFinished() {
+			outcome.Outcome = MoveOutcomeSkipped
+			continue
+		}
+		delta, err := g.Click(player, move.ClickType, move.Row, move.Col)
+		outcome.Delta = delta
+		if delta != nil {
+			outcome.Outcome = MoveOutcomePlayed
+		} else {
+			outcome.Outcome = MoveOutcomeFailed
+		}
+		if err != nil && !errors.Is(err, ErrDefeat) {
+			outcome.Error = err.Error()
+		}
+	}
+	result.Version = g.Version
+	result.Status = g.Status
+
+	return result
+}
+
+// MovesFor returns the result of a batch as the player is allowed to see it, like DeltaFor
+func (g *Game) MovesFor(player string, r *MovesResult) *MovesResult {
+	view := *r
+	view.Moves = make([]MoveOutcome, len(r.Moves))
+	for i, outcome := range r.Moves {
+		if outcome.Delta != nil {
+			outcome.Delta = g.DeltaFor(player, outcome.Delta)
+		}
+		view.Moves[i] = outcome
+	}
+
+	return &view
+}
@@ -10,8 +10,10 @@ import (
 )
 
 var (
-	ErrDefeat    = errors.New("Defeat")
-	ErrNotActive = errors.New("Game not active")
+	ErrDefeat       = errors.New("Defeat")
+	ErrNotActive    = errors.New("Game not active")
+	ErrNotStartable = errors.New("Game can't be started")
+	ErrOutOfBounds  = errors.New("Field out of bounds")
 )
 
 // Some default game parameters, if the user does not provide those.
@@ -27,6 +29,12 @@ const (
 	GameClickTypeNormal = 1
 	GameClickTypeFlag   = 2
 	GameClickTypeReveal = 3
+	GameClickTypeChord  = 4
+	// Arcade mode item uses
+	GameClickTypeRadar    = 5
+	GameClickTypeDetector = 6
+	// Takes the flag off a field
+	GameClickTypeUnflag = 7
 )
 
 type GameStatus string
@@ -38,28 +46,56 @@ type Position struct {
 	Col int `json:"col"` // col of the field position
 }
 
-//Field represents a square unit in the MineField
+// Field represents a square unit in the MineField
 type Field struct {
-	Mine      bool     `json:"mine"`
-	Clicked   bool     `json:"clicked"`   // indicated whether the field was clicked
-	Flagged   bool     `json:"flagged"`   // red flag in the field
-	AdjCount  int      `json:"adjMines"`  // count of adjacent mines
-	Position  Position `json:"position"`  // position in the minefield
-	ClickedBy string   `json:"clickedBy"` // who clicked this field
+	Mine       bool       `json:"mine"`
+	Clicked    bool       `json:"clicked"`              // indicated whether the field was clicked
+	Flagged    bool       `json:"flagged"`              // red flag in the field
+	AdjCount   int        `json:"adjMines"`             // count of adjacent mines
+	Position   Position   `json:"position"`             // position in the minefield
+	ClickedBy  string     `json:"clickedBy"`            // who clicked this field
+	RevealedAt *time.Time `json:"revealedAt,omitempty"` // when the field was revealed
+	Item       Item       `json:"item,omitempty"`       // arcade item awarded to whoever reveals this field
+}
+
+// Rules groups the rules a game is played with, they are stored along the game
+type Rules struct {
+	Type     GameType  `json:"type,omitempty"`     // classic when empty
+	Kernel   Kernel    `json:"kernel"`             // neighbourhood used to count, reveal and chord fields
+	Arcade   bool      `json:"arcade,omitempty"`   // revealing some fields awards items to the player
+	Fog      *FogRules `json:"fog,omitempty"`      // players only see around their last reveals
+	Tier     string    `json:"tier,omitempty"`     // difficulty tier the board has to be generated in
+	Practice bool      `json:"practice,omitempty"` // restored from a save slot, never ranked
 }
 
 // Game contains the structure of the game
 type Game struct {
-	ID         string     `json:"id"`
-	Rows       int        `json:"rows"`
-	Cols       int        `json:"cols"`
-	Mines      int        `json:"mines"`
-	Status     GameStatus `json:"status"`
-	MineField  [][]Field  `json:"mineField"`
-	StartedAt  *time.Time `json:"startedAt,omitempty"`
-	FinishedAt *time.Time `json:"finishedAt,omitempty"`
-	CreatedAt  time.Time  `json:"createdAt"`
-	CreatedBy  string     `json:"createdBy"` // who created this game
+	ID                string               `json:"id"`
+	SchemaVersion     int                  `json:"schemaVersion"` // version of the shape of this struct when stored
+	Version           uint64               `json:"version"`       // version of the state, every change increases it
+	Rows              int                  `json:"rows"`
+	Cols              int                  `json:"cols"`
+	Mines             int                  `json:"mines"`
+	Rules             Rules                `json:"rules"`
+	Status            GameStatus           `json:"status"`
+	MineField         [][]Field            `json:"mineField"`
+	Endless           *EndlessBoard        `json:"endless,omitempty"` // board of endless games, which have no MineField
+	Seed              int64                `json:"seed,omitempty"`    // seed the board was generated from
+	Difficulty        *Difficulty          `json:"difficulty,omitempty"`
+	History           []Move               `json:"history,omitempty"`     // every move played in the game
+	Inventories       map[string]Inventory `json:"inventories,omitempty"` // arcade items held by each player
+	StartedAt         *time.Time           `json:"startedAt,omitempty"`
+	FinishedAt        *time.Time           `json:"finishedAt,omitempty"`
+	CreatedAt         time.Time            `json:"createdAt"`
+	CreatedBy         string               `json:"createdBy"`                   // who created this game
+	Code              string               `json:"code,omitempty"`              // board code, shown once the game is finished
+	Unranked          bool                 `json:"unranked,omitempty"`          // forked and restored games don't count for rankings
+	ForkedFrom        string               `json:"forkedFrom,omitempty"`        // game this one was forked from
+	PreviousAttemptID string               `json:"previousAttemptID,omitempty"` // attempt at the same board this one retries
+	FirstAttemptID    string               `json:"firstAttemptID,omitempty"`    // first attempt at the same board
+	Durability        Durability           `json:"durability,omitempty"`        // how soon changes get stored, see StoreDurability
+
+	changes []change // fields changed by the move being played
 }
 
 func (g *Game) Start() error {
@@ -67,21 +103,68 @@ func (g *Game) Start() error {
 		g.Status = GameStatusStarted
 		now := time.Now()
 		g.StartedAt = &now
+		g.Version++
 		return nil
 	}
-	return fmt.Errorf("Cannot start a game that is in status: %s", g.Status)
+	return fmt.Errorf("%w, it is in status: %s", ErrNotStartable, g.Status)
 }
 
-func (g *Game) Click(clickedBy string, clickType ClickType, row, col int) error {
+// Click plays a move of the player in the game and records it in the game history, it returns what the
+// move changed: the fields, the status and the version of the game. Moves that couldn't be played return
+// no delta, a losing move returns its delta along ErrDefeat and the move revealing the last safe field
+// wins the game.
+func (g *Game) Click(clickedBy string, clickType ClickType, row, col int) (*Delta, error) {
 	if !g.IsActive() {
-		return ErrNotActive
+		return nil, ErrNotActive
 	}
-	if row < 0 || row > g.Rows || col < 0 || col > g.Cols {
-		return fmt.Errorf("Field [%d, %d] out of bounds", row, col)
+	if !g.IsEndless() && (row < 0 || row >= g.Rows || col < 0 || col >= g.Cols) {
+		return nil, fmt.Errorf("%w: [%d, %d]", ErrOutOfBounds, row, col)
+	}
+	status, moves := g.Status, len(g.History)
+	g.track()
+	var err error
+	if clickType == GameClickTypeRadar || clickType == GameClickTypeDetector {
+		err = g.useItem(clickedBy, clickType, row, col)
+	} else {
+		move := g.record(clickedBy, clickType.String(), row, col, "", "")
+		err = g.click(clickedBy, clickType, row, col)
+		if errors.Is(err, ErrDefeat) {
+			g.History[move].Result = MoveResultDefeat
+		}
+	}
+	if err == nil {
+		g.checkVictory()
+	}
+	if len(g.History) == moves {
+		g.changes = nil
+		return nil, err
+	}
+	g.Version++
+	delta := g.delta(status)
+	move := g.History[moves]
+	delta.Move = &move
+
+	return delta, err
+}
+
+func (g *Game) click(clickedBy string, clickType ClickType, row, col int) error {
+	if g.IsEndless() {
+		return g.clickEndless(clickedBy, clickType, row, col)
+	}
+	if clickType == GameClickTypeChord {
+		return g.chord(clickedBy, row, col)
+	}
+	if clickType == GameClickTypeUnflag {
+		g.unflag(row, col)
+		return nil
 	}
 	if g.MineField[row][col].Clicked && clickType != GameClickTypeReveal {
 		return nil
 	}
+	if clickType == GameClickTypeNormal && g.MineField[row][col].Mine && g.absorbExplosion(clickedBy, row, col) {
+		return nil
+	}
+	g.changing(row, col)
 	g.MineField[row][col].Clicked = true
 	g.MineField[row][col].ClickedBy = clickedBy
 	switch clickType {
@@ -89,109 +172,179 @@ func (g *Game) Click(clickedBy string, clickType ClickType, row, col int) error
 		g.MineField[row][col].Flagged = true
 	case GameClickTypeNormal:
 		if !g.MineField[row][col].Flagged && g.MineField[row][col].Mine {
-			g.Status = GameStatusDefeat
-			now := time.Now()
-			g.FinishedAt = &now
+			g.finish(GameStatusDefeat)
 			return ErrDefeat
 		}
+		g.markRevealed(row, col)
+		g.awardItem(clickedBy, row, col)
 		if g.MineField[row][col].AdjCount == 0 {
-			_ = g.Click(clickedBy, GameClickTypeReveal, row, col)
+			_ = g.click(clickedBy, GameClickTypeReveal, row, col)
 		}
 	case GameClickTypeReveal:
+		g.markRevealed(row, col)
+		g.awardItem(clickedBy, row, col)
 		g.autoReveal(clickedBy, row, col)
 	}
 	g.printMinefield()
 	return nil
 }
 
-func NewGame(rows, cols, mines int, createdBy string) *Game {
+// unflag takes the flag off a field, leaving it as if it was never clicked. Fields without a flag are left
+// as they are.
+func (g *Game) unflag(row, col int) {
+	if !g.MineField[row][col].Flagged {
+		return
+	}
+	g.changing(row, col)
+	g.MineField[row][col].Clicked = false
+	g.MineField[row][col].Flagged = false
+	g.MineField[row][col].ClickedBy = ""
+}
+
+// generate lays out the board out of the game seed: the fields, the mines and the arcade items
+func (g *Game) generate() {
+	rnd := rand.New(rand.NewSource(g.Seed))
+	g.MineField = make([][]Field, g.Rows)
+	for i := 0; i < g.Rows; i++ {
+		g.MineField[i] = make([]Field, g.Cols)
+		for j := 0; j < g.Cols; j++ {
+			g.MineField[i][j] = Field{
+				Position: Position{i, j},
+			}
+		}
+	}
+	for mineCount := 0; mineCount < g.Mines; {
+		row := rnd.Intn(g.Rows)
+		col := rnd.Intn(g.Cols)
+		if !g.MineField[row][col].Mine {
+			g.MineField[row][col].Mine = true
+			mineCount++
+		}
+	}
+	g.countAdjacentMines()
+	if g.Rules.Arcade {
+		g.placeItems(rnd)
+	}
+}
+
+// NewGame creates a game, for endless games mines is the amount of mines of each chunk
+func NewGame(rows, cols, mines int, rules Rules, createdBy string) *Game {
+	if rules.Kernel == "" {
+		rules.Kernel = KernelMoore
+	}
+	if rules.Type == GameTypeEndless {
+		return newEndlessGame(mines, rules, createdBy)
+	}
 	if rows < GameMinRows {
 		rows = GameMinRows
 	}
+	if rows > GameMaxRows {
+		rows = GameMaxRows
+	}
 	if cols < GameMinCols {
 		cols = GameMinCols
 	}
+	if cols > GameMaxCols {
+		cols = GameMaxCols
+	}
 	if mines < 1 || mines > rows*cols {
 		mines = rows + cols // Make sure amount of mines is relative to a median of rows + cols
 	}
 
-	id, _ := utils.GenerateGUID()
-	newGame := Game{
-		ID:        id,
-		Rows:      rows,
-		Cols:      cols,
-		Mines:     mines,
-		Status:    GameStatusCreated,
-		CreatedAt: time.Now(),
-		// CreatedBy: User,
-	}
-
-	mineCount := 0
-	newGame.MineField = make([][]Field, newGame.Rows)
-	for i := 0; i < newGame.Rows; i++ {
-		newGame.MineField[i] = make([]Field, newGame.Cols)
-		for j := 0; j < newGame.Cols; j++ {
-			newGame.MineField[i][j] = Field{
-				Position: Position{i, j},
-			}
-		}
+	newGame := newClassicGame(rows, cols, mines, rules, time.Now().UnixNano(), createdBy)
+	// Boards out of the requested tier get rejected and generated again from the next seed, within budget
+	attempts := TierMaxFields / (rows * cols)
+	if attempts > TierMaxAttempts {
+		attempts = TierMaxAttempts
 	}
-	for mineCount < newGame.Mines {
-		seed := rand.NewSource(time.Now().UnixNano())
-		row := rand.New(seed).Intn(newGame.Rows)
-		col := rand.New(seed).Intn(newGame.Cols)
-		if !newGame.MineField[row][col].Mine {
-			newGame.MineField[row][col].Mine = true
-			mineCount++
-			offRow := row
-			if row == 0 {
-				offRow++
-			}
-			offCol := col
-			if col == 0 {
-				offCol++
-			}
-			for i := offRow - 1; i < newGame.Rows && i <= row+1; i++ {
-				for j := offCol - 1; j < newGame.Cols && j <= col+1; j++ {
-					if !newGame.MineField[i][j].Mine {
-						newGame.MineField[i][j].AdjCount++
-					} else {
-						newGame.MineField[i][j].AdjCount = 0
-					}
-				}
-			}
-		}
+	for attempt := 1; rules.Tier != "" && newGame.Difficulty.Tier != rules.Tier && attempt < attempts; attempt++ {
+		newGame.Seed++
+		newGame.generate()
+		newGame.Difficulty = newGame.Rate()
+	}
+
+	return newGame
+}
+
+// newClassicGame creates a classic game with the board laid out from the seed
+func newClassicGame(rows, cols, mines int, rules Rules, seed int64, createdBy string) *Game {
+	id, _ := utils.GenerateGUID()
+	game := &Game{
+		ID:            id,
+		SchemaVersion: StateSchemaVersion,
+		Rows:          rows,
+		Cols:          cols,
+		Mines:         mines,
+		Rules:         rules,
+		Seed:          seed,
+		Status:        GameStatusCreated,
+		CreatedAt:     time.Now(),
+		CreatedBy:     createdBy,
 	}
-	newGame.CreatedBy = createdBy
-	newGame.CreatedAt = time.Now()
+	game.generate()
+	game.Difficulty = game.Rate()
 
-	return &newGame
+	return game
 }
 
 func (g *Game) IsActive() bool {
 	return g.Status == GameStatusStarted
 }
 
+// markRevealed records when a field was revealed, the first time it happens
+func (g *Game) markRevealed(row, col int) {
+	if g.MineField[row][col].RevealedAt == nil {
+		now := time.Now()
+		g.MineField[row][col].RevealedAt = &now
+	}
+}
+
+// autoReveal reveals the neighbours of an empty field, and keeps going through the empty ones
 func (g *Game) autoReveal(clickedBy string, row, col int) {
-	offRow := row
-	if row == 0 {
-		offRow++
-	}
-	offCol := col
-	if col == 0 {
-		offCol++
-	}
-	for i := offRow - 1; i < g.Rows && i <= row+1; i++ {
-		for j := offCol - 1; j < g.Cols && j <= col+1; j++ {
-			if !g.MineField[i][j].Mine && g.MineField[i][j].AdjCount == 0 {
-				if !g.MineField[i][j].Clicked {
-					_ = g.Click(clickedBy, GameClickTypeReveal, i, j)
-				}
-			}
+	for _, n := range g.neighbours(row, col) {
+		field := &g.MineField[n.Row][n.Col]
+		if field.Mine || field.Clicked {
+			continue
+		}
+		if field.AdjCount == 0 {
+			_ = g.click(clickedBy, GameClickTypeReveal, n.Row, n.Col)
+		} else {
+			g.changing(n.Row, n.Col)
+			field.Clicked = true
+			field.ClickedBy = clickedBy
+			g.markRevealed(n.Row, n.Col)
+			g.awardItem(clickedBy, n.Row, n.Col)
 		}
 	}
 }
 
+// chord clicks every hidden neighbour of a revealed field once all of its adjacent mines are flagged
+func (g *Game) chord(clickedBy string, row, col int) error {
+	field := g.MineField[row][col]
+	if !field.Clicked || field.Flagged || field.AdjCount == 0 {
+		return nil
+	}
+	flags := 0
+	for _, n := range g.neighbours(row, col) {
+		if g.MineField[n.Row][n.Col].Flagged {
+			flags++
+		}
+	}
+	if flags != field.AdjCount {
+		return nil
+	}
+	for _, n := range g.neighbours(row, col) {
+		if g.MineField[n.Row][n.Col].Clicked {
+			continue
+		}
+		if err := g.click(clickedBy, GameClickTypeNormal, n.Row, n.Col); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Functions that pretty print the mine field :)
 
 func (g *Game) printMinefield() {
@@ -0,0 +1,101 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+)
+
+// Retry creates a new attempt at the board of the game: same layout and rules, but no moves played.
+// The game itself is left untouched.
+func (g *Game) Retry(createdBy string) *Game {
+	var retry *Game
+	if g.IsEndless() {
+		retry = newEndlessGame(g.Endless.ChunkMines, g.Rules, createdBy)
+		retry.Seed = g.Seed
+	} else {
+		id, _ := utils.GenerateGUID()
+		retry = &Game{
+			ID:            id,
+			SchemaVersion: StateSchemaVersion,
+			Rows:          g.Rows,
+			Cols:          g.Cols,
+			Mines:         g.Mines,
+			Rules:         g.Rules,
+			Status:        GameStatusCreated,
+			Seed:          g.Seed,
+			Difficulty:    g.Difficulty,
+			CreatedAt:     time.Now(),
+			CreatedBy:     createdBy,
+		}
+		retry.MineField = make([][]Field, g.Rows)
+		for i := range g.MineField {
+			retry.MineField[i] = make([]Field, len(g.MineField[i]))
+			for j, field := range g.MineField[i] {
+				retry.MineField[i][j] = Field{
+					Mine:     field.Mine,
+					AdjCount: field.AdjCount,
+					Position: Position{i, j},
+					Item:     field.Item,
+				}
+			}
+		}
+	}
+	retry.Unranked = g.Unranked
+	retry.Durability = g.Durability
+	retry.PreviousAttemptID = g.ID
+	retry.FirstAttemptID = g.FirstAttemptID
+	if retry.FirstAttemptID == "" {
+		retry.FirstAttemptID = g.ID
+	}
+
+	return retry
+}
+
+// Attempt summarizes a game played on a board, to compare the attempts at it
+type Attempt struct {
+	ID                string     `json:"id"`
+	PreviousAttemptID string     `json:"previousAttemptID,omitempty"`
+	CreatedBy         string     `json:"createdBy"`
+	Status            GameStatus `json:"status"`
+	Moves             int        `json:"moves"`
+	Revealed          int        `json:"revealed"`           // safe fields revealed
+	Duration          int64      `json:"duration,omitempty"` // milliseconds played, until now for unfinished games
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	FinishedAt        *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+}
+
+// Attempt summarizes the game as an attempt at its board
+func (g *Game) Attempt() *Attempt {
+	attempt := &Attempt{
+		ID:                g.ID,
+		PreviousAttemptID: g.PreviousAttemptID,
+		CreatedBy:         g.CreatedBy,
+		Status:            g.Status,
+		Moves:             len(g.History),
+		StartedAt:         g.StartedAt,
+		FinishedAt:        g.FinishedAt,
+		CreatedAt:         g.CreatedAt,
+	}
+	if g.IsEndless() {
+		attempt.Revealed = g.Endless.Score
+	} else {
+		for i := range g.MineField {
+			for _, field := range g.MineField[i] {
+				if field.Clicked && !field.Flagged && !field.Mine {
+					attempt.Revealed++
+				}
+			}
+		}
+	}
+	if g.StartedAt != nil {
+		end := time.Now()
+		if g.FinishedAt != nil {
+			end = *g.FinishedAt
+		}
+		attempt.Duration = end.Sub(*g.StartedAt).Milliseconds()
+	}
+
+	return attempt
+}
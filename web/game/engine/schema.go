@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownSchemaVersion = errors.New("Game state schema version is newer than this engine")
+)
+
+// StateSchemaVersion is the version of the shape of the game state, bump it and register an upgrader
+// from the previous version whenever a change to Game or Field would break stored states
+const StateSchemaVersion = 2
+
+// StateUpgrader moves a stored game state, as decoded from JSON, one schema version up
+type StateUpgrader func(state map[string]interface{}) error
+
+// stateUpgraders maps each schema version to the upgrader that moves states out of it
+var stateUpgraders = map[int]StateUpgrader{
+	0: upgradeStateV0,
+	1: upgradeStateV1,
+}
+
+// RegisterStateUpgrader registers the upgrader of the states of a schema version
+func RegisterStateUpgrader(from int, upgrader StateUpgrader) {
+	stateUpgraders[from] = upgrader
+}
+
+// StateVersion returns the schema version of a stored state, states stored before versioning are version 0
+func StateVersion(state map[string]interface{}) int {
+	switch version := state["schemaVersion"].(type) {
+	case float64:
+		return int(version)
+	case int:
+		return version
+	default:
+		return 0
+	}
+}
+
+// UpgradeState runs the upgraders needed to move a stored state to StateSchemaVersion,
+// it returns whether the state changed
+func UpgradeState(state map[string]interface{}) (upgraded bool, err error) {
+	version := StateVersion(state)
+	if version > StateSchemaVersion {
+		return false, fmt.Errorf("%w: %d", ErrUnknownSchemaVersion, version)
+	}
+	for ; version < StateSchemaVersion; version++ {
+		upgrader, found := stateUpgraders[version]
+		if !found {
+			return upgraded, fmt.Errorf("No upgrader registered for game state schema version %d", version)
+		}
+		if err = upgrader(state); err != nil {
+			return upgraded, fmt.Errorf("Upgrading game state from schema version %d: %w", version, err)
+		}
+		state["schemaVersion"] = version + 1
+		upgraded = true
+	}
+
+	return upgraded, nil
+}
+
+// upgradeStateV0 upgrades states stored before game rules existed, those games were classic Moore games.
+// Their sizes and mines are counted out of the mine field, the ones stored next to it came from the input
+// of the player and may not match it.
+func upgradeStateV0(state map[string]interface{}) error {
+	rules, _ := state["rules"].(map[string]interface{})
+	if rules == nil {
+		rules = map[string]interface{}{}
+	}
+	if kernel, _ := rules["kernel"].(string); kernel == "" {
+		rules["kernel"] = string(KernelMoore)
+	}
+	state["rules"] = rules
+	if status, _ := state["status"].(string); status == "" {
+		state["status"] = GameStatusCreated
+	}
+	rows, _ := state["mineField"].([]interface{})
+	cols, mines := 0, 0
+	for i, row := range rows {
+		fields, _ := row.([]interface{})
+		if i == 0 {
+			cols = len(fields)
+		}
+		for j, field := range fields {
+			f, ok := field.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if f["position"] == nil {
+				f["position"] = map[string]interface{}{"row": i, "col": j}
+			}
+			if mine, _ := f["mine"].(bool); mine {
+				mines++
+			}
+		}
+	}
+	if len(rows) > 0 {
+		state["rows"], state["cols"], state["mines"] = len(rows), cols, mines
+	}
+
+	return nil
+}
+
+// upgradeStateV1 moves the mine field of the state to the compact board encoding
+func upgradeStateV1(state map[string]interface{}) error {
+	if state["mineField"] == nil {
+		delete(state, "mineField")
+		return nil
+	}
+	b, err := json.Marshal(state["mineField"])
+	if err != nil {
+		return err
+	}
+	var mineField [][]Field
+	if err = json.Unmarshal(b, &mineField); err != nil {
+		return err
+	}
+	// The state is kept as decoded from JSON, where the board is a base64 string
+	b, err = json.Marshal(EncodeBoard(mineField))
+	if err != nil {
+		return err
+	}
+	var board string
+	if err = json.Unmarshal(b, &board); err != nil {
+		return err
+	}
+	state["board"] = board
+	delete(state, "mineField")
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrCorruptedState = errors.New("Corrupted game state")
+)
+
+var knownStatuses = map[GameStatus]bool{
+	GameStatusCreated:   true,
+	GameStatusStarted:   true,
+	GameStatusVictory:   true,
+	GameStatusDefeat:    true,
+	GameStatusForfeited: true,
+	GameStatusAbandoned: true,
+}
+
+// StateError lists the inconsistencies found in a game state
+type StateError struct {
+	GameID string
+	Issues []string
+}
+
+func (e *StateError) Error() string {
+	return fmt.Sprintf("Game %s state is corrupted: %s", e.GameID, strings.Join(e.Issues, "; "))
+}
+
+func (e *StateError) Unwrap() error {
+	return ErrCorruptedState
+}
+
+func (e *StateError) add(format string, args ...interface{}) {
+	e.Issues = append(e.Issues, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the game state is self-consistent: the board matches its dimensions, fields
+// know their position, counts match their neighbours and the board holds the amount of mines
+// of the game. It returns a *StateError listing every inconsistency found.
+func (g *Game) Validate() error {
+	e := &StateError{GameID: g.ID}
+	if !knownStatuses[g.Status] {
+		e.add("unknown status %q", g.Status)
+	}
+	if g.Rules.Kernel != "" {
+		if _, err := ParseKernel(string(g.Rules.Kernel)); err != nil {
+			e.add("unknown kernel %q", g.Rules.Kernel)
+		}
+	}
+	if g.IsEndless() {
+		g.validateEndless(e)
+	} else {
+		g.validateMineField(e)
+	}
+	if len(e.Issues) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+func (g *Game) validateMineField(e *StateError) {
+	if g.Rows < 1 || g.Cols < 1 {
+		e.add("invalid dimensions %dx%d", g.Rows, g.Cols)
+		return
+	}
+	if len(g.MineField) != g.Rows {
+		e.add("mine field has %d rows, expected %d", len(g.MineField), g.Rows)
+		return
+	}
+	mines := 0
+	for i := range g.MineField {
+		if len(g.MineField[i]) != g.Cols {
+			e.add("mine field row %d has %d cols, expected %d", i, len(g.MineField[i]), g.Cols)
+			return
+		}
+		for j, field := range g.MineField[i] {
+			if field.Position.Row != i || field.Position.Col != j {
+				e.add("field [%d, %d] has position [%d, %d]", i, j, field.Position.Row, field.Position.Col)
+			}
+			if field.Mine {
+				mines++
+			}
+		}
+	}
+	if mines != g.Mines {
+		e.add("mine field has %d mines, expected %d", mines, g.Mines)
+	}
+	for i := range g.MineField {
+		for j, field := range g.MineField[i] {
+			if count := g.adjacentMines(i, j); !field.Mine && field.AdjCount != count {
+				e.add("field [%d, %d] counts %d adjacent mines, has %d", i, j, field.AdjCount, count)
+			}
+		}
+	}
+}
+
+func (g *Game) validateEndless(e *StateError) {
+	if g.Endless == nil {
+		e.add("endless game without an endless board")
+		return
+	}
+	if g.Endless.ChunkSize < 1 {
+		e.add("invalid chunk size %d", g.Endless.ChunkSize)
+		return
+	}
+	size := len(newPlane(g.Endless.ChunkSize * g.Endless.ChunkSize))
+	for key, chunk := range g.Endless.Chunks {
+		if chunk == nil {
+			e.add("chunk %s is empty", key)
+			continue
+		}
+		if key != chunkKey(chunk.Row, chunk.Col) {
+			e.add("chunk %s has position %s", key, chunkKey(chunk.Row, chunk.Col))
+		}
+		if len(chunk.Mines) != size || len(chunk.Clicked) != size || len(chunk.Flagged) != size {
+			e.add("chunk %s planes don't match the chunk size %d", key, g.Endless.ChunkSize)
+		}
+	}
+}
+
+func (g *Game) adjacentMines(row, col int) int {
+	count := 0
+	for _, n := range g.neighbours(row, col) {
+		if g.MineField[n.Row][n.Col].Mine {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Repair recomputes the fields of the state that are derived from the board: the position of the
+// fields, their adjacent mine counts and the amount of mines. Boards with the wrong shape can't be
+// repaired, Validate still reports them afterwards.
+func (g *Game) Repair() {
+	if g.IsEndless() || len(g.MineField) != g.Rows {
+		return
+	}
+	mines := 0
+	for i := range g.MineField {
+		if len(g.MineField[i]) != g.Cols {
+			return
+		}
+		for j := range g.MineField[i] {
+			g.MineField[i][j].Position = Position{i, j}
+			if g.MineField[i][j].Mine {
+				mines++
+			}
+		}
+	}
+	g.Mines = mines
+	g.countAdjacentMines()
+}
@@ -0,0 +1,25 @@
+package engine
+
+// checkVictory wins the game once every safe field of its board is revealed, whichever move revealed
+// the last one
+func (g *Game) checkVictory() {
+	if g.IsActive() && g.cleared() {
+		g.finish(GameStatusVictory)
+	}
+}
+
+// cleared tells whether every safe field of a classic board has been revealed, endless boards never are
+func (g *Game) cleared() bool {
+	if g.IsEndless() || len(g.MineField) == 0 {
+		return false
+	}
+	for i := range g.MineField {
+		for _, field := range g.MineField[i] {
+			if !field.Mine && (!field.Clicked || field.Flagged) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
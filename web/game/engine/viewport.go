@@ -0,0 +1,91 @@
+package engine
+
+// Viewport limits
+const (
+	ViewportDefaultSize = 20
+	ViewportMaxSize     = 100
+)
+
+// States of a field as seen by the players
+const (
+	CellStateHidden     = "hidden"
+	CellStateRevealed   = "revealed"
+	CellStateFlagged    = "flagged"
+	CellStateMine       = "mine"       // only shown once the game is over
+	CellStateRemembered = "remembered" // revealed, but faded by the fog so the count is hidden
+	CellStateVoid       = "void"       // outside of the board
+)
+
+// CellView is what a player sees of a field
+type CellView struct {
+	State    string `json:"state"`
+	AdjCount int    `json:"adjMines,omitempty"`
+}
+
+// Viewport is a window of the board, starting at [Row, Col]
+type Viewport struct {
+	Row   int          `json:"row"`
+	Col   int          `json:"col"`
+	Rows  int          `json:"rows"`
+	Cols  int          `json:"cols"`
+	Cells [][]CellView `json:"cells"`
+}
+
+// Viewport returns a window of the board as seen by the player, it accepts any coordinate so endless
+// boards can be browsed, fields outside of a classic board are void
+func (g *Game) Viewport(player string, row, col, rows, cols int) *Viewport {
+	if rows < 1 {
+		rows = ViewportDefaultSize
+	}
+	if cols < 1 {
+		cols = ViewportDefaultSize
+	}
+	if rows > ViewportMaxSize {
+		rows = ViewportMaxSize
+	}
+	if cols > ViewportMaxSize {
+		cols = ViewportMaxSize
+	}
+	visible := g.visibility(player)
+	view := &Viewport{Row: row, Col: col, Rows: rows, Cols: cols}
+	view.Cells = make([][]CellView, rows)
+	for i := 0; i < rows; i++ {
+		view.Cells[i] = make([]CellView, cols)
+		for j := 0; j < cols; j++ {
+			view.Cells[i][j] = g.cellView(row+i, col+j, visible)
+		}
+	}
+
+	return view
+}
+
+func (g *Game) cellView(row, col int, visible func(row, col int) bool) CellView {
+	var clicked, flagged, mine bool
+	var adjCount int
+	if g.IsEndless() {
+		clicked, flagged = g.endlessClicked(row, col), g.endlessFlagged(row, col)
+		mine = !g.IsActive() && g.endlessMine(row, col)
+		if clicked {
+			adjCount = g.endlessAdjCount(row, col)
+		}
+	} else {
+		if row < 0 || row >= g.Rows || col < 0 || col >= g.Cols {
+			return CellView{State: CellStateVoid}
+		}
+		field := g.MineField[row][col]
+		clicked, flagged, adjCount = field.Clicked, field.Flagged, field.AdjCount
+		mine = !g.IsActive() && field.Mine
+	}
+	switch {
+	case flagged:
+		return CellView{State: CellStateFlagged}
+	case clicked && !visible(row, col):
+		return CellView{State: CellStateRemembered}
+	case clicked:
+		return CellView{State: CellStateRevealed, AdjCount: adjCount}
+	case mine && g.Status != GameStatusCreated:
+		return CellView{State: CellStateMine}
+	default:
+		return CellView{State: CellStateHidden}
+	}
+}
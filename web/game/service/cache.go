@@ -0,0 +1,188 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+)
+
+// Cache and write-behind defaults, used when the configuration leaves them unset
+const (
+	DefaultCacheSize     = 1000
+	DefaultCacheTTL      = 30 * time.Minute
+	DefaultReapInterval  = time.Minute
+	DefaultFlushInterval = 5 * time.Second
+	DefaultFlushBatch    = 100
+)
+
+type cacheEntry struct {
+	game     *engine.Game
+	changes  uint64 // times the game changed
+	stored   uint64 // changes already stored
+	lastUsed time.Time
+	elem     *list.Element
+}
+
+// dirty tells whether the game changed since it was last stored
+func (e *cacheEntry) dirty() bool {
+	return e.changes != e.stored
+}
+
+// gameCache keeps the games in memory, bounded by size with least recently used eviction, and by
+// time with entries not used for longer than the ttl expiring. Evicted entries are handed back to
+// the caller, which has to store the dirty ones.
+type gameCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+	lru     *list.List // game ids, most recently used first
+}
+
+func newGameCache(size int, ttl time.Duration) *gameCache {
+	if size < 1 {
+		size = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &gameCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*cacheEntry),
+		lru:     list.New(),
+	}
+}
+
+func (c *gameCache) get(gameID string) (*engine.Game, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.entries[gameID]
+	if !found {
+		return nil, false
+	}
+	c.touch(entry)
+
+	return entry.game, true
+}
+
+// put caches the game, it returns the entry evicted to make room for it, if any
+func (c *gameCache) put(game *engine.Game, dirty bool) (evicted *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, found := c.entries[game.ID]; found {
+		entry.game = game
+		if dirty {
+			entry.changes++
+		}
+		c.touch(entry)
+		return nil
+	}
+	entry := &cacheEntry{game: game, lastUsed: time.Now()}
+	if dirty {
+		entry.changes++
+	}
+	entry.elem = c.lru.PushFront(game.ID)
+	c.entries[game.ID] = entry
+	if c.lru.Len() > c.size {
+		evicted = c.remove(c.lru.Back().Value.(string))
+	}
+
+	return evicted
+}
+
+func (c *gameCache) markDirty(gameID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, found := c.entries[gameID]; found {
+		entry.changes++
+		c.touch(entry)
+	}
+}
+
+// markStored records that the game was stored as it was after the given changes, later changes keep it dirty
+func (c *gameCache) markStored(gameID string, changes uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, found := c.entries[gameID]; found && changes > entry.stored {
+		entry.stored = changes
+	}
+}
+
+// changes returns how many times a cached game changed
+func (c *gameCache) changes(gameID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, found := c.entries[gameID]; found {
+		return entry.changes
+	}
+
+	return 0
+}
+
+func (c *gameCache) delete(gameID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(gameID)
+}
+
+// expire evicts the entries not used for longer than the ttl
+func (c *gameCache) expire() (evicted []*cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deadline := time.Now().Add(-c.ttl)
+	for elem := c.lru.Back(); elem != nil; {
+		entry := c.entries[elem.Value.(string)]
+		if entry.lastUsed.After(deadline) {
+			break
+		}
+		elem = elem.Prev()
+		evicted = append(evicted, c.remove(entry.game.ID))
+	}
+
+	return evicted
+}
+
+// games returns a snapshot of the cached games
+func (c *gameCache) games() map[string]*engine.Game {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	games := make(map[string]*engine.Game, len(c.entries))
+	for id, entry := range c.entries {
+		games[id] = entry.game
+	}
+
+	return games
+}
+
+// dirtyGames returns the cached games changed since they were last stored
+func (c *gameCache) dirtyGames() (games []*engine.Game) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.entries {
+		if entry.dirty() {
+			games = append(games, entry.game)
+		}
+	}
+
+	return games
+}
+
+func (c *gameCache) touch(entry *cacheEntry) {
+	entry.lastUsed = time.Now()
+	c.lru.MoveToFront(entry.elem)
+}
+
+func (c *gameCache) remove(gameID string) *cacheEntry {
+	entry, found := c.entries[gameID]
+	if !found {
+		return nil
+	}
+	c.lru.Remove(entry.elem)
+	delete(c.entries, gameID)
+
+	return entry
+}
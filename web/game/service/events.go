@@ -0,0 +1,163 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+)
+
+// Event delivery limits
+const (
+	DefaultEventBuffer  = 64  // events a subscriber can fall behind before it gets dropped
+	DefaultEventBacklog = 256 // last events of each game kept for subscribers resuming
+)
+
+// Subscription delivers the events of a game as its player is allowed to see them, Events gets closed
+// when the subscriber is dropped for falling behind or unsubscribes
+type Subscription struct {
+	GameID string
+	Player string
+	Events <-chan engine.Event
+	events chan engine.Event
+}
+
+// projection returns an event as a player is allowed to see it, see engine.Game.EventFor. Events are
+// projected when they are published, while the game can't change, so subscribers never read the game
+type projection func(player string, e engine.Event) engine.Event
+
+// eventHub fans the events of the games out to their subscribers, numbering them by game and keeping
+// the last ones for the subscribers that resume after a disconnection
+type eventHub struct {
+	mu      sync.Mutex
+	seqs    map[string]uint64
+	backlog map[string][]engine.Event
+	subs    map[string]map[*Subscription]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		seqs:    make(map[string]uint64),
+		backlog: make(map[string][]engine.Event),
+		subs:    make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// publish numbers the events and hands them to the subscribers of the game as projected for each of
+// them, a nil project hands them as they are. Subscribers too slow to take them are dropped instead of
+// holding the game back
+func (h *eventHub) publish(gameID string, project projection, events ...engine.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, e := range events {
+		h.seqs[gameID]++
+		e.Seq = h.seqs[gameID]
+		e.GameID = gameID
+		if e.At.IsZero() {
+			e.At = time.Now()
+		}
+		backlog := append(h.backlog[gameID], e)
+		if len(backlog) > DefaultEventBacklog {
+			backlog = backlog[len(backlog)-DefaultEventBacklog:]
+		}
+		h.backlog[gameID] = backlog
+		for sub := range h.subs[gameID] {
+			select {
+			case sub.events <- projectFor(project, sub.Player, e):
+			default:
+				h.remove(sub)
+			}
+		}
+	}
+}
+
+func (h *eventHub) subscribe(gameID, player string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.add(gameID, player)
+}
+
+// resume subscribes to the events of a game published after the event lastSeq, returning the ones
+// already published. When some of them are gone, or lastSeq is unknown like after a restart, a resync
+// event telling the subscriber to read the game again is returned instead. The missed events are
+// projected like the published ones.
+func (h *eventHub) resume(gameID, player string, lastSeq uint64, project projection) (sub *Subscription,
+	missed []engine.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	backlog := h.backlog[gameID]
+	seq := h.seqs[gameID]
+	if lastSeq > seq || (lastSeq < seq && (len(backlog) == 0 || backlog[0].Seq > lastSeq+1)) {
+		missed = append(missed, engine.Event{Seq: seq, Type: engine.EventResync, GameID: gameID, At: time.Now()})
+		return h.add(gameID, player), missed
+	}
+	for _, e := range backlog {
+		if e.Seq > lastSeq {
+			missed = append(missed, projectFor(project, player, e))
+		}
+	}
+
+	return h.add(gameID, player), missed
+}
+
+// closeAll ends every subscription, their subscribers see their events closed
+func (h *eventHub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subs := range h.subs {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// forget drops the sequence and the backlog of a game nobody is subscribed to
+func (h *eventHub) forget(gameID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subs[gameID]) == 0 {
+		delete(h.seqs, gameID)
+		delete(h.backlog, gameID)
+	}
+}
+
+func (h *eventHub) add(gameID, player string) *Subscription {
+	events := make(chan engine.Event, DefaultEventBuffer)
+	sub := &Subscription{GameID: gameID, Player: player, Events: events, events: events}
+	if h.subs[gameID] == nil {
+		h.subs[gameID] = make(map[*Subscription]struct{})
+	}
+	h.subs[gameID][sub] = struct{}{}
+
+	return sub
+}
+
+func (h *eventHub) unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(sub)
+}
+
+func (h *eventHub) remove(sub *Subscription) {
+	subs, found := h.subs[sub.GameID]
+	if !found {
+		return
+	}
+	if _, found = subs[sub]; !found {
+		return
+	}
+	delete(subs, sub)
+	close(sub.events)
+	if len(subs) == 0 {
+		delete(h.subs, sub.GameID)
+	}
+}
+
+func projectFor(project projection, player string, e engine.Event) engine.Event {
+	if project == nil {
+		return e
+	}
+
+	return project(player, e)
+}
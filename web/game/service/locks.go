@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sync"
+)
+
+// gameLocks serializes the changes of each game. The lock of a game is kept while someone holds it or
+// waits for it and dropped afterwards, so removed and evicted games don't leave their locks behind while
+// whoever holds them still gets to unlock them.
+type gameLocks struct {
+	mu    sync.Mutex
+	locks map[string]*gameLock
+}
+
+type gameLock struct {
+	sync.Mutex
+	refs int // goroutines holding the lock or waiting for it
+}
+
+func newGameLocks() *gameLocks {
+	return &gameLocks{locks: make(map[string]*gameLock)}
+}
+
+// lock locks a game, it returns the function unlocking it
+func (l *gameLocks) lock(gameID string) (unlock func()) {
+	l.mu.Lock()
+	gl, found := l.locks[gameID]
+	if !found {
+		gl = &gameLock{}
+		l.locks[gameID] = gl
+	}
+	gl.refs++
+	l.mu.Unlock()
+	gl.Lock()
+
+	return func() {
+		gl.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if gl.refs--; gl.refs == 0 {
+			delete(l.locks, gameID)
+		}
+	}
+}
@@ -1,73 +1,534 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
 	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
 )
 
 var (
-	ErrGameNotFound = errors.New("Game not found")
+	ErrGameNotFound       = errors.New("Game not found")
+	ErrCorruptedGameState = errors.New("Corrupted game state")
 )
 
 // MineSweeperGame represents a minesweeper game service
 type MineSweeperGameSvc interface {
-	CreateGame(rows, cols, mines int, createdBy string) (game *engine.Game, err error)
-	StartGame(gameID string) (err error)
+	CreateGame(rows, cols, mines int, rules engine.Rules, createdBy string) (game *engine.Game, err error)
+	CreateGameFromCode(code string, createdBy string) (game *engine.Game, err error)
+	StartGame(ctx context.Context, gameID string) (game *engine.Game, err error)
 	GetGame(gameID string) (game *engine.Game, err error)
-	Click(gameID string, user string, clickType engine.ClickType, row, col int) (err error)
+	LoadGame(ctx context.Context, gameID string) (game *engine.Game, err error)
+	UpdateGame(ctx context.Context, gameID string, change func(game *engine.Game) error) (game *engine.Game, err error)
+	Click(ctx context.Context, gameID string, user string, clickType engine.ClickType, row, col int) (game *engine.Game, delta *engine.Delta, err error)
+	PlayMoves(ctx context.Context, gameID string, player string, moves []engine.PlannedMove) (game *engine.Game, result *engine.MovesResult, err error)
+	Forfeit(ctx context.Context, gameID string, player string) (game *engine.Game, err error)
+	Join(gameID string, player string)
+	Subscribe(gameID string, player string) *Subscription
+	Resume(ctx context.Context, gameID string, player string, lastSeq uint64) (sub *Subscription, missed []engine.Event, err error)
+	Unsubscribe(sub *Subscription)
+	CloseSubscriptions()
 	GetGameList() (games map[string]*engine.Game, err error)
 	UpdateGameState(gameID string, game *engine.Game) (err error)
+	RemoveGame(gameID string)
+	AbandonIdleGames(ctx context.Context) (abandoned []string, err error)
+	Flush(ctx context.Context) (flushed int, err error)
+	WriteBehind() bool
+	StartWorkers(ctx context.Context)
+}
+
+// Config holds the settings of the minesweeper game service
+type Config struct {
+	RepairState  bool          // recompute the derived fields of inconsistent game states instead of refusing them
+	IdleTimeout  time.Duration // started games not played for longer than this get abandoned, 0 never abandons them
+	CacheSize    int           // games kept in memory, the least recently used get evicted
+	CacheTTL     time.Duration // games not used for longer than this get evicted
+	ReapInterval time.Duration // how often the reaper abandons idle games and evicts expired ones
+	// Write-behind mode makes the service the authority for the games it holds, their changes get
+	// stored in batches, when they finish, or on every move depending on their durability
+	WriteBehind   bool
+	FlushInterval time.Duration // how often the changed games get stored in write-behind mode
+	FlushBatch    int           // games stored in each transaction
 }
 
 // MineSweeperGameSvcImpl implementing struct of a minesweeper game service
 type MineSweeperGameSvcImpl struct {
 	// Will hold persistance instance, logger, and/or any instances needed by the game
-	games map[string]*engine.Game
+	log      *logger.Logger
+	cfg      Config
+	gameRepo repo.GameRepo
+	games    *gameCache
+	events   *eventHub
+	locks    *gameLocks
 }
 
-func (ms *MineSweeperGameSvcImpl) NewMineSweeperSvc() MineSweeperGameSvc {
+func (ms *MineSweeperGameSvcImpl) NewMineSweeperSvc(log *logger.Logger, cfg Config, gameRepo repo.GameRepo) MineSweeperGameSvc {
 	return &MineSweeperGameSvcImpl{
-		games: make(map[string]*engine.Game),
+		log:      log,
+		cfg:      cfg,
+		gameRepo: gameRepo,
+		games:    newGameCache(cfg.CacheSize, cfg.CacheTTL),
+		events:   newEventHub(),
+		locks:    newGameLocks(),
 	}
 }
 
-func (ms *MineSweeperGameSvcImpl) CreateGame(rows, cols, mines int, createdBy string) (game *engine.Game, err error) {
-	game = engine.NewGame(rows, cols, mines, createdBy)
-	ms.games[game.ID] = game
-	return ms.games[game.ID], err
+// CreateGame creates a game, the caller is the one storing it
+func (ms *MineSweeperGameSvcImpl) CreateGame(rows, cols, mines int, rules engine.Rules, createdBy string) (game *engine.Game, err error) {
+	game = engine.NewGame(rows, cols, mines, rules, createdBy)
+	if rules.Tier != "" && (game.Difficulty == nil || game.Difficulty.Tier != rules.Tier) {
+		return nil, engine.ErrTierNotReached
+	}
+	ms.cache(game, false)
+	return game, err
 }
 
-func (ms *MineSweeperGameSvcImpl) StartGame(gameID string) (err error) {
-	game, err := ms.GetGame(gameID)
+// CreateGameFromCode creates a game with the board of a board code
+func (ms *MineSweeperGameSvcImpl) CreateGameFromCode(code string, createdBy string) (game *engine.Game, err error) {
+	game, err = engine.NewGameFromCode(code, createdBy)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	ms.cache(game, false)
+	return game, nil
+}
+
+// StartGame starts a game and stores it
+func (ms *MineSweeperGameSvcImpl) StartGame(ctx context.Context, gameID string) (game *engine.Game, err error) {
+	return ms.UpdateGame(ctx, gameID, func(game *engine.Game) error {
+		if err := game.Start(); err != nil {
+			return err
+		}
+		ms.events.publish(gameID, game.EventFor, engine.Event{Type: engine.EventGameStarted, Status: game.Status, Version: game.Version})
 
-	return game.Start()
+		return nil
+	})
 }
 
+// GetGame returns a copy of a game held in memory, taken under its lock. The games the service returns are
+// copies, so they can be read while their moves keep being played
 func (ms *MineSweeperGameSvcImpl) GetGame(gameID string) (game *engine.Game, err error) {
-	if _, found := ms.games[gameID]; found {
-		return ms.games[gameID], nil
+	defer ms.lock(gameID)()
+	if game, found := ms.games.get(gameID); found {
+		return game.Copy(), nil
 	}
 	return nil, ErrGameNotFound
 }
 
-func (ms *MineSweeperGameSvcImpl) Click(gameID string, clickedBy string, clickType engine.ClickType, row, col int) (err error) {
-	game, err := ms.GetGame(gameID)
-	if err != nil {
+// LoadGame returns a copy of a game held in memory, or reads it from the repo when it isn't
+func (ms *MineSweeperGameSvcImpl) LoadGame(ctx context.Context, gameID string) (game *engine.Game, err error) {
+	defer ms.lock(gameID)()
+	cached, found := ms.games.get(gameID)
+	if found {
+		return cached.Copy(), nil
+	}
+	if game, err = ms.read(ctx, gameID, nil); err != nil {
+		return nil, err
+	}
+
+	return game.Copy(), nil
+}
+
+// UpdateGame loads the latest state of a game, changes it and stores it, holding the lock of the game
+// throughout so no other change gets in between. It returns a copy of the game as the change left it.
+// Changes failing with other errors than engine.ErrDefeat are not stored
+func (ms *MineSweeperGameSvcImpl) UpdateGame(ctx context.Context, gameID string, change func(game *engine.Game) error) (game *engine.Game, err error) {
+	defer ms.lock(gameID)()
+	if game, err = ms.loadForUpdate(ctx, gameID); err != nil {
+		return nil, err
+	}
+	changeErr := change(game)
+	if changeErr != nil && !errors.Is(changeErr, engine.ErrDefeat) {
+		return nil, changeErr
+	}
+	if err = ms.store(ctx, game); err != nil {
+		return nil, err
+	}
+
+	return game.Copy(), changeErr
+}
+
+// Click plays a move in a game and stores it, returning what it changed. A losing move returns the game
+// and its delta along engine.ErrDefeat
+func (ms *MineSweeperGameSvcImpl) Click(ctx context.Context, gameID string, clickedBy string, clickType engine.ClickType, row, col int) (game *engine.Game, delta *engine.Delta, err error) {
+	game, err = ms.UpdateGame(ctx, gameID, func(game *engine.Game) (err error) {
+		delta, err = game.Click(clickedBy, clickType, row, col)
+		if delta != nil {
+			ms.events.publish(gameID, game.EventFor, delta.Events(clickedBy)...)
+		}
+
 		return err
+	})
+	if game == nil {
+		return nil, nil, err
+	}
+
+	return game, delta, err
+}
+
+// PlayMoves plays a batch of moves in a game and stores it, no other move can be played in the game meanwhile
+func (ms *MineSweeperGameSvcImpl) PlayMoves(ctx context.Context, gameID string, player string, moves []engine.PlannedMove) (game *engine.Game, result *engine.MovesResult, err error) {
+	game, err = ms.UpdateGame(ctx, gameID, func(game *engine.Game) error {
+		result = game.PlayMoves(player, moves)
+		for _, move := range result.Moves {
+			if move.Delta != nil {
+				ms.events.publish(gameID, game.EventFor, move.Delta.Events(player)...)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return game, result, nil
+}
+
+// lock locks the changes of a game, it returns the function unlocking them
+func (ms *MineSweeperGameSvcImpl) lock(gameID string) (unlock func()) {
+	return ms.locks.lock(gameID)
+}
+
+// Forfeit gives up a game for the player, revealing its board, and stores it
+func (ms *MineSweeperGameSvcImpl) Forfeit(ctx context.Context, gameID string, player string) (game *engine.Game, err error) {
+	return ms.UpdateGame(ctx, gameID, func(game *engine.Game) error {
+		delta, err := game.Forfeit()
+		if err != nil {
+			return err
+		}
+		ms.events.publish(gameID, game.EventFor, delta.Events(player)...)
+
+		return nil
+	})
+}
+
+// Join tells whoever watches a game that a player joined it
+func (ms *MineSweeperGameSvcImpl) Join(gameID string, player string) {
+	ms.events.publish(gameID, nil, engine.Event{Type: engine.EventPlayerJoined, Player: player})
+}
+
+// Subscribe starts delivering the events of a game as the player is allowed to see them, the
+// subscription has to be ended with Unsubscribe
+func (ms *MineSweeperGameSvcImpl) Subscribe(gameID string, player string) *Subscription {
+	return ms.events.subscribe(gameID, player)
+}
+
+// Resume subscribes to the events of a game after the event lastSeq, returning the ones already published,
+// as the player is allowed to see them. The game is loaded under its lock to project them, reading it from
+// the repo when it isn't held in memory
+func (ms *MineSweeperGameSvcImpl) Resume(ctx context.Context, gameID string, player string, lastSeq uint64) (sub *Subscription, missed []engine.Event, err error) {
+	defer ms.lock(gameID)()
+	game, found := ms.games.get(gameID)
+	if !found {
+		if game, err = ms.read(ctx, gameID, nil); err != nil {
+			return nil, nil, err
+		}
 	}
-	return game.Click(clickedBy, clickType, row, col)
+	sub, missed = ms.events.resume(gameID, player, lastSeq, game.EventFor)
+
+	return sub, missed, nil
+}
+
+// CloseSubscriptions ends every subscription, so the streams of events end before shutting down
+func (ms *MineSweeperGameSvcImpl) CloseSubscriptions() {
+	ms.events.closeAll()
+}
+
+// Unsubscribe stops delivering the events of a subscription and closes it
+func (ms *MineSweeperGameSvcImpl) Unsubscribe(sub *Subscription) {
+	ms.events.unsubscribe(sub)
 }
 
+// GetGameList returns copies of the games held in memory, each taken under the lock of its game
 func (ms *MineSweeperGameSvcImpl) GetGameList() (games map[string]*engine.Game, err error) {
-	return ms.games, nil
+	cached := ms.games.games()
+	games = make(map[string]*engine.Game, len(cached))
+	for id, game := range cached {
+		unlock := ms.lock(id)
+		games[id] = game.Copy()
+		unlock()
+	}
+
+	return games, nil
 }
 
+// UpdateGameState validates the game state before the service takes it, inconsistent states are
+// repaired when the service is configured to, otherwise they are refused
 func (ms *MineSweeperGameSvcImpl) UpdateGameState(gameID string, game *engine.Game) (err error) {
-	ms.games[gameID] = game
+	if err = ms.validate(game); err != nil {
+		return err
+	}
+	ms.cache(game, false)
+
+	return nil
+}
+
+// RemoveGame drops a game from the service, deleted games must not be served anymore
+func (ms *MineSweeperGameSvcImpl) RemoveGame(gameID string) {
+	defer ms.lock(gameID)()
+	ms.games.delete(gameID)
+	ms.events.forget(gameID)
+}
+
+// AbandonIdleGames abandons the started games idle for longer than the configured timeout, the ones in
+// memory and the ones only stored, and stores them
+func (ms *MineSweeperGameSvcImpl) AbandonIdleGames(ctx context.Context) (abandoned []string, err error) {
+	if ms.cfg.IdleTimeout <= 0 {
+		return nil, nil
+	}
+	cached := ms.games.games()
+	for _, game := range cached {
+		if !ms.abandonIdle(game) {
+			continue
+		}
+		if err = ms.flush(ctx, game); err != nil {
+			ms.log.Error().Err(err).Str("gameID", game.ID).Msg("Abandoned game could not be stored")
+			continue
+		}
+		abandoned = append(abandoned, game.ID)
+	}
+	gamesStore, err := ms.gameRepo.ListIdle(ctx, time.Now().Add(-ms.cfg.IdleTimeout))
+	if err != nil {
+		return abandoned, err
+	}
+	for _, gameStore := range gamesStore {
+		if _, found := cached[gameStore.ID]; found {
+			continue
+		}
+		game, err := gameStore.GetGameState()
+		if err != nil {
+			ms.log.Warn().Err(err).Str("gameID", gameStore.ID).Msg("Game left out of the idle check")
+			continue
+		}
+		if !game.IsIdle(ms.cfg.IdleTimeout) || game.Abandon() != nil {
+			continue
+		}
+		gameStore.UpdateGameState(game)
+		if _, err = ms.gameRepo.UpsertGame(ctx, &gameStore.ID, gameStore); err != nil {
+			ms.log.Error().Err(err).Str("gameID", game.ID).Msg("Abandoned game could not be stored")
+			continue
+		}
+		abandoned = append(abandoned, game.ID)
+	}
+
+	return abandoned, nil
+}
+
+// abandonIdle abandons a game held in memory if it is idle, it returns whether it did
+func (ms *MineSweeperGameSvcImpl) abandonIdle(game *engine.Game) bool {
+	defer ms.lock(game.ID)()
+	if !game.IsIdle(ms.cfg.IdleTimeout) || game.Abandon() != nil {
+		return false
+	}
+	ms.games.markDirty(game.ID)
+	ms.events.publish(game.ID, game.EventFor, engine.Event{Type: engine.EventGameFinished, Status: game.Status, Version: game.Version})
+
+	return true
+}
+
+// store stores the changes of a game whose lock is held: right away, or in write-behind mode in the next
+// batch unless the game finished or its durability asks for every move to be stored
+func (ms *MineSweeperGameSvcImpl) store(ctx context.Context, game *engine.Game) error {
+	ms.games.markDirty(game.ID)
+	if ms.cfg.WriteBehind && !game.IsFinished() && game.StoreDurability() == engine.DurabilityBatch {
+		return nil
+	}
+
+	return ms.write(ctx, newGameStore(game), ms.games.changes(game.ID))
+}
+
+// WriteBehind tells whether the service holds the latest state of its games, ahead of the repo
+func (ms *MineSweeperGameSvcImpl) WriteBehind() bool {
+	return ms.cfg.WriteBehind
+}
+
+// Flush stores every game changed since it was last stored, in batches
+func (ms *MineSweeperGameSvcImpl) Flush(ctx context.Context) (flushed int, err error) {
+	games := ms.games.dirtyGames()
+	batch := ms.cfg.FlushBatch
+	if batch < 1 {
+		batch = DefaultFlushBatch
+	}
+	for start := 0; start < len(games); start += batch {
+		end := start + batch
+		if end > len(games) {
+			end = len(games)
+		}
+		gamesStore := make([]*models.Game, 0, end-start)
+		changes := make([]uint64, 0, end-start)
+		for _, game := range games[start:end] {
+			gameStore, gameChanges := ms.snapshot(game)
+			gamesStore = append(gamesStore, gameStore)
+			changes = append(changes, gameChanges)
+		}
+		if err = ms.gameRepo.UpdateGameStates(ctx, gamesStore); err != nil {
+			return flushed, err
+		}
+		for i, gameStore := range gamesStore {
+			ms.games.markStored(gameStore.ID, changes[i])
+		}
+		flushed += end - start
+	}
+
+	return flushed, nil
+}
+
+// StartWorkers runs the background workers of the service until the context is done: the reaper,
+// which abandons the idle games and evicts the expired ones from memory, and in write-behind mode
+// the flusher, which stores the changed games
+func (ms *MineSweeperGameSvcImpl) StartWorkers(ctx context.Context) {
+	every(ctx, ms.cfg.ReapInterval, DefaultReapInterval, ms.reap)
+	if ms.cfg.WriteBehind {
+		every(ctx, ms.cfg.FlushInterval, DefaultFlushInterval, func(ctx context.Context) {
+			if flushed, err := ms.Flush(ctx); err != nil {
+				ms.log.Error().Err(err).Int("flushed", flushed).Msg("Games could not be flushed")
+			}
+		})
+	}
+}
+
+// every runs work in the background on each interval, until the context is done
+func every(ctx context.Context, interval, fallback time.Duration, work func(ctx context.Context)) {
+	if interval <= 0 {
+		interval = fallback
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				work(ctx)
+			}
+		}
+	}()
+}
+
+func (ms *MineSweeperGameSvcImpl) reap(ctx context.Context) {
+	abandoned, err := ms.AbandonIdleGames(ctx)
+	if err != nil {
+		ms.log.Error().Err(err).Msg("Idle games could not be abandoned")
+	}
+	evicted := ms.games.expire()
+	for _, entry := range evicted {
+		ms.evict(entry)
+	}
+	if len(abandoned) > 0 || len(evicted) > 0 {
+		ms.log.Info().Int("abandoned", len(abandoned)).Int("evicted", len(evicted)).Msg("Games reaped")
+	}
+}
+
+// cache keeps the game in memory, storing the game evicted to make room for it if it changed. The caller
+// may hold the lock of the game cached, the evicted one is stored apart as locking it here could deadlock
+func (ms *MineSweeperGameSvcImpl) cache(game *engine.Game, dirty bool) {
+	if evicted := ms.games.put(game, dirty); evicted != nil {
+		go ms.evict(evicted)
+	}
+}
+
+// evict stores a game evicted from memory if it changed, once the moves being played in it are done
+func (ms *MineSweeperGameSvcImpl) evict(entry *cacheEntry) {
+	ms.events.forget(entry.game.ID)
+	unlock := ms.lock(entry.game.ID)
+	if !entry.dirty() {
+		unlock()
+		return
+	}
+	gameStore := newGameStore(entry.game)
+	unlock()
+	if err := ms.gameRepo.UpdateGameStates(context.Background(), []*models.Game{gameStore}); err != nil {
+		ms.log.Error().Err(err).Str("gameID", entry.game.ID).Msg("Evicted game could not be stored")
+	}
+}
+
+// flush stores the state of a game held in memory
+func (ms *MineSweeperGameSvcImpl) flush(ctx context.Context, game *engine.Game) error {
+	gameStore, changes := ms.snapshot(game)
+
+	return ms.write(ctx, gameStore, changes)
+}
+
+// write stores the columns of a game built along the changes they hold
+func (ms *MineSweeperGameSvcImpl) write(ctx context.Context, gameStore *models.Game, changes uint64) error {
+	if err := ms.gameRepo.UpdateGameStates(ctx, []*models.Game{gameStore}); err != nil {
+		return err
+	}
+	ms.games.markStored(gameStore.ID, changes)
+
+	return nil
+}
+
+// loadForUpdate gets the latest state of a game whose lock is held. In write-behind mode the service holds
+// it, otherwise the stored state is read as another instance may have changed it.
+func (ms *MineSweeperGameSvcImpl) loadForUpdate(ctx context.Context, gameID string) (*engine.Game, error) {
+	cached, found := ms.games.get(gameID)
+	if found && ms.cfg.WriteBehind {
+		return cached, nil
+	}
+
+	return ms.read(ctx, gameID, cached)
+}
+
+// read reads the stored state of a game whose lock is held and keeps it in memory, unless the game held is
+// as recent. Corrupted states are refused with ErrCorruptedGameState
+func (ms *MineSweeperGameSvcImpl) read(ctx context.Context, gameID string, cached *engine.Game) (*engine.Game, error) {
+	gameStore, err := ms.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	game, err := gameStore.GetGameState()
+	if err == nil && cached != nil && cached.Version == game.Version {
+		return cached, nil
+	}
+	if err == nil {
+		err = ms.validate(game)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorruptedGameState, err)
+	}
+	ms.cache(game, false)
+
+	return game, nil
+}
+
+// snapshot builds the stored columns of a game held in memory in between its moves, along the changes
+// they hold. The game is written after its lock is released
+func (ms *MineSweeperGameSvcImpl) snapshot(game *engine.Game) (gameStore *models.Game, changes uint64) {
+	defer ms.lock(game.ID)()
+
+	return newGameStore(game), ms.games.changes(game.ID)
+}
+
+// newGameStore builds the stored columns of a game out of its state
+func newGameStore(game *engine.Game) *models.Game {
+	gameStore := &models.Game{BaseModel: models.BaseModel{ID: game.ID}}
+	gameStore.UpdateGameState(game)
+
+	return gameStore
+}
+
+func (ms *MineSweeperGameSvcImpl) validate(game *engine.Game) (err error) {
+	if err = game.Validate(); err == nil {
+		return nil
+	}
+	ms.log.Warn().Err(err).Str("gameID", game.ID).Msg("Inconsistent game state")
+	if !ms.cfg.RepairState {
+		return err
+	}
+	game.Repair()
+	if err = game.Validate(); err != nil {
+		ms.log.Error().Err(err).Str("gameID", game.ID).Msg("Game state could not be repaired")
+		return err
+	}
+	ms.log.Info().Str("gameID", game.ID).Msg("Game state repaired")
 
 	return nil
 }
@@ -0,0 +1,107 @@
+package games
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+)
+
+// Retry godoc
+// @Summary Retries the board of a game of minesweeper
+// @Description Creates a new game with the same board and rules as the game, linked to it as its previous attempt, the game itself is left untouched
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 403 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/retry [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) Retry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	// A retry holds the whole board, other players only get to retry the games already finished
+	if !services.CanManage(currentUser, gameStore) && !game.IsFinished() {
+		svc.responseHelper.Error(w, r, http.StatusForbidden,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "retry"))
+		return
+	}
+	retry, err := svc.gameSvc.RetryGame(ctx, game, currentUser)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusCreated, retry.ID)
+}
+
+// Attempts godoc
+// @Summary Lists the attempts at the board of a game of minesweeper
+// @Description Lists the attempts of the caller at the board of the game, from the first one on, with their outcome, moves and time played
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/attempts [get]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+func (svc *GameHandlerSvc) Attempts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	firstAttemptID := game.FirstAttemptID
+	if firstAttemptID == "" {
+		firstAttemptID = game.ID
+	}
+	gamesStore, err := svc.gameRepo.ListAttempts(ctx, firstAttemptID, currentUser.ID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	attempts := make([]*engine.Attempt, 0, len(gamesStore))
+	for _, gameStore := range gamesStore {
+		// Attempts being played are summarized out of the engine, their stored state may be behind
+		attempt, err := svc.gameEngineSvc.GetGame(gameStore.ID)
+		if err != nil {
+			if attempt, err = gameStore.GetGameState(); err != nil {
+				svc.log.Warn().Err(err).Str("gameID", gameStore.ID).Msg("Attempt left out of the list")
+				continue
+			}
+		}
+		attempts = append(attempts, attempt.Attempt())
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, attempts)
+}
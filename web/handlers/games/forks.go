@@ -0,0 +1,211 @@
+package games
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+	"gorm.io/gorm"
+)
+
+// Fork godoc
+// @Summary Forks a game of minesweeper
+// @Description Copies the board, the moves and the timer of a game into a new unranked game owned by the caller, forks of lost games continue from right before the losing move
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 403 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/fork [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) Fork(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	// A fork holds the whole board, other players only get to fork the games already finished
+	if !services.CanManage(currentUser, gameStore) && !game.IsFinished() {
+		svc.responseHelper.Error(w, r, http.StatusForbidden,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "fork"))
+		return
+	}
+	fork, err := svc.gameSvc.ForkGame(ctx, game, currentUser, false)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusCreated, fork.ID)
+}
+
+// Save godoc
+// @Summary Saves a game of minesweeper in a named slot
+// @Description Snapshots the current state of a game in a named save slot, saving again with the same name overwrites the slot
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 403 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/saves [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param saveInput body requests.GameSaveInput true "Save Input"
+func (svc *GameHandlerSvc) Save(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	var input requests.GameSaveInput
+	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	if !input.Valid() {
+		svc.responseHelper.Error(w, r, http.StatusBadRequest,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeReqHelperInvalidValue, "name", 0))
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !services.CanManage(currentUser, gameStore) {
+		svc.responseHelper.Error(w, r, http.StatusForbidden,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "save"))
+		return
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	save := &models.GameSave{
+		GameID:      gameID,
+		Name:        input.Name,
+		CreatedByID: currentUser.ID,
+	}
+	save.UpdateGameState(game)
+	if _, err = svc.gameRepo.UpsertSave(ctx, save); err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, save)
+}
+
+// ListSaves godoc
+// @Summary Lists the save slots of a game of minesweeper
+// @Description Lists the named save slots of a game, oldest first
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/saves [get]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+func (svc *GameHandlerSvc) ListSaves(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	gameID := path.Base(path.Dir(r.URL.Path))
+	// Saves of deleted games are not listed
+	if _, err := svc.gameRepo.Read(ctx, gameID); err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	saves, err := svc.gameRepo.ListSaves(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, saves)
+}
+
+// RestoreSave godoc
+// @Summary Restores a save slot of a game of minesweeper
+// @Description Creates a practice game owned by the caller out of a save slot, practice games are unranked
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 403 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/saves/{name}/restore [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param name path string true "Save slot name"
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) RestoreSave(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	// /games/{id}/saves/{name}/restore
+	name := path.Base(path.Dir(r.URL.Path))
+	gameID := path.Base(path.Dir(path.Dir(path.Dir(r.URL.Path))))
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	// Saves hold the whole board of games still played
+	if !services.CanManage(currentUser, gameStore) {
+		svc.responseHelper.Error(w, r, http.StatusForbidden,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "restore the saves of"))
+		return
+	}
+	save, err := svc.gameRepo.ReadSave(ctx, gameID, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		svc.responseHelper.Error(w, r, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	saved, err := save.GetGameState()
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError,
+			svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeCorruptedGameState, gameID, err.Error()))
+		return
+	}
+	game, err := svc.gameSvc.ForkGame(ctx, saved, currentUser, true)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusCreated, game.ID)
+}
@@ -1,31 +1,57 @@
 package games
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"net/http"
 	"path"
+	"strconv"
 
-	"github.com/cmelgarejo/minesweeper-svc/database/models"
 	"github.com/cmelgarejo/minesweeper-svc/database/repo"
 	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
-	"github.com/cmelgarejo/minesweeper-svc/utils"
 	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
 	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
 	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
 	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
 	"github.com/cmelgarejo/minesweeper-svc/web/services"
 	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
 	"github.com/loopcontext/msgcat"
 )
 
 type GameHandler interface {
 	Create(w http.ResponseWriter, r *http.Request)
+	CreateFromCode(w http.ResponseWriter, r *http.Request)
 	Read(w http.ResponseWriter, r *http.Request)
+	View(w http.ResponseWriter, r *http.Request)
+	Difficulty(w http.ResponseWriter, r *http.Request)
 	Click(w http.ResponseWriter, r *http.Request)
+	Moves(w http.ResponseWriter, r *http.Request)
+	Fork(w http.ResponseWriter, r *http.Request)
+	Save(w http.ResponseWriter, r *http.Request)
+	ListSaves(w http.ResponseWriter, r *http.Request)
+	RestoreSave(w http.ResponseWriter, r *http.Request)
+	Retry(w http.ResponseWriter, r *http.Request)
+	Attempts(w http.ResponseWriter, r *http.Request)
+	Forfeit(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Restore(w http.ResponseWriter, r *http.Request)
+	Socket(conn *websocket.Conn)
+	Stream(c *fiber.Ctx) error
+	// Resources of the v2 routes
+	CreateGame(w http.ResponseWriter, r *http.Request)
+	GetGame(w http.ResponseWriter, r *http.Request)
+	DeleteGame(w http.ResponseWriter, r *http.Request)
+	StartGame(w http.ResponseWriter, r *http.Request)
+	Reveal(w http.ResponseWriter, r *http.Request)
+	Flag(w http.ResponseWriter, r *http.Request)
+	Unflag(w http.ResponseWriter, r *http.Request)
+	PlayMoves(w http.ResponseWriter, r *http.Request)
 	// For Admins
 	List(w http.ResponseWriter, r *http.Request)
 	Start(w http.ResponseWriter, r *http.Request)
+	Abandon(w http.ResponseWriter, r *http.Request)
 }
 
 type GameHandlerSvc struct {
@@ -34,6 +60,7 @@ type GameHandlerSvc struct {
 	gameRepo       repo.GameRepo
 	gameEngineSvc  service.MineSweeperGameSvc
 	authSvc        services.AuthSvc
+	gameSvc        services.GameSvc
 	requestHelper  common.RequestHelper
 	responseHelper common.ResponseHelper
 }
@@ -48,6 +75,7 @@ func NewGameHandlerSvc(log logger.Logger, catalog msgcat.MessageCatalog,
 		gameRepo:       gameRepo,
 		gameEngineSvc:  gameEngineSvc,
 		authSvc:        authSvc,
+		gameSvc:        services.NewGameSvcImpl(catalog, gameRepo, gameEngineSvc),
 		responseHelper: responseHelper,
 		requestHelper:  requestHelper,
 	}
@@ -65,48 +93,79 @@ func NewGameHandlerSvc(log logger.Logger, catalog msgcat.MessageCatalog,
 // @Failure 500 {object} responses.ResponseError
 // @Router /v1/api/games [post]
 // @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
 // @Param gameInput body requests.GameCreateInput true "Game Input"
 func (svc *GameHandlerSvc) Create(w http.ResponseWriter, r *http.Request) {
+	game, status, err := svc.createGame(w, r)
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusCreated, game.ID)
+}
+
+// createGame creates and stores the game of the request for the current user, or returns the status to
+// answer the error with
+func (svc *GameHandlerSvc) createGame(w http.ResponseWriter, r *http.Request) (*engine.Game, int, error) {
 	ctx := r.Context()
 	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
 	if err != nil {
-		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	var input requests.GameCreateInput
 	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
 	if err != nil {
-		svc.responseHelper.Error(w, r, status, err)
-		return
+		return nil, status, err
 	}
-	game, err := svc.gameEngineSvc.CreateGame(input.Rows, input.Cols, input.Mines, currentUser.Fullname)
+	game, _, err := svc.gameSvc.CreateGame(ctx, input, currentUser)
+	switch {
+	case errors.Is(err, services.ErrInvalidGameRules):
+		return nil, http.StatusBadRequest, err
+	case errors.Is(err, engine.ErrTierNotReached):
+		return nil, http.StatusUnprocessableEntity, err
+	case err != nil:
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return game, http.StatusCreated, nil
+}
+
+// CreateFromCode godoc
+// @Summary Creates a game of minesweeper out of a board code
+// @Description Creates a game with the same board as the game the code was taken from, finished games show their board code
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/codes/{code} [post]
+// @Param code path string true "Board code"
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) CreateFromCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
 	if err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	var mf struct {
-		Minefield [][]engine.Field `json:"mineField"`
-	}
-	mf.Minefield = game.MineField
-	b, _ := json.Marshal(mf)
-	gameStore := &models.Game{
-		Rows:        input.Rows,
-		Cols:        input.Cols,
-		Mines:       input.Mines,
-		CreatedByID: currentUser.Fullname,
-	}
-	err = json.Unmarshal(b, &gameStore.GameState)
-	if err != nil {
+	code := path.Base(r.URL.Path)
+	game, err := svc.gameEngineSvc.CreateGameFromCode(code, currentUser.Fullname)
+	if errors.Is(err, engine.ErrInvalidBoardCode) || errors.Is(err, engine.ErrGeneratorVersion) {
+		svc.responseHelper.Error(w, r, http.StatusBadRequest,
+			svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeInvalidBoardCode, code, err.Error()))
+		return
+	} else if err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	gameStore.ID = game.ID
-	_, err = svc.gameRepo.UpsertGame(ctx, nil, gameStore)
-	if err != nil {
+	if _, err = svc.gameSvc.StoreNewGame(ctx, game, currentUser); err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	svc.responseHelper.Send(w, r, http.StatusOK, game.ID)
+	svc.responseHelper.Send(w, r, http.StatusCreated, game.ID)
 }
 
 // Read godoc
@@ -120,36 +179,128 @@ func (svc *GameHandlerSvc) Create(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} responses.ResponseError
 // @Failure 500 {object} responses.ResponseError
 // @Router /v1/api/games/{id} [get]
+// @Param encoding query string false "Mine field encoding, compact packs it as a base64 board" Enums(compact)
 // @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
 // @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
 func (svc *GameHandlerSvc) Read(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
 	gameID := path.Base(r.URL.Path)
-	game, err := svc.gameEngineSvc.GetGame(gameID)
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, svc.present(r, game, currentUser.Fullname))
+}
+
+// View godoc
+// @Summary Gets a window of the board of a minesweeper game
+// @Description Gets a window of the board, endless games accept any coordinate, even negative ones
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/view [get]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param row query int false "Top row of the window" default(0)
+// @Param col query int false "Left col of the window" default(0)
+// @Param rows query int false "Rows of the window" default(20)
+// @Param cols query int false "Cols of the window" default(20)
+func (svc *GameHandlerSvc) View(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
 	if err != nil {
-		// if the game is not cached in the game engine service, lets pick from db
-		if errors.Is(err, service.ErrGameNotFound) {
-			gameStore, err := svc.gameRepo.Read(ctx, gameID)
-			if err == nil {
-				game = gameStore.GetGameState()
-			}
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	var window [4]int
+	for i, param := range []string{"row", "col", "rows", "cols"} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
 		}
+		n, err := strconv.Atoi(value)
 		if err != nil {
-			svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+			svc.responseHelper.Error(w, r, http.StatusBadRequest,
+				svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeReqHelperInvalidValue, param, 0))
 			return
 		}
+		window[i] = n
 	}
-
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
 	if err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	svc.responseHelper.Send(w, r, http.StatusOK, game)
+	svc.responseHelper.Send(w, r, http.StatusOK,
+		game.Viewport(currentUser.Fullname, window[0], window[1], window[2], window[3]))
+}
+
+// Difficulty godoc
+// @Summary Rates the difficulty of the board of a minesweeper game
+// @Description Rates the board using its 3BV, the safe fields a deductive solver can't reach without guessing, its openings and its mine density
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/difficulty [get]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+func (svc *GameHandlerSvc) Difficulty(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	gameID := path.Base(path.Dir(r.URL.Path))
+	difficulty, err := svc.gameSvc.RateGame(ctx, gameID)
+	if errors.Is(err, engine.ErrNotRateable) {
+		svc.responseHelper.Error(w, r, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, difficulty)
+}
+
+// present returns what the player is allowed to see of the game, fog games are projected for the player
+func (svc *GameHandlerSvc) present(r *http.Request, game *engine.Game, player string) interface{} {
+	if game.Rules.Fog != nil {
+		return game.Project(player)
+	}
+	if compactRequested(r) {
+		return game.Compact()
+	}
+
+	return game
+}
+
+// ResponseDelta is the response mode of moves answered with what they changed, see engine.Delta
+const ResponseDelta = "delta"
+
+// deltaRequested tells whether the client asked for only what a move changed instead of the whole game
+func deltaRequested(r *http.Request) bool {
+	return r.URL.Query().Get("response") == ResponseDelta
+}
+
+// compactRequested tells whether the client asked for the mine fields in the compact board encoding
+func compactRequested(r *http.Request) bool {
+	return r.URL.Query().Get("encoding") == engine.EncodingCompact
 }
 
 // Click godoc
 // @Summary Clicks field on a game of minesweeper
-// @Description Clicks field on a game of minesweeper and returns the mine field state
+// @Description Clicks field on a game of minesweeper and returns the mine field state, losing clicks tell the defeat by the status of the game
 // @Tags game
 // @Accept json
 // @Produce json
@@ -158,8 +309,11 @@ func (svc *GameHandlerSvc) Read(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} responses.ResponseError
 // @Failure 500 {object} responses.ResponseError
 // @Router /v1/api/games/{id} [patch]
+// @Param encoding query string false "Mine field encoding, compact packs it as a base64 board" Enums(compact)
+// @Param response query string false "Response mode, delta returns only the changed cells, the status and the version of the game, defeats included" Enums(delta)
 // @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
 // @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
 // @Param gameInput body requests.GameInput true "Game Input"
 func (svc *GameHandlerSvc) Click(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -177,41 +331,26 @@ func (svc *GameHandlerSvc) Click(w http.ResponseWriter, r *http.Request) {
 	}
 	// Get game id
 	gameID := path.Base(r.URL.Path)
-	// Get game data from store and sync
-	gameStore, err := svc.gameRepo.Read(ctx, gameID)
-	if err != nil {
-		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	_ = svc.gameEngineSvc.UpdateGameState(gameID, gameStore.GetGameState())
-	// Click in the game engine
-	err = svc.gameEngineSvc.Click(gameID, currentUser.Fullname, input.GetClickType(), input.Row, input.Col)
-	if err != nil {
-		if errors.Is(err, engine.ErrDefeat) {
-			svc.responseHelper.Error(w, r, http.StatusInternalServerError,
-				svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeTotalDefeat, input.Row, input.Col))
-			gameStore.Status = engine.GameStatusDefeat
-		} else {
-			svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-			return
-		}
-	}
-	game, err := svc.gameEngineSvc.GetGame(gameID)
-	if err != nil {
-		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	gameStore.UpdateGameState(game)
-	_, err = svc.gameRepo.UpsertGame(ctx, &gameID, gameStore)
-	if err != nil {
+	game, delta, err := svc.play(ctx, gameID, currentUser.Fullname, input.GetClickType(), input.Row, input.Col)
+	// A losing click was played all the same, the game and the delta tell the defeat by their status
+	if err != nil && !errors.Is(err, engine.ErrDefeat) {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	// Should the error been pushed in the
-	if gameStore.Status == engine.GameStatusDefeat {
+	if deltaRequested(r) {
+		svc.responseHelper.Send(w, r, http.StatusOK, game.DeltaFor(currentUser.Fullname, delta))
 		return
 	}
-	svc.responseHelper.Send(w, r, http.StatusOK, game)
+	svc.responseHelper.Send(w, r, http.StatusOK, svc.present(r, game, currentUser.Fullname))
+}
+
+// play clicks a field of a game for the player and stores the game, returning what the click changed.
+// A losing click returns the game and its delta along engine.ErrDefeat.
+func (svc *GameHandlerSvc) play(ctx context.Context, gameID, player string, clickType engine.ClickType,
+	row, col int) (*engine.Game, *engine.Delta, error) {
+	game, delta, err := svc.gameEngineSvc.Click(ctx, gameID, player, clickType, row, col)
+
+	return game, delta, svc.gameSvc.GameError(ctx, gameID, err)
 }
 
 // List godoc
@@ -225,6 +364,7 @@ func (svc *GameHandlerSvc) Click(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} responses.ResponseError
 // @Failure 500 {object} responses.ResponseError
 // @Router /v1/api/games [get]
+// @Param encoding query string false "Mine field encoding, compact packs it as a base64 board" Enums(compact)
 // @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
 func (svc *GameHandlerSvc) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -236,23 +376,41 @@ func (svc *GameHandlerSvc) List(w http.ResponseWriter, r *http.Request) {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	var list map[string]*engine.Game
-	list, err = svc.gameEngineSvc.GetGameList()
-	if len(list) < 1 {
-		gamesStore, err := svc.gameRepo.List(ctx)
-		if err == nil {
-			for _, game := range gamesStore {
-				list[game.ID] = &engine.Game{}
-				b, _ := utils.ToJSONBytes(game.GameState)
-				_ = utils.ToObject(b, list[game.ID])
-
-			}
-		}
+	gamesStore, err := svc.gameRepo.List(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
 	}
+	// The games held in memory may be ahead of the stored ones, the rest are decoded without caching them
+	cached, err := svc.gameEngineSvc.GetGameList()
 	if err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	list := make(map[string]*engine.Game, len(gamesStore))
+	for _, gameStore := range gamesStore {
+		if game, found := cached[gameStore.ID]; found {
+			list[gameStore.ID] = game
+			continue
+		}
+		game, err := gameStore.GetGameState()
+		if err == nil {
+			err = game.Validate()
+		}
+		if err != nil {
+			svc.log.Warn().Err(err).Str("gameID", gameStore.ID).Msg("Game left out of the list")
+			continue
+		}
+		list[gameStore.ID] = game
+	}
+	if compactRequested(r) {
+		compactList := make(map[string]*engine.CompactGame, len(list))
+		for id, game := range list {
+			compactList[id] = game.Compact()
+		}
+		svc.responseHelper.Send(w, r, http.StatusOK, compactList)
+		return
+	}
 	svc.responseHelper.Send(w, r, http.StatusOK, list)
 }
 
@@ -267,42 +425,32 @@ func (svc *GameHandlerSvc) List(w http.ResponseWriter, r *http.Request) {
 // @Failure 404 {object} responses.ResponseError
 // @Failure 500 {object} responses.ResponseError
 // @Router /v1/api/games/start/{id} [post]
+// @Param encoding query string false "Mine field encoding, compact packs it as a base64 board" Enums(compact)
 // @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
 // @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
 func (svc *GameHandlerSvc) Start(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	// FUTURE: I could store who started then game...
-	// currentUser, err := svc.authSvc.GetCurrentUser(ctx)
-	// if err != nil {
-	// 	svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-	// 	return
-	// }
-	// Get game id
-	gameID := path.Base(r.URL.Path)
-	// Get game data from store and sync
-	gameStore, err := svc.gameRepo.Read(ctx, gameID)
-	if err != nil {
-		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	_ = svc.gameEngineSvc.UpdateGameState(gameID, gameStore.GetGameState())
-	// Start the game
-	err = svc.gameEngineSvc.StartGame(gameID)
-	if err != nil {
-		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
-		return
-	}
-	game, err := svc.gameEngineSvc.GetGame(gameID)
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
 	if err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	gameStore.UpdateGameState(game)
-	_, err = svc.gameRepo.UpsertGame(ctx, &gameID, gameStore)
+	// Get game id
+	gameID := path.Base(r.URL.Path)
+	game, err := svc.startGame(ctx, gameID)
 	if err != nil {
 		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	svc.responseHelper.Send(w, r, http.StatusOK, game)
+	svc.responseHelper.Send(w, r, http.StatusOK, svc.present(r, game, currentUser.Fullname))
+}
+
+// startGame starts a game and stores it
+func (svc *GameHandlerSvc) startGame(ctx context.Context, gameID string) (*engine.Game, error) {
+	game, err := svc.gameEngineSvc.StartGame(ctx, gameID)
+
+	return game, svc.gameSvc.GameError(ctx, gameID, err)
 }
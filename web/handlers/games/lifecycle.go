@@ -0,0 +1,183 @@
+package games
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+)
+
+// Forfeit godoc
+// @Summary Forfeits a game of minesweeper
+// @Description Gives up a game that didn't finish, the game moves to the forfeited status and its board is revealed
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/forfeit [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) Forfeit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !services.CanManage(currentUser, gameStore) {
+		svc.responseHelper.Error(w, r, http.StatusForbidden,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "forfeit"))
+		return
+	}
+	game, err := svc.gameEngineSvc.Forfeit(ctx, gameID, currentUser.Fullname)
+	if errors.Is(err, engine.ErrNotActive) {
+		svc.responseHelper.Error(w, r, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, svc.gameSvc.GameError(ctx, gameID, err))
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, svc.present(r, game, currentUser.Fullname))
+}
+
+// Abandon godoc
+// @Summary Abandons the idle games
+// @Description Abandons every started game that wasn't played for longer than the configured idle timeout, only Admins can do it
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/abandon [post]
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) Abandon(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil || !currentUser.Admin {
+		if err == nil {
+			err = svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeUnauthorized)
+		}
+		svc.responseHelper.Error(w, r, http.StatusForbidden, err)
+		return
+	}
+	abandoned, err := svc.gameEngineSvc.AbandonIdleGames(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, abandoned)
+}
+
+// Delete godoc
+// @Summary Deletes a game of minesweeper
+// @Description Soft deletes a game, which can be restored later. Admins can delete it for good with hard=true
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id} [delete]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param hard query bool false "Delete the game for good, only Admins can do it"
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(r.URL.Path)
+	if status, err := svc.deleteGame(r, currentUser, gameID); err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, gameID)
+}
+
+// deleteGame deletes a game for the user, for good when the request asks for it, or returns the status to
+// answer the error with
+func (svc *GameHandlerSvc) deleteGame(r *http.Request, user *models.User, gameID string) (int, error) {
+	ctx := r.Context()
+	hard := r.URL.Query().Get("hard") == "true"
+	if hard && !user.Admin {
+		return http.StatusForbidden, svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeUnauthorized)
+	}
+	gameStore, err := svc.gameRepo.ReadWithDeleted(ctx, gameID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !services.CanManage(user, gameStore) {
+		return http.StatusForbidden, svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "delete")
+	}
+	if hard {
+		err = svc.gameRepo.HardDelete(ctx, gameID)
+	} else {
+		err = svc.gameRepo.Delete(ctx, gameID)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	svc.gameEngineSvc.RemoveGame(gameID)
+
+	return http.StatusNoContent, nil
+}
+
+// Restore godoc
+// @Summary Restores a deleted game of minesweeper
+// @Description Brings back a soft deleted game, games deleted for good can't be restored
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/restore [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) Restore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	gameID := path.Base(path.Dir(r.URL.Path))
+	gameStore, err := svc.gameRepo.ReadWithDeleted(ctx, gameID)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !services.CanManage(currentUser, gameStore) {
+		svc.responseHelper.Error(w, r, http.StatusForbidden,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGameNotOwned, gameID, "restore"))
+		return
+	}
+	if err = svc.gameRepo.Restore(ctx, gameID); err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, gameID)
+}
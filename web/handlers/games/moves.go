@@ -0,0 +1,61 @@
+package games
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+)
+
+// Moves godoc
+// @Summary Plays a batch of moves in a game of minesweeper
+// @Description Plays the clicks and flags in order, with no other move played in between and the game stored once. The moves after one ending the game are skipped, the ones that can't be played are reported and the batch goes on.
+// @Description Returns the outcome of each move with what it changed, and the version and status of the game after the batch.
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/moves [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param movesInput body requests.GameMovesInput true "Moves Input"
+func (svc *GameHandlerSvc) Moves(w http.ResponseWriter, r *http.Request) {
+	result, status, err := svc.playMoves(w, r, path.Base(path.Dir(r.URL.Path)))
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, result)
+}
+
+// playMoves plays the batch of moves of the request in the game and stores it once, returning the outcome
+// of the moves as the current user is allowed to see it, or the status to answer the error with
+func (svc *GameHandlerSvc) playMoves(w http.ResponseWriter, r *http.Request, gameID string) (
+	*engine.MovesResult, int, error) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	var input requests.GameMovesInput
+	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
+	if err != nil {
+		return nil, status, err
+	}
+	if !input.Valid() {
+		return nil, http.StatusBadRequest,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeInvalidMoves, requests.MovesMaxCount, len(input.Moves))
+	}
+	game, result, err := svc.gameEngineSvc.PlayMoves(ctx, gameID, currentUser.Fullname, input.GetMoves())
+	if err != nil {
+		return nil, http.StatusInternalServerError, svc.gameSvc.GameError(ctx, gameID, err)
+	}
+
+	return game.MovesFor(currentUser.Fullname, result), http.StatusOK, nil
+}
@@ -0,0 +1,266 @@
+package games
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GamesResourcePath is the path of the games resource of the v2 routes, created games are located under it
+const GamesResourcePath = "/v2/api/games"
+
+// CreateGame godoc
+// @Summary Creates a game of minesweeper
+// @Description Creates a game of minesweeper and returns it, located by the Location header
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 201 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 422 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games [post]
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param gameInput body requests.GameCreateInput true "Game Input"
+func (svc *GameHandlerSvc) CreateGame(w http.ResponseWriter, r *http.Request) {
+	game, status, err := svc.createGame(w, r)
+	if err != nil {
+		svc.resourceError(w, r, status, err)
+		return
+	}
+	w.Header().Set(fiber.HeaderLocation, GamesResourcePath+"/"+game.ID)
+	svc.responseHelper.Send(w, r, http.StatusCreated, svc.present(r, game, game.CreatedBy))
+}
+
+// GetGame godoc
+// @Summary Gets a game of minesweeper
+// @Description Gets a game of minesweeper as the current user is allowed to see it
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id} [get]
+// @Param encoding query string false "Mine field encoding, compact packs it as a base64 board" Enums(compact)
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+func (svc *GameHandlerSvc) GetGame(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, common.RouteParam(r, "id"))
+	if err != nil {
+		svc.resourceError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, svc.present(r, game, currentUser.Fullname))
+}
+
+// DeleteGame godoc
+// @Summary Deletes a game of minesweeper
+// @Description Soft deletes a game, which can be restored later. Admins can delete it for good with hard=true
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 403 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id} [delete]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param hard query bool false "Delete the game for good, only Admins can do it"
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) DeleteGame(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := svc.authSvc.GetCurrentUser(r.Context())
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	status, err := svc.deleteGame(r, currentUser, common.RouteParam(r, "id"))
+	if err != nil {
+		svc.resourceError(w, r, status, err)
+		return
+	}
+	w.WriteHeader(status)
+}
+
+// StartGame godoc
+// @Summary Starts a game of minesweeper
+// @Description Starts a created game of minesweeper and returns it, games already started conflict
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 404 {object} responses.ResponseError
+// @Failure 409 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id}/start [post]
+// @Param encoding query string false "Mine field encoding, compact packs it as a base64 board" Enums(compact)
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+func (svc *GameHandlerSvc) StartGame(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	game, err := svc.startGame(ctx, common.RouteParam(r, "id"))
+	if err != nil {
+		svc.resourceError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, svc.present(r, game, currentUser.Fullname))
+}
+
+// Reveal godoc
+// @Summary Reveals a field of a game of minesweeper
+// @Description Reveals a field, or with chord the fields around a revealed one once its mines are flagged, and returns what changed: the cells, the status and the version of the game, defeats included
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 409 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id}/reveals [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param revealInput body requests.RevealInput true "Reveal Input"
+func (svc *GameHandlerSvc) Reveal(w http.ResponseWriter, r *http.Request) {
+	var input requests.RevealInput
+	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	svc.playResource(w, r, input.GetClickType(), input.Row, input.Col)
+}
+
+// Flag godoc
+// @Summary Flags a field of a game of minesweeper
+// @Description Flags a field and returns what changed: the cells, the status and the version of the game
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 409 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id}/flags [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param flagInput body requests.FlagInput true "Flag Input"
+func (svc *GameHandlerSvc) Flag(w http.ResponseWriter, r *http.Request) {
+	var input requests.FlagInput
+	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	svc.playResource(w, r, engine.GameClickTypeFlag, input.Row, input.Col)
+}
+
+// Unflag godoc
+// @Summary Takes the flag off a field of a game of minesweeper
+// @Description Takes the flag off a field and returns what changed: the cells, the status and the version of the game. Fields without a flag are left as they are.
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 409 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id}/flags [delete]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param flagInput body requests.FlagInput true "Flag Input"
+func (svc *GameHandlerSvc) Unflag(w http.ResponseWriter, r *http.Request) {
+	var input requests.FlagInput
+	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	svc.playResource(w, r, engine.GameClickTypeUnflag, input.Row, input.Col)
+}
+
+// playResource plays a move of the current user in the game of the route, answering with what it changed
+func (svc *GameHandlerSvc) playResource(w http.ResponseWriter, r *http.Request, clickType engine.ClickType,
+	row, col int) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	game, delta, err := svc.play(ctx, common.RouteParam(r, "id"), currentUser.Fullname, clickType, row, col)
+	if err != nil && !errors.Is(err, engine.ErrDefeat) {
+		svc.resourceError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	// The delta tells the defeat by its status
+	svc.responseHelper.Send(w, r, http.StatusOK, game.DeltaFor(currentUser.Fullname, delta))
+}
+
+// PlayMoves godoc
+// @Summary Plays a batch of moves in a game of minesweeper
+// @Description Plays the clicks and flags in order, with no other move played in between and the game stored once. The moves after one ending the game are skipped, the ones that can't be played are reported and the batch goes on.
+// @Description Returns the outcome of each move with what it changed, and the version and status of the game after the batch.
+// @Tags game
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 400 {object} responses.ResponseError
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/games/{id}/moves [post]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param Idempotency-Key header string false "Retries sent with the same key get the response of the first request"
+// @Param movesInput body requests.GameMovesInput true "Moves Input"
+func (svc *GameHandlerSvc) PlayMoves(w http.ResponseWriter, r *http.Request) {
+	result, status, err := svc.playMoves(w, r, common.RouteParam(r, "id"))
+	if err != nil {
+		svc.resourceError(w, r, status, err)
+		return
+	}
+	svc.responseHelper.Send(w, r, http.StatusOK, result)
+}
+
+// resourceError answers an error of the v2 routes: missing games are not found, moves and starts the game
+// can't take conflict with its status or its fields, and fields out of the board are bad requests. Other
+// errors keep the status given.
+func (svc *GameHandlerSvc) resourceError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, service.ErrGameNotFound):
+		status = http.StatusNotFound
+		err = svc.catalog.WrapErrorWithCtx(r.Context(), err, codes.MsgCodeDBRecordsNotFound, "games")
+	case errors.Is(err, engine.ErrNotActive), errors.Is(err, engine.ErrNotStartable),
+		errors.Is(err, engine.ErrRevealed), errors.Is(err, engine.ErrFlagged):
+		status = http.StatusConflict
+	case errors.Is(err, engine.ErrOutOfBounds), errors.Is(err, engine.ErrUnsupportedClick):
+		status = http.StatusBadRequest
+	}
+	svc.responseHelper.Error(w, r, status, err)
+}
@@ -0,0 +1,132 @@
+package games
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/responses"
+	"github.com/gofiber/websocket/v2"
+	"github.com/loopcontext/msgcat"
+)
+
+// SocketErrorType is the type of the error messages sent over the game socket
+const SocketErrorType = "error"
+
+// Socket godoc
+// @Summary Plays a game of minesweeper over a WebSocket
+// @Description Upgrades to a WebSocket that pushes the events of the game as they happen (cell.revealed, flag.changed, game.started, game.finished, player.joined) carrying only the changed cells.
+// @Description Commands like {"type": "click", "row": 0, "col": 0, "clickType": "flag"} are played by the current user, failed ones are answered with an error message.
+// @Description Clients that can't set headers on the upgrade request can send the API key in the apiKey query parameter.
+// @Tags game
+// @Produce json
+// @Success 101 {object} engine.Event
+// @Failure 401 {object} responses.ResponseError
+// @Failure 426 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/ws [get]
+// @Router /v2/api/games/{id}/ws [get]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param X-API-KEY header string false "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param apiKey query string false "API Key, for clients that can't set headers"
+func (svc *GameHandlerSvc) Socket(conn *websocket.Conn) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	send := func(msg interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return conn.WriteJSON(msg)
+	}
+	currentUser, _ := conn.Locals(string(utils.CurrrentUserCtxKey)).(*models.User)
+	if currentUser == nil {
+		_ = send(svc.socketError(ctx, svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeUnauthorized)))
+		return
+	}
+	player := currentUser.Fullname
+	gameID := conn.Params("id")
+	if _, err := svc.gameSvc.LoadGame(ctx, gameID); err != nil {
+		_ = send(svc.socketError(ctx, err))
+		return
+	}
+	// The events get written until the subscription ends: the handler returning unsubscribes, while a
+	// subscriber falling behind or shutting down ends it, closing the socket for the client to reconnect
+	var writer sync.WaitGroup
+	done := make(chan struct{})
+	sub := svc.gameEngineSvc.Subscribe(gameID, player)
+	writer.Add(1)
+	defer writer.Wait()
+	defer svc.gameEngineSvc.Unsubscribe(sub)
+	defer close(done)
+	go func() {
+		defer writer.Done()
+		for e := range sub.Events {
+			if err := send(e); err != nil {
+				return
+			}
+		}
+		select {
+		case <-done:
+		default:
+			mu.Lock()
+			_ = conn.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "event stream ended"))
+			mu.Unlock()
+			_ = conn.Close()
+		}
+	}()
+	svc.gameEngineSvc.Join(gameID, player)
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				svc.log.Debug().Err(err).Str("gameID", gameID).Msg("Game socket closed")
+			}
+			return
+		}
+		var cmd requests.GameCommand
+		if err = json.Unmarshal(msg, &cmd); err != nil {
+			_ = send(svc.socketError(ctx, svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeReqHelperBadlyFormed)))
+			continue
+		}
+		if cmd.Type != requests.GameCommandClick {
+			_ = send(svc.socketError(ctx, svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeUnknownGameCommand,
+				cmd.Type, requests.GameCommandClick)))
+			continue
+		}
+		// Moves are answered by their events, which every socket of the game gets, defeats included
+		_, _, err = svc.play(ctx, gameID, player, cmd.GetClickType(), cmd.Row, cmd.Col)
+		if err != nil && !errors.Is(err, engine.ErrDefeat) {
+			_ = send(svc.socketError(ctx, err))
+		}
+	}
+}
+
+// socketError builds the error message of the game socket out of an error
+func (svc *GameHandlerSvc) socketError(ctx context.Context, err error) *responses.SocketError {
+	return &responses.SocketError{
+		Type:          SocketErrorType,
+		ResponseError: *svc.responseError(ctx, err),
+	}
+}
+
+// responseError builds an error response out of an error, for the handlers answering out of the
+// response helper, like the HTTP error responses
+func (svc *GameHandlerSvc) responseError(ctx context.Context, err error) *responses.ResponseError {
+	cde, ok := err.(*msgcat.DefaultError)
+	if !ok {
+		cde = svc.catalog.GetErrorWithCtx(ctx, 1, err.Error()).(*msgcat.DefaultError)
+	}
+
+	return &responses.ResponseError{
+		ResponseBase: responses.ResponseBase{
+			Code:    cde.ErrorCode(),
+			Message: cde.GetShortMessage(),
+			Details: cde.GetLongMessage(),
+		},
+	}
+}
@@ -0,0 +1,121 @@
+package games
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// StreamKeepAlive is how often an idle event stream gets a comment, so proxies don't close it
+const StreamKeepAlive = 15 * time.Second
+
+// HeaderLastEventID is sent by reconnecting event stream clients with the seq of the last event they got
+const HeaderLastEventID = "Last-Event-ID"
+
+// Stream godoc
+// @Summary Streams the events of a game of minesweeper
+// @Description Server-Sent Events stream of the game events (cell.revealed, flag.changed, game.started, game.finished, player.joined) carrying only the changed cells, each with its seq as id.
+// @Description Reconnecting clients send the seq of the last event they got in Last-Event-ID, or in the lastEventId query parameter, to get the events they missed. When those are gone a game.resync event asks them to read the game again.
+// @Description Clients that can't set headers can send the API key in the apiKey query parameter.
+// @Tags game
+// @Produce text/event-stream
+// @Success 200 {object} engine.Event
+// @Failure 400 {object} responses.ResponseError
+// @Failure 401 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v1/api/games/{id}/events [get]
+// @Router /v2/api/games/{id}/events [get]
+// @Param id path string true "Game ID" default(ef99fdfd88565827ad330d83aac5fbaa)
+// @Param Last-Event-ID header int false "Seq of the last event received"
+// @Param lastEventId query int false "Seq of the last event received, for clients that can't set headers"
+// @Param X-API-KEY header string false "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param apiKey query string false "API Key, for clients that can't set headers"
+func (svc *GameHandlerSvc) Stream(c *fiber.Ctx) error {
+	ctx := c.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(svc.responseError(ctx, err))
+	}
+	player := currentUser.Fullname
+	// The stream outlives the handler, which is when fiber reuses the values of the request
+	gameID := utils.CopyString(c.Params("id"))
+	lastEventID := c.Get(HeaderLastEventID, c.Query("lastEventId"))
+	var lastSeq uint64
+	if lastEventID != "" {
+		if lastSeq, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(svc.responseError(ctx,
+				svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeReqHelperInvalidValue, HeaderLastEventID, 0)))
+		}
+	}
+	if _, err = svc.gameSvc.LoadGame(ctx, gameID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(svc.responseError(ctx, err))
+	}
+	var sub *service.Subscription
+	var missed []engine.Event
+	if lastEventID != "" {
+		if sub, missed, err = svc.gameEngineSvc.Resume(ctx, gameID, player, lastSeq); err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(svc.responseError(ctx, svc.gameSvc.GameError(ctx, gameID, err)))
+		}
+	} else {
+		sub = svc.gameEngineSvc.Subscribe(gameID, player)
+	}
+	svc.gameEngineSvc.Join(gameID, player)
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	// The body is written once the handler returns, until the client goes away or the subscription ends
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer svc.gameEngineSvc.Unsubscribe(sub)
+		for _, e := range missed {
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+		keepAlive := time.NewTicker(StreamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case e, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if err := writeEvent(w, e); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// writeEvent writes a game event in the Server-Sent Events format
+func writeEvent(w *bufio.Writer, e engine.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+
+	return err
+}
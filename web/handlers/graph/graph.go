@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/gofiber/websocket/v2"
+	graphql "github.com/graph-gophers/graphql-go"
+	qerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/loopcontext/msgcat"
+)
+
+// Schema of the graph of games, users, moves and stats
+//
+//go:embed schema.graphql
+var Schema string
+
+// Limits of the queries, users and games link each other
+const (
+	MaxDepth       = 8  // deepest a query can nest its fields
+	MaxParallelism = 10 // resolvers a query runs at once
+)
+
+type GraphHandler interface {
+	Query(w http.ResponseWriter, r *http.Request)
+	Socket(conn *websocket.Conn)
+}
+
+type GraphHandlerSvc struct {
+	log            logger.Logger
+	catalog        msgcat.MessageCatalog
+	authSvc        services.AuthSvc
+	requestHelper  common.RequestHelper
+	responseHelper common.ResponseHelper
+	schema         *graphql.Schema
+}
+
+func NewGraphHandlerSvc(log logger.Logger, catalog msgcat.MessageCatalog, authRepo repo.AuthRepo,
+	gameRepo repo.GameRepo, gameEngineSvc service.MineSweeperGameSvc, authSvc services.AuthSvc,
+	requestHelper common.RequestHelper, responseHelper common.ResponseHelper) GraphHandler {
+	res := &resolver{
+		log:           log,
+		catalog:       catalog,
+		authRepo:      authRepo,
+		gameRepo:      gameRepo,
+		gameEngineSvc: gameEngineSvc,
+		authSvc:       authSvc,
+		gameSvc:       services.NewGameSvcImpl(catalog, gameRepo, gameEngineSvc),
+	}
+
+	return &GraphHandlerSvc{
+		log:            log,
+		catalog:        catalog,
+		authSvc:        authSvc,
+		requestHelper:  requestHelper,
+		responseHelper: responseHelper,
+		schema: graphql.MustParseSchema(Schema, res, graphql.MaxDepth(MaxDepth),
+			graphql.MaxParallelism(MaxParallelism)),
+	}
+}
+
+// QueryInput is a GraphQL request
+type QueryInput struct {
+	Query         string                 `json:"query" example:"{ me { username stats { played winRate } } }"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Query godoc
+// @Summary Runs a GraphQL query or mutation
+// @Description Queries the games, users, moves and stats of the graph, or creates, starts, clicks and flags games. Fields telling what a user owns are only resolved for the user itself and the admins.
+// @Description Answers in the GraphQL response format, errors carry the code and details of the message catalog in their extensions. Subscriptions to the game events go over a WebSocket upgrade of this same path, with the graphql-ws protocol.
+// @Tags graph
+// @Accept json
+// @Produce json
+// @Success 200 {object} object "GraphQL response with data and errors"
+// @Failure 400 {object} responses.ResponseError
+// @Failure 401 {object} responses.ResponseError
+// @Router /graphql [post]
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param queryInput body graph.QueryInput true "Query Input"
+func (svc *GraphHandlerSvc) Query(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := svc.authSvc.GetCurrentUser(r.Context())
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	var input QueryInput
+	err, status := svc.requestHelper.DecodeJSONBody(w, r, &input)
+	if err != nil {
+		svc.responseHelper.Error(w, r, status, err)
+		return
+	}
+	// The resolvers get contexts derived from the one of the request, which only tell the user by its key
+	ctx := context.WithValue(r.Context(), utils.CurrrentUserCtxKey, currentUser)
+	resp := svc.schema.Exec(ctx, input.Query, input.OperationName, input.Variables)
+	svc.catalogErrors(ctx, resp.Errors)
+	data, err := json.Marshal(resp)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set(common.ContentTypeKey, common.AppTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(data); err != nil {
+		svc.log.SendError(err)
+	}
+}
+
+// catalogErrors puts the code and details of the catalog errors the resolvers returned in the extensions
+// of the GraphQL errors, like the HTTP error responses tell them
+func (svc *GraphHandlerSvc) catalogErrors(ctx context.Context, errs []*qerrors.QueryError) {
+	for _, qerr := range errs {
+		if qerr.ResolverError == nil {
+			continue
+		}
+		cde, ok := qerr.ResolverError.(*msgcat.DefaultError)
+		if !ok {
+			cde = svc.catalog.GetErrorWithCtx(ctx, 1, qerr.ResolverError.Error()).(*msgcat.DefaultError)
+		}
+		qerr.Message = cde.GetShortMessage()
+		qerr.Extensions = map[string]interface{}{
+			"code":    cde.ErrorCode(),
+			"details": cde.GetLongMessage(),
+		}
+	}
+}
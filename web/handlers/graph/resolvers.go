@@ -0,0 +1,270 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/loopcontext/msgcat"
+)
+
+// resolver resolves the queries, mutations and subscriptions of the schema for the current user
+type resolver struct {
+	log           logger.Logger
+	catalog       msgcat.MessageCatalog
+	authRepo      repo.AuthRepo
+	gameRepo      repo.GameRepo
+	gameEngineSvc service.MineSweeperGameSvc
+	authSvc       services.AuthSvc
+	gameSvc       services.GameSvc
+}
+
+func (res *resolver) Me(ctx context.Context) (*userResolver, error) {
+	currentUser, err := res.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := res.authRepo.Read(ctx, currentUser.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userResolver{res: res, user: user}, nil
+}
+
+func (res *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	if err := res.allowed(ctx, string(args.ID), "user"); err != nil {
+		return nil, err
+	}
+	user, err := res.authRepo.Read(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &userResolver{res: res, user: user}, nil
+}
+
+func (res *resolver) Game(ctx context.Context, args struct{ ID graphql.ID }) (*gameResolver, error) {
+	gameStore, err := res.gameRepo.Read(ctx, string(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	// LoadGame hands out a copy taken under the lock of the game
+	game, err := res.gameSvc.LoadGame(ctx, gameStore.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gameResolver{res: res, game: game, gameStore: gameStore}, nil
+}
+
+func (res *resolver) Games(ctx context.Context, args struct{ Status *string }) ([]*gameResolver, error) {
+	currentUser, err := res.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.listGames(ctx, currentUser.ID, args.Status)
+}
+
+type fogInput struct {
+	Radius int32
+	Memory int32
+}
+
+type createGameInput struct {
+	Rows       int32
+	Cols       int32
+	Mines      int32
+	Type       *string
+	Kernel     *string
+	Arcade     *bool
+	Fog        *fogInput
+	Tier       *string
+	Durability *string
+}
+
+func (res *resolver) CreateGame(ctx context.Context, args struct{ Input createGameInput }) (*gameResolver, error) {
+	currentUser, err := res.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	input := requests.GameCreateInput{
+		Rows:       int(args.Input.Rows),
+		Cols:       int(args.Input.Cols),
+		Mines:      int(args.Input.Mines),
+		Type:       value(args.Input.Type),
+		Kernel:     value(args.Input.Kernel),
+		Arcade:     args.Input.Arcade != nil && *args.Input.Arcade,
+		Tier:       value(args.Input.Tier),
+		Durability: value(args.Input.Durability),
+	}
+	if fog := args.Input.Fog; fog != nil {
+		input.Fog = &requests.Fog{Radius: int(fog.Radius), Memory: int(fog.Memory)}
+	}
+	game, gameStore, err := res.gameSvc.CreateGame(ctx, input, currentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// The game just created is the one held in memory, it gets resolved out of a copy like the others
+	return &gameResolver{res: res, game: game.Copy(), gameStore: gameStore}, nil
+}
+
+func (res *resolver) StartGame(ctx context.Context, args struct{ ID graphql.ID }) (*gameResolver, error) {
+	gameID := string(args.ID)
+	gameStore, err := res.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	game, err := res.gameEngineSvc.StartGame(ctx, gameID)
+	if err != nil {
+		return nil, res.gameSvc.GameError(ctx, gameID, err)
+	}
+
+	return &gameResolver{res: res, game: game, gameStore: gameStore}, nil
+}
+
+type clickArgs struct {
+	ID    graphql.ID
+	Row   int32
+	Col   int32
+	Chord *bool
+}
+
+func (res *resolver) Click(ctx context.Context, args clickArgs) (*deltaResolver, error) {
+	input := requests.RevealInput{Row: int(args.Row), Col: int(args.Col), Chord: args.Chord != nil && *args.Chord}
+
+	return res.play(ctx, string(args.ID), input.GetClickType(), args.Row, args.Col)
+}
+
+func (res *resolver) Flag(ctx context.Context, args struct {
+	ID  graphql.ID
+	Row int32
+	Col int32
+}) (*deltaResolver, error) {
+	return res.play(ctx, string(args.ID), engine.GameClickTypeFlag, args.Row, args.Col)
+}
+
+// play clicks a field of a game for the current user and stores the game, returning what the click
+// changed. A losing click is told by the status of the delta.
+func (res *resolver) play(ctx context.Context, gameID string, clickType engine.ClickType, row, col int32) (
+	*deltaResolver, error) {
+	player, err := res.player(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The game clicked is a copy taken under its lock, once the click is played
+	game, delta, err := res.gameEngineSvc.Click(ctx, gameID, player, clickType, int(row), int(col))
+	if err != nil && !errors.Is(err, engine.ErrDefeat) {
+		return nil, res.gameSvc.GameError(ctx, gameID, err)
+	}
+
+	return &deltaResolver{game.DeltaFor(player, delta)}, nil
+}
+
+func (res *resolver) GameEvents(ctx context.Context, args struct {
+	ID      graphql.ID
+	LastSeq *int32
+}) (<-chan *eventResolver, error) {
+	player, err := res.player(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := string(args.ID)
+	if _, err = res.gameSvc.LoadGame(ctx, gameID); err != nil {
+		return nil, err
+	}
+	var sub *service.Subscription
+	var missed []engine.Event
+	if args.LastSeq != nil {
+		if sub, missed, err = res.gameEngineSvc.Resume(ctx, gameID, player, uint64(*args.LastSeq)); err != nil {
+			return nil, res.gameSvc.GameError(ctx, gameID, err)
+		}
+	} else {
+		sub = res.gameEngineSvc.Subscribe(gameID, player)
+	}
+	res.gameEngineSvc.Join(gameID, player)
+	events := make(chan *eventResolver)
+	// The events get sent until the subscription is stopped or ends, a subscriber falling behind or
+	// shutting down ends it
+	go func() {
+		defer close(events)
+		defer res.gameEngineSvc.Unsubscribe(sub)
+		send := func(e engine.Event) bool {
+			select {
+			case events <- &eventResolver{e}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, e := range missed {
+			if !send(e) {
+				return
+			}
+		}
+		for {
+			select {
+			case e, ok := <-sub.Events:
+				if !ok || !send(e) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// player is the name the current user plays with
+func (res *resolver) player(ctx context.Context) (string, error) {
+	currentUser, err := res.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return currentUser.Fullname, nil
+}
+
+// listGames lists the games created by the user, of the status if given
+func (res *resolver) listGames(ctx context.Context, userID string, status *string) ([]*gameResolver, error) {
+	gameStores, err := res.gameRepo.ListByCreator(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	games := make([]*gameResolver, 0, len(gameStores))
+	for _, gameStore := range gameStores {
+		if status != nil && gameStore.Status != *status {
+			continue
+		}
+		// Cached games hold the latest state and get copied under their lock, the stored one is only read
+		game, err := res.gameEngineSvc.GetGame(gameStore.ID)
+		if err != nil {
+			if game, err = gameStore.GetGameState(); err != nil {
+				return nil, res.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeCorruptedGameState, gameStore.ID,
+					err.Error())
+			}
+		}
+		games = append(games, &gameResolver{res: res, game: game, gameStore: gameStore})
+	}
+
+	return games, nil
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
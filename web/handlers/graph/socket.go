@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+	"github.com/gofiber/websocket/v2"
+	graphql "github.com/graph-gophers/graphql-go"
+	qerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+// SocketProtocol is the subprotocol of the GraphQL socket, the one of subscriptions-transport-ws
+const SocketProtocol = "graphql-ws"
+
+// MaxSocketSubscriptions is how many subscriptions a GraphQL socket runs at once
+const MaxSocketSubscriptions = 8
+
+// Message types of the graphql-ws protocol
+const (
+	msgConnectionInit      = "connection_init"
+	msgConnectionAck       = "connection_ack"
+	msgConnectionError     = "connection_error"
+	msgConnectionTerminate = "connection_terminate"
+	msgStart               = "start"
+	msgData                = "data"
+	msgError               = "error"
+	msgComplete            = "complete"
+	msgStop                = "stop"
+)
+
+// socketMessage is a message of the graphql-ws protocol
+type socketMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Socket godoc
+// @Summary Subscribes to GraphQL subscriptions over a WebSocket
+// @Description Upgrades to a WebSocket speaking the graphql-ws protocol: after connection_init, each start message runs a subscription like gameEvents and sends its events as data messages until stopped. A socket runs up to 8 subscriptions at once.
+// @Description Clients that can't set headers on the upgrade request can send the API key in the apiKey query parameter.
+// @Tags graph
+// @Produce json
+// @Success 101 {object} object "graphql-ws messages"
+// @Failure 401 {object} responses.ResponseError
+// @Failure 426 {object} responses.ResponseError
+// @Router /graphql [get]
+// @Param X-API-KEY header string false "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+// @Param apiKey query string false "API Key, for clients that can't set headers"
+func (svc *GraphHandlerSvc) Socket(conn *websocket.Conn) {
+	var mu sync.Mutex
+	send := func(msg socketMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return conn.WriteJSON(msg)
+	}
+	// The subscriptions run for the user of the upgrade request, until stopped or the socket closes
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	currentUser, _ := conn.Locals(string(utils.CurrrentUserCtxKey)).(*models.User)
+	if currentUser == nil {
+		_ = send(svc.socketError("", msgConnectionError, svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeUnauthorized)))
+		return
+	}
+	ctx = context.WithValue(ctx, utils.CurrrentUserCtxKey, currentUser)
+	var subs sync.WaitGroup
+	defer subs.Wait()
+	// The subscriptions running, each one drops itself once it ends
+	var stopsMu sync.Mutex
+	stops := map[string]context.CancelFunc{}
+	stopSubscription := func(id string) {
+		stopsMu.Lock()
+		defer stopsMu.Unlock()
+		if stop, found := stops[id]; found {
+			stop()
+			delete(stops, id)
+		}
+	}
+	defer func() {
+		stopsMu.Lock()
+		defer stopsMu.Unlock()
+		for _, stop := range stops {
+			stop()
+		}
+	}()
+	for {
+		var msg socketMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		switch msg.Type {
+		case msgConnectionInit:
+			_ = send(socketMessage{Type: msgConnectionAck})
+		case msgConnectionTerminate:
+			return
+		case msgStart:
+			var input QueryInput
+			if err := json.Unmarshal(msg.Payload, &input); err != nil {
+				_ = send(svc.socketError(msg.ID, msgError,
+					svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeReqHelperBadlyFormed)))
+				continue
+			}
+			stopSubscription(msg.ID)
+			stopsMu.Lock()
+			if len(stops) >= MaxSocketSubscriptions {
+				stopsMu.Unlock()
+				_ = send(svc.socketError(msg.ID, msgError, svc.catalog.GetErrorWithCtx(ctx,
+					codes.MsgCodeGraphTooManySubscriptions, MaxSocketSubscriptions)))
+				continue
+			}
+			subCtx, stop := context.WithCancel(ctx)
+			stops[msg.ID] = stop
+			stopsMu.Unlock()
+			subs.Add(1)
+			go func(id string) {
+				defer subs.Done()
+				svc.subscribe(subCtx, id, input, send)
+				stopsMu.Lock()
+				defer stopsMu.Unlock()
+				// A start reusing the id may have replaced the subscription already
+				if subCtx.Err() == nil {
+					stop()
+					delete(stops, id)
+				}
+			}(msg.ID)
+		case msgStop:
+			stopSubscription(msg.ID)
+		default:
+			_ = send(svc.socketError(msg.ID, msgError, svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGraphUnknownMessage,
+				msg.Type, "connection_init, start, stop, connection_terminate")))
+		}
+	}
+}
+
+// subscribe runs a subscription, or a query or mutation sent over the socket, sending its responses until
+// it ends or gets stopped
+func (svc *GraphHandlerSvc) subscribe(ctx context.Context, id string, input QueryInput,
+	send func(socketMessage) error) {
+	responses, err := svc.schema.Subscribe(ctx, input.Query, input.OperationName, input.Variables)
+	if err != nil {
+		_ = send(svc.socketError(id, msgError, err))
+		return
+	}
+	// The responses are drained even once they can't be sent, for the subscription to end with the context
+	var failed bool
+	for response := range responses {
+		resp, ok := response.(*graphql.Response)
+		if !ok || failed {
+			continue
+		}
+		svc.catalogErrors(ctx, resp.Errors)
+		payload, err := json.Marshal(resp)
+		if err != nil {
+			svc.log.SendError(err)
+			continue
+		}
+		failed = send(socketMessage{ID: id, Type: msgData, Payload: payload}) != nil
+	}
+	if !failed && ctx.Err() == nil {
+		_ = send(socketMessage{ID: id, Type: msgComplete})
+	}
+}
+
+// socketError builds an error message of the socket, its payload is a GraphQL error carrying the code and
+// details of the catalog error
+func (svc *GraphHandlerSvc) socketError(id, msgType string, err error) socketMessage {
+	qerr := &qerrors.QueryError{ResolverError: err}
+	svc.catalogErrors(context.Background(), []*qerrors.QueryError{qerr})
+	payload, _ := json.Marshal(qerr)
+
+	return socketMessage{ID: id, Type: msgType, Payload: payload}
+}
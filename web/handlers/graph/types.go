@@ -0,0 +1,457 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// userResolver resolves a user, the fields telling its account are only resolved for the user itself and
+// the admins
+type userResolver struct {
+	res  *resolver
+	user *models.User
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.user.ID)
+}
+
+func (u *userResolver) Username() string {
+	return u.user.Username
+}
+
+func (u *userResolver) Fullname() string {
+	return u.user.Fullname
+}
+
+func (u *userResolver) Email(ctx context.Context) (*string, error) {
+	if err := u.res.allowed(ctx, u.user.ID, "email"); err != nil {
+		return nil, err
+	}
+
+	return &u.user.Email, nil
+}
+
+func (u *userResolver) APIKeys(ctx context.Context) (*[]string, error) {
+	if err := u.res.allowed(ctx, u.user.ID, "apiKeys"); err != nil {
+		return nil, err
+	}
+	apiKeys := make([]string, len(u.user.APIKeys))
+	for i, apiKey := range u.user.APIKeys {
+		apiKeys[i] = apiKey.APIKey
+	}
+
+	return &apiKeys, nil
+}
+
+func (u *userResolver) Games(ctx context.Context, args struct{ Status *string }) (*[]*gameResolver, error) {
+	if err := u.res.allowed(ctx, u.user.ID, "games"); err != nil {
+		return nil, err
+	}
+	games, err := u.res.listGames(ctx, u.user.ID, args.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &games, nil
+}
+
+func (u *userResolver) Stats(ctx context.Context) (*statsResolver, error) {
+	gameStores, err := u.res.gameRepo.ListByCreator(ctx, u.user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStats(gameStores), nil
+}
+
+// statsResolver resolves the stats of the games of a user
+type statsResolver struct {
+	played, active, victories, defeats, forfeited, abandoned int32
+	fastestVictory                                           *float64
+}
+
+// newStats counts the games by status, taken from the stored games to not decode their states
+func newStats(gameStores []*models.Game) *statsResolver {
+	stats := &statsResolver{played: int32(len(gameStores))}
+	for _, gameStore := range gameStores {
+		switch engine.GameStatus(gameStore.Status) {
+		case engine.GameStatusCreated, engine.GameStatusStarted:
+			stats.active++
+		case engine.GameStatusVictory:
+			stats.victories++
+			if gameStore.Unranked || gameStore.StartedAt == nil || gameStore.FinishedAt == nil {
+				continue
+			}
+			took := gameStore.FinishedAt.Sub(*gameStore.StartedAt).Seconds()
+			if stats.fastestVictory == nil || took < *stats.fastestVictory {
+				stats.fastestVictory = &took
+			}
+		case engine.GameStatusDefeat:
+			stats.defeats++
+		case engine.GameStatusForfeited:
+			stats.forfeited++
+		case engine.GameStatusAbandoned:
+			stats.abandoned++
+		}
+	}
+
+	return stats
+}
+
+func (s *statsResolver) Played() int32 {
+	return s.played
+}
+
+func (s *statsResolver) Active() int32 {
+	return s.active
+}
+
+func (s *statsResolver) Victories() int32 {
+	return s.victories
+}
+
+func (s *statsResolver) Defeats() int32 {
+	return s.defeats
+}
+
+func (s *statsResolver) Forfeited() int32 {
+	return s.forfeited
+}
+
+func (s *statsResolver) Abandoned() int32 {
+	return s.abandoned
+}
+
+func (s *statsResolver) WinRate() float64 {
+	finished := s.played - s.active
+	if finished == 0 {
+		return 0
+	}
+
+	return float64(s.victories) / float64(finished)
+}
+
+func (s *statsResolver) FastestVictory() *float64 {
+	return s.fastestVictory
+}
+
+// gameResolver resolves a game as the current user is allowed to see it. The game is a copy taken under
+// its lock, the fields get resolved while its moves keep being played
+type gameResolver struct {
+	res       *resolver
+	game      *engine.Game
+	gameStore *models.Game
+}
+
+func (g *gameResolver) ID() graphql.ID {
+	return graphql.ID(g.game.ID)
+}
+
+func (g *gameResolver) Version() int32 {
+	return int32(g.game.Version)
+}
+
+func (g *gameResolver) Type() string {
+	if g.game.Rules.Type == "" {
+		return string(engine.GameTypeClassic)
+	}
+
+	return string(g.game.Rules.Type)
+}
+
+func (g *gameResolver) Rows() int32 {
+	return int32(g.game.Rows)
+}
+
+func (g *gameResolver) Cols() int32 {
+	return int32(g.game.Cols)
+}
+
+func (g *gameResolver) Mines() int32 {
+	return int32(g.game.Mines)
+}
+
+func (g *gameResolver) Status() string {
+	return string(g.game.Status)
+}
+
+func (g *gameResolver) CreatedBy() *userResolver {
+	if g.gameStore == nil || g.gameStore.CreatedBy == nil {
+		return nil
+	}
+
+	return &userResolver{res: g.res, user: g.gameStore.CreatedBy}
+}
+
+func (g *gameResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: g.game.CreatedAt}
+}
+
+func (g *gameResolver) StartedAt() *graphql.Time {
+	return toTime(g.game.StartedAt)
+}
+
+func (g *gameResolver) FinishedAt() *graphql.Time {
+	return toTime(g.game.FinishedAt)
+}
+
+func (g *gameResolver) Difficulty() *difficultyResolver {
+	if g.game.Difficulty == nil {
+		return nil
+	}
+
+	return &difficultyResolver{g.game.Difficulty}
+}
+
+func (g *gameResolver) BoardCode() *string {
+	if !g.game.IsFinished() || g.game.Code == "" {
+		return nil
+	}
+
+	return &g.game.Code
+}
+
+func (g *gameResolver) Cells(ctx context.Context) ([]*cellResolver, error) {
+	player, err := g.res.player(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCells(g.game.Cells(player)), nil
+}
+
+func (g *gameResolver) Moves(ctx context.Context) (*[]*moveResolver, error) {
+	if !g.game.IsFinished() {
+		var createdByID string
+		if g.gameStore != nil {
+			createdByID = g.gameStore.CreatedByID
+		}
+		if err := g.res.allowed(ctx, createdByID, "moves"); err != nil {
+			return nil, err
+		}
+	}
+	moves := make([]*moveResolver, len(g.game.History))
+	for i, move := range g.game.History {
+		moves[i] = &moveResolver{move}
+	}
+
+	return &moves, nil
+}
+
+type difficultyResolver struct {
+	difficulty *engine.Difficulty
+}
+
+func (d *difficultyResolver) BBBV() int32 {
+	return int32(d.difficulty.BBBV)
+}
+
+func (d *difficultyResolver) Unsolved() int32 {
+	return int32(d.difficulty.Unsolved)
+}
+
+func (d *difficultyResolver) Guesses() int32 {
+	return int32(d.difficulty.Guesses)
+}
+
+func (d *difficultyResolver) Openings() int32 {
+	return int32(d.difficulty.Openings)
+}
+
+func (d *difficultyResolver) Density() float64 {
+	return d.difficulty.Density
+}
+
+func (d *difficultyResolver) Rating() float64 {
+	return d.difficulty.Rating
+}
+
+func (d *difficultyResolver) Tier() string {
+	return d.difficulty.Tier
+}
+
+type cellResolver struct {
+	cell engine.CellDelta
+}
+
+func toCells(cells []engine.CellDelta) []*cellResolver {
+	resolvers := make([]*cellResolver, len(cells))
+	for i, cell := range cells {
+		resolvers[i] = &cellResolver{cell}
+	}
+
+	return resolvers
+}
+
+func (c *cellResolver) Row() int32 {
+	return int32(c.cell.Row)
+}
+
+func (c *cellResolver) Col() int32 {
+	return int32(c.cell.Col)
+}
+
+func (c *cellResolver) Revealed() bool {
+	return c.cell.Revealed
+}
+
+func (c *cellResolver) Flagged() bool {
+	return c.cell.Flagged
+}
+
+func (c *cellResolver) Mine() bool {
+	return c.cell.Mine
+}
+
+func (c *cellResolver) AdjMines() int32 {
+	return int32(c.cell.AdjMines)
+}
+
+func (c *cellResolver) Fogged() bool {
+	return c.cell.Fogged
+}
+
+func (c *cellResolver) ClickedBy() *string {
+	return optional(c.cell.ClickedBy)
+}
+
+type moveResolver struct {
+	move engine.Move
+}
+
+func (m *moveResolver) Seq() int32 {
+	return int32(m.move.Seq)
+}
+
+func (m *moveResolver) Player() string {
+	return m.move.Player
+}
+
+func (m *moveResolver) Action() string {
+	return m.move.Action
+}
+
+func (m *moveResolver) Row() int32 {
+	return int32(m.move.Row)
+}
+
+func (m *moveResolver) Col() int32 {
+	return int32(m.move.Col)
+}
+
+func (m *moveResolver) Result() *string {
+	return optional(m.move.Result)
+}
+
+func (m *moveResolver) Item() *string {
+	return optional(string(m.move.Item))
+}
+
+func (m *moveResolver) At() graphql.Time {
+	return graphql.Time{Time: m.move.At}
+}
+
+type deltaResolver struct {
+	delta *engine.Delta
+}
+
+func (d *deltaResolver) GameID() graphql.ID {
+	return graphql.ID(d.delta.GameID)
+}
+
+func (d *deltaResolver) Version() int32 {
+	return int32(d.delta.Version)
+}
+
+func (d *deltaResolver) Move() *moveResolver {
+	if d.delta.Move == nil {
+		return nil
+	}
+
+	return &moveResolver{*d.delta.Move}
+}
+
+func (d *deltaResolver) Cells() []*cellResolver {
+	return toCells(d.delta.Cells)
+}
+
+func (d *deltaResolver) Status() string {
+	return string(d.delta.Status)
+}
+
+func (d *deltaResolver) PreviousStatus() *string {
+	return optional(string(d.delta.PreviousStatus))
+}
+
+type eventResolver struct {
+	event engine.Event
+}
+
+func (e *eventResolver) Seq() int32 {
+	return int32(e.event.Seq)
+}
+
+func (e *eventResolver) Type() string {
+	return string(e.event.Type)
+}
+
+func (e *eventResolver) GameID() graphql.ID {
+	return graphql.ID(e.event.GameID)
+}
+
+func (e *eventResolver) Version() int32 {
+	return int32(e.event.Version)
+}
+
+func (e *eventResolver) Player() *string {
+	return optional(e.event.Player)
+}
+
+func (e *eventResolver) Cells() []*cellResolver {
+	return toCells(e.event.Cells)
+}
+
+func (e *eventResolver) Status() *string {
+	return optional(string(e.event.Status))
+}
+
+func (e *eventResolver) At() graphql.Time {
+	return graphql.Time{Time: e.event.At}
+}
+
+func toTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &graphql.Time{Time: *t}
+}
+
+// optional resolves empty strings to null
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// allowed tells whether the current user can see a field of what the user owns: the user itself and the
+// admins can
+func (res *resolver) allowed(ctx context.Context, ownerID, field string) error {
+	currentUser, err := res.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if currentUser.Admin || (ownerID != "" && currentUser.ID == ownerID) {
+		return nil
+	}
+
+	return res.catalog.GetErrorWithCtx(ctx, codes.MsgCodeGraphFieldDenied, field)
+}
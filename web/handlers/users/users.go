@@ -1,15 +1,19 @@
 package users
 
 import (
+	"errors"
 	"net/http"
 	"path"
 
 	"github.com/cmelgarejo/minesweeper-svc/database/models"
 	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
 	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
 	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
 	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
 	"github.com/loopcontext/msgcat"
+	"gorm.io/gorm"
 )
 
 type UserHandler interface {
@@ -17,6 +21,7 @@ type UserHandler interface {
 	Create(w http.ResponseWriter, r *http.Request)
 	Read(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
+	Me(w http.ResponseWriter, r *http.Request)
 	// For Admins
 	// List(w http.ResponseWriter, r *http.Request)
 	// Delete(w http.ResponseWriter, r *http.Request)
@@ -26,16 +31,18 @@ type UserHandlerSvc struct {
 	log            logger.Logger
 	catalog        msgcat.MessageCatalog
 	authRepo       repo.AuthRepo
+	authSvc        services.AuthSvc
 	requestHelper  common.RequestHelper
 	responseHelper common.ResponseHelper
 }
 
 func NewUserHandlerSvc(log logger.Logger, catalog msgcat.MessageCatalog, authRepo repo.AuthRepo,
-	requestHelper common.RequestHelper, responseHelper common.ResponseHelper) UserHandler {
+	authSvc services.AuthSvc, requestHelper common.RequestHelper, responseHelper common.ResponseHelper) UserHandler {
 	return &UserHandlerSvc{
 		log:            log,
 		catalog:        catalog,
 		authRepo:       authRepo,
+		authSvc:        authSvc,
 		responseHelper: responseHelper,
 		requestHelper:  requestHelper,
 	}
@@ -130,6 +137,41 @@ func (svc *UserHandlerSvc) Read(w http.ResponseWriter, r *http.Request) {
 	svc.responseHelper.Send(w, r, http.StatusOK, user)
 }
 
+// Me godoc
+// @Summary Gets the current user of minesweeper
+// @Description Gets the user the API key belongs to, with its API keys
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} responses.Response
+// @Failure 404 {object} responses.ResponseError
+// @Failure 500 {object} responses.ResponseError
+// @Router /v2/api/users/me [get]
+// @Param X-API-KEY header string true "API Key" default(587fa65a9c375165828a6fbb5f9963a7)
+func (svc *UserHandlerSvc) Me(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	currentUser, err := svc.authSvc.GetCurrentUser(ctx)
+	if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+
+		return
+	}
+	user, err := svc.authRepo.Read(ctx, currentUser.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		svc.responseHelper.Error(w, r, http.StatusNotFound,
+			svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeDBRecordsNotFound, repo.TblUsers))
+
+		return
+	} else if err != nil {
+		svc.responseHelper.Error(w, r, http.StatusInternalServerError, err)
+
+		return
+	}
+	// The password hash stays on the server
+	user.Password = ""
+	svc.responseHelper.Send(w, r, http.StatusOK, user)
+}
+
 // Update godoc
 // @Summary Updates an user of minesweeper
 // @Description Updates an user of minesweeper and returns an userID
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/services/common"
+	"github.com/gofiber/adaptor/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/loopcontext/msgcat"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed" // set on the responses replayed out of a stored key
+	IdempotencyKeyMaxLength  = 255
+)
+
+// IdempotencyPurgeInterval is how often the expired keys get dropped
+const IdempotencyPurgeInterval = time.Hour
+
+type IdempotencyMiddleware interface {
+	CheckIdempotencyKey(c *fiber.Ctx) error
+	StartWorkers(ctx context.Context)
+}
+
+type IdempotencyMiddlewareSvc struct {
+	log             logger.Logger
+	catalog         msgcat.MessageCatalog
+	idempotencyRepo repo.IdempotencyRepo
+	responseHelper  common.ResponseHelper
+	retention       time.Duration // how long a completed key is kept
+	lease           time.Duration // how long a key in flight is kept
+}
+
+func NewIdempotencyMiddlewareSvc(log logger.Logger, catalog msgcat.MessageCatalog,
+	idempotencyRepo repo.IdempotencyRepo, responseHelper common.ResponseHelper, retention, lease time.Duration) IdempotencyMiddleware {
+	return &IdempotencyMiddlewareSvc{
+		log:             log,
+		catalog:         catalog,
+		idempotencyRepo: idempotencyRepo,
+		responseHelper:  responseHelper,
+		retention:       retention,
+		lease:           lease,
+	}
+}
+
+// CheckIdempotencyKey makes the mutating requests sent with an Idempotency-Key header safe to retry: the
+// response of the first one is stored with the key and replayed to the retries. Reusing the key with a
+// different request is rejected, as is retrying while the first one is still being handled, for as long
+// as its lease lasts: the key of a request that never ended is taken over by the next retry. Failed
+// requests (5xx) don't keep their key so they can be retried. Keys belong to the current user, so this
+// goes after the auth middleware.
+func (svc *IdempotencyMiddlewareSvc) CheckIdempotencyKey(c *fiber.Ctx) error {
+	keyValue := c.Get(HeaderIdempotencyKey)
+	if keyValue == "" || !mutating(c.Method()) {
+		return c.Next()
+	}
+	ctx := c.Context()
+	if len(keyValue) > IdempotencyKeyMaxLength {
+		return svc.error(c, http.StatusBadRequest,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeInvalidIdempotencyKey, IdempotencyKeyMaxLength))
+	}
+	currentUser, _ := c.Locals(string(utils.CurrrentUserCtxKey)).(*models.User)
+	if currentUser == nil {
+		return svc.error(c, http.StatusInternalServerError,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeHelperCurrentUserNotFound))
+	}
+	key := &models.IdempotencyKey{
+		UserID:      currentUser.ID,
+		Key:         keyValue,
+		RequestHash: requestHash(c),
+		ExpiresAt:   time.Now().Add(svc.lease),
+	}
+	existing, err := svc.idempotencyRepo.Reserve(ctx, key)
+	if err != nil {
+		return svc.error(c, http.StatusInternalServerError,
+			svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeDBUnexpectedErr, err.Error()))
+	}
+	if existing != nil {
+		return svc.replay(c, existing, key.RequestHash)
+	}
+	if err = c.Next(); err != nil {
+		svc.release(key)
+		return err
+	}
+	status := c.Response().StatusCode()
+	if status >= http.StatusInternalServerError {
+		svc.release(key)
+		return nil
+	}
+	key.StatusCode = status
+	key.ContentType = string(c.Response().Header.ContentType())
+	key.Body = append([]byte{}, c.Response().Body()...)
+	key.ExpiresAt = time.Now().Add(svc.retention)
+	if err = svc.idempotencyRepo.Complete(ctx, key); err != nil {
+		svc.log.Error().Err(err).Str("key", keyValue).Msg("Idempotency key response could not be stored")
+		svc.release(key)
+	}
+
+	return nil
+}
+
+// replay answers a request with the response stored under its key
+func (svc *IdempotencyMiddlewareSvc) replay(c *fiber.Ctx, key *models.IdempotencyKey, hash string) error {
+	ctx := c.Context()
+	if key.RequestHash != hash {
+		return svc.error(c, http.StatusUnprocessableEntity,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeIdempotencyKeyReused, key.Key))
+	}
+	if !key.Completed() {
+		return svc.error(c, http.StatusConflict,
+			svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeIdempotencyKeyInFlight, key.Key))
+	}
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Set(fiber.HeaderContentType, key.ContentType)
+
+	return c.Status(key.StatusCode).Send(key.Body)
+}
+
+func (svc *IdempotencyMiddlewareSvc) release(key *models.IdempotencyKey) {
+	if err := svc.idempotencyRepo.Release(context.Background(), key); err != nil {
+		svc.log.Error().Err(err).Str("key", key.Key).Msg("Idempotency key could not be released")
+	}
+}
+
+// StartWorkers drops the expired keys every IdempotencyPurgeInterval, until the context is done
+func (svc *IdempotencyMiddlewareSvc) StartWorkers(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(IdempotencyPurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				svc.purge(ctx)
+			}
+		}
+	}()
+}
+
+func (svc *IdempotencyMiddlewareSvc) purge(ctx context.Context) {
+	purged, err := svc.idempotencyRepo.Purge(ctx, time.Now())
+	if err != nil {
+		svc.log.Error().Err(err).Msg("Expired idempotency keys could not be purged")
+		return
+	}
+	svc.log.Debug().Int64("purged", purged).Msg("Expired idempotency keys purged")
+}
+
+// error answers with an error response like the net/http handlers do
+func (svc *IdempotencyMiddlewareSvc) error(c *fiber.Ctx, statusCode int, err error) error {
+	return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		svc.responseHelper.Error(w, r, statusCode, err)
+	})(c)
+}
+
+func mutating(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// requestHash tells requests apart by their method, url and body
+func requestHash(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
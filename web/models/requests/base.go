@@ -1,6 +1,11 @@
 package requests
 
-import "github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+)
 
 type Credentials struct {
 	Username string `json:"username" example:"player1"`
@@ -16,19 +21,131 @@ type UserInput struct {
 type GameInput struct {
 	Row       int    `json:"row" example:"0"`
 	Col       int    `json:"col" example:"0"`
-	ClickType string `json:"clickType" enums:"click,flag"`
+	ClickType string `json:"clickType" enums:"click,flag,unflag,chord,radar,detector"`
 }
 
 type GameCreateInput struct {
-	Rows  int `json:"row" example:"5"`
-	Cols  int `json:"col" example:"5"`
-	Mines int `json:"mines" example:"5"`
+	Rows   int    `json:"row" example:"5"`
+	Cols   int    `json:"col" example:"5"`
+	Mines  int    `json:"mines" example:"5"`
+	Type   string `json:"type" enums:"classic,endless" example:"classic"`
+	Kernel string `json:"kernel" enums:"moore,knight,manhattan2" example:"moore"`
+	Arcade bool   `json:"arcade" example:"false"`
+	Fog    *Fog   `json:"fog,omitempty"`
+	Tier   string `json:"tier" enums:"beginner,intermediate,expert,master" example:""`
+	// Durability of the game in write-behind mode, by default ranked games store every move
+	Durability string `json:"durability,omitempty" enums:"move,batch" example:""`
+}
+
+// GameSaveInput names a save slot of a game
+type GameSaveInput struct {
+	Name string `json:"name" example:"before the last corner"`
+}
+
+// SaveNameMaxLength is the longest name a save slot can have
+const SaveNameMaxLength = 64
+
+// Valid tells whether the save slot name can be used, names are part of the restore route
+func (gsi *GameSaveInput) Valid() bool {
+	return gsi.Name != "" && len(gsi.Name) <= SaveNameMaxLength && !strings.ContainsAny(gsi.Name, "/?#")
+}
+
+// Fog enables the limited visibility mode
+type Fog struct {
+	Radius int `json:"radius" example:"2"`
+	Memory int `json:"memory" example:"3"`
+}
+
+// GetRules builds the game rules out of the input, validating them
+func (gci *GameCreateInput) GetRules() (rules engine.Rules, err error) {
+	switch engine.GameType(gci.Type) {
+	case "", engine.GameTypeClassic:
+	case engine.GameTypeEndless:
+		rules.Type = engine.GameTypeEndless
+	default:
+		return rules, fmt.Errorf("Unknown game type: %s", gci.Type)
+	}
+	rules.Kernel, err = engine.ParseKernel(gci.Kernel)
+	if err != nil {
+		return rules, err
+	}
+	rules.Tier, err = engine.ParseTier(gci.Tier)
+	rules.Arcade = gci.Arcade
+	if gci.Fog != nil {
+		rules.Fog = engine.NewFogRules(gci.Fog.Radius, gci.Fog.Memory)
+	}
+
+	return
+}
+
+// GameMovesInput is a batch of moves played in order
+type GameMovesInput struct {
+	Moves []GameInput `json:"moves"`
 }
 
+// MovesMaxCount is the most moves a batch can have
+const MovesMaxCount = 500
+
+// Valid tells whether the batch can be played
+func (gmi *GameMovesInput) Valid() bool {
+	return len(gmi.Moves) > 0 && len(gmi.Moves) <= MovesMaxCount
+}
+
+// GetMoves returns the moves of the batch for the game engine
+func (gmi *GameMovesInput) GetMoves() []engine.PlannedMove {
+	moves := make([]engine.PlannedMove, len(gmi.Moves))
+	for i, move := range gmi.Moves {
+		moves[i] = engine.PlannedMove{ClickType: move.GetClickType(), Row: move.Row, Col: move.Col}
+	}
+
+	return moves
+}
+
+// RevealInput reveals a field, a chord reveals the fields around a revealed one once its mines are flagged
+type RevealInput struct {
+	Row   int  `json:"row" example:"0"`
+	Col   int  `json:"col" example:"0"`
+	Chord bool `json:"chord" example:"false"`
+}
+
+// GetClickType returns the click of the reveal for the game engine
+func (ri *RevealInput) GetClickType() engine.ClickType {
+	if ri.Chord {
+		return engine.GameClickTypeChord
+	}
+
+	return engine.GameClickTypeNormal
+}
+
+// FlagInput flags a field, or takes its flag off
+type FlagInput struct {
+	Row int `json:"row" example:"0"`
+	Col int `json:"col" example:"0"`
+}
+
+// GameCommand is a command sent over the game socket
+type GameCommand struct {
+	Type string `json:"type" enums:"click" example:"click"`
+	GameInput
+}
+
+// Commands of the game socket
+const (
+	GameCommandClick = "click"
+)
+
 func (gi *GameInput) GetClickType() engine.ClickType {
 	switch gi.ClickType {
 	case "flag":
 		return engine.GameClickTypeFlag
+	case "unflag":
+		return engine.GameClickTypeUnflag
+	case "chord":
+		return engine.GameClickTypeChord
+	case "radar":
+		return engine.GameClickTypeRadar
+	case "detector":
+		return engine.GameClickTypeDetector
 	case "normal":
 		fallthrough
 	default:
@@ -18,6 +18,12 @@ type ResponseError struct {
 	ResponseBase
 }
 
+// SocketError is an error sent over the game socket, next to the game events
+type SocketError struct {
+	Type string `json:"type" example:"error"`
+	ResponseError
+}
+
 //Field represents a square unit in the MineField
 type Field struct {
 	Mine      bool           `json:"mine,omitempty"`
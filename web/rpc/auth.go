@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	"github.com/loopcontext/msgcat"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuthServiceSvc struct {
+	pb.UnimplementedAuthServiceServer
+	log      logger.Logger
+	catalog  msgcat.MessageCatalog
+	authRepo repo.AuthRepo
+}
+
+func NewAuthServiceSvc(log logger.Logger, catalog msgcat.MessageCatalog, authRepo repo.AuthRepo) pb.AuthServiceServer {
+	return &AuthServiceSvc{
+		log:      log,
+		catalog:  catalog,
+		authRepo: authRepo,
+	}
+}
+
+// SignIn returns the API key of the user of the credentials
+func (svc *AuthServiceSvc) SignIn(ctx context.Context, req *pb.SignInRequest) (*pb.SignInResponse, error) {
+	apiKey, err := svc.authRepo.SignIn(ctx, req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, status.Error(grpccodes.Unauthenticated, errorMessage(err))
+	}
+
+	return &pb.SignInResponse{ApiKey: apiKey.APIKey}, nil
+}
+
+// CreateUser creates a user with an API key
+func (svc *AuthServiceSvc) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	user, err := svc.authRepo.UpsertUser(ctx, nil, &models.User{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		Email:    req.GetEmail(),
+		Fullname: req.GetFullname(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toUser(user), nil
+}
+
+// GetCurrentUser returns the user of the API key of the call
+func (svc *AuthServiceSvc) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserRequest) (*pb.User, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err = svc.authRepo.Read(ctx, user.ID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toUser(user), nil
+}
@@ -0,0 +1,214 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// clickTypes maps the click types of the API to the ones of the game engine
+var clickTypes = map[pb.ClickType]engine.ClickType{
+	pb.ClickType_CLICK_TYPE_UNSPECIFIED: engine.GameClickTypeNormal,
+	pb.ClickType_CLICK_TYPE_FLAG:        engine.GameClickTypeFlag,
+	pb.ClickType_CLICK_TYPE_CHORD:       engine.GameClickTypeChord,
+	pb.ClickType_CLICK_TYPE_RADAR:       engine.GameClickTypeRadar,
+	pb.ClickType_CLICK_TYPE_DETECTOR:    engine.GameClickTypeDetector,
+}
+
+func toClickType(clickType pb.ClickType) engine.ClickType {
+	if ct, found := clickTypes[clickType]; found {
+		return ct
+	}
+
+	return engine.GameClickTypeNormal
+}
+
+// toGame converts a game as the player is allowed to see it
+func toGame(game *engine.Game, player string) *pb.Game {
+	g := &pb.Game{
+		Id:         game.ID,
+		Version:    game.Version,
+		Type:       string(game.Rules.Type),
+		Rows:       int32(game.Rows),
+		Cols:       int32(game.Cols),
+		Mines:      int32(game.Mines),
+		Status:     string(game.Status),
+		CreatedBy:  game.CreatedBy,
+		StartedAt:  toTimestamp(game.StartedAt),
+		FinishedAt: toTimestamp(game.FinishedAt),
+		Cells:      toCells(game.Cells(player)),
+	}
+	if game.IsFinished() {
+		g.BoardCode = game.Code
+	}
+
+	return g
+}
+
+func toCells(cells []engine.CellDelta) []*pb.Cell {
+	converted := make([]*pb.Cell, len(cells))
+	for i, cell := range cells {
+		converted[i] = &pb.Cell{
+			Row:       int32(cell.Row),
+			Col:       int32(cell.Col),
+			Revealed:  cell.Revealed,
+			Flagged:   cell.Flagged,
+			Mine:      cell.Mine,
+			AdjMines:  int32(cell.AdjMines),
+			Fogged:    cell.Fogged,
+			ClickedBy: cell.ClickedBy,
+		}
+	}
+
+	return converted
+}
+
+func toDelta(delta *engine.Delta) *pb.Delta {
+	if delta == nil {
+		return nil
+	}
+	d := &pb.Delta{
+		GameId:         delta.GameID,
+		Version:        delta.Version,
+		Cells:          toCells(delta.Cells),
+		Status:         string(delta.Status),
+		PreviousStatus: string(delta.PreviousStatus),
+	}
+	if move := delta.Move; move != nil {
+		d.Move = toMove(move)
+	}
+
+	return d
+}
+
+func toMove(move *engine.Move) *pb.Move {
+	return &pb.Move{
+		Seq:    uint64(move.Seq),
+		Player: move.Player,
+		Action: move.Action,
+		Row:    int32(move.Row),
+		Col:    int32(move.Col),
+		Result: move.Result,
+		At:     timestamppb.New(move.At),
+		Item:   string(move.Item),
+	}
+}
+
+func toViewport(viewport *engine.Viewport) *pb.Viewport {
+	v := &pb.Viewport{
+		Row:   int32(viewport.Row),
+		Col:   int32(viewport.Col),
+		Rows:  int32(viewport.Rows),
+		Cols:  int32(viewport.Cols),
+		Cells: make([]*pb.ViewRow, len(viewport.Cells)),
+	}
+	for i, row := range viewport.Cells {
+		v.Cells[i] = &pb.ViewRow{Cells: make([]*pb.CellView, len(row))}
+		for j, cell := range row {
+			v.Cells[i].Cells[j] = &pb.CellView{State: cell.State, AdjMines: int32(cell.AdjCount)}
+		}
+	}
+
+	return v
+}
+
+func toDifficulty(difficulty *engine.Difficulty) *pb.Difficulty {
+	return &pb.Difficulty{
+		Bbbv:     int32(difficulty.BBBV),
+		Unsolved: int32(difficulty.Unsolved),
+		Guesses:  int32(difficulty.Guesses),
+		Openings: int32(difficulty.Openings),
+		Density:  difficulty.Density,
+		Rating:   difficulty.Rating,
+		Tier:     difficulty.Tier,
+	}
+}
+
+// toSave converts a save slot, leaving its game state out
+func toSave(save *models.GameSave) *pb.Save {
+	s := &pb.Save{
+		GameId:    save.GameID,
+		Name:      save.Name,
+		CreatedAt: timestamppb.New(save.CreatedAt),
+		UpdatedAt: timestamppb.New(save.UpdatedAt),
+	}
+	if save.CreatedBy != nil {
+		s.CreatedBy = save.CreatedBy.Fullname
+	}
+
+	return s
+}
+
+func toAttempt(attempt *engine.Attempt) *pb.Attempt {
+	return &pb.Attempt{
+		Id:                attempt.ID,
+		PreviousAttemptId: attempt.PreviousAttemptID,
+		CreatedBy:         attempt.CreatedBy,
+		Status:            string(attempt.Status),
+		Moves:             int32(attempt.Moves),
+		Revealed:          int32(attempt.Revealed),
+		Duration:          attempt.Duration,
+		StartedAt:         toTimestamp(attempt.StartedAt),
+		FinishedAt:        toTimestamp(attempt.FinishedAt),
+		CreatedAt:         timestamppb.New(attempt.CreatedAt),
+	}
+}
+
+func toMovesResult(result *engine.MovesResult) *pb.MovesResult {
+	r := &pb.MovesResult{
+		GameId:  result.GameID,
+		Version: result.Version,
+		Status:  string(result.Status),
+		Moves:   make([]*pb.MoveOutcome, len(result.Moves)),
+	}
+	for i, outcome := range result.Moves {
+		r.Moves[i] = &pb.MoveOutcome{
+			Index:   int32(outcome.Index),
+			Outcome: outcome.Outcome,
+			Delta:   toDelta(outcome.Delta),
+			Error:   outcome.Error,
+		}
+	}
+
+	return r
+}
+
+func toEvent(e engine.Event) *pb.Event {
+	return &pb.Event{
+		Seq:     e.Seq,
+		Type:    string(e.Type),
+		GameId:  e.GameID,
+		Version: e.Version,
+		Player:  e.Player,
+		Cells:   toCells(e.Cells),
+		Status:  string(e.Status),
+		At:      timestamppb.New(e.At),
+	}
+}
+
+// toUser converts a user, leaving its password out
+func toUser(user *models.User) *pb.User {
+	u := &pb.User{
+		Id:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Fullname: user.Fullname,
+		ApiKeys:  make([]string, len(user.APIKeys)),
+	}
+	for i, apiKey := range user.APIKeys {
+		u.ApiKeys[i] = apiKey.APIKey
+	}
+
+	return u
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,195 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+// ForkGame forks a game into a new unranked game of the current user, other players only get to fork the
+// games already finished
+func (svc *GameServiceSvc) ForkGame(ctx context.Context, req *pb.ForkGameRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	// A fork holds the whole board
+	if !services.CanManage(user, gameStore) && !game.IsFinished() {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "fork")
+	}
+	fork, err := svc.gameSvc.ForkGame(ctx, game, user, false)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(fork, user.Fullname), nil
+}
+
+// SaveGame snapshots a game of the current user in a named save slot, overwriting the slot of the same name
+func (svc *GameServiceSvc) SaveGame(ctx context.Context, req *pb.SaveGameRequest) (*pb.Save, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	input := requests.GameSaveInput{Name: req.GetName()}
+	if !input.Valid() {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.InvalidArgument, codes.MsgCodeReqHelperInvalidValue,
+			"name", 0)
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if !services.CanManage(user, gameStore) {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "save")
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	save := &models.GameSave{
+		GameID:      gameID,
+		Name:        input.Name,
+		CreatedByID: user.ID,
+	}
+	save.UpdateGameState(game)
+	if save, err = svc.gameRepo.UpsertSave(ctx, save); err != nil {
+		return nil, statusError(err)
+	}
+	save.CreatedBy = user
+
+	return toSave(save), nil
+}
+
+// ListSaves lists the save slots of a game, oldest first
+func (svc *GameServiceSvc) ListSaves(ctx context.Context, req *pb.ListSavesRequest) (*pb.ListSavesResponse, error) {
+	gameID := req.GetGameId()
+	// Saves of deleted games are not listed
+	if _, err := svc.gameRepo.Read(ctx, gameID); err != nil {
+		return nil, statusError(err)
+	}
+	saves, err := svc.gameRepo.ListSaves(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	list := &pb.ListSavesResponse{Saves: make([]*pb.Save, len(saves))}
+	for i, save := range saves {
+		list.Saves[i] = toSave(save)
+	}
+
+	return list, nil
+}
+
+// RestoreSave creates a practice game of the current user out of a save slot of one of its games
+func (svc *GameServiceSvc) RestoreSave(ctx context.Context, req *pb.RestoreSaveRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	// Saves hold the whole board of games still played
+	if !services.CanManage(user, gameStore) {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "restore the saves of")
+	}
+	save, err := svc.gameRepo.ReadSave(ctx, gameID, req.GetName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	saved, err := save.GetGameState()
+	if err != nil {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.Internal, codes.MsgCodeCorruptedGameState, gameID,
+			err.Error())
+	}
+	game, err := svc.gameSvc.ForkGame(ctx, saved, user, true)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// RetryGame creates a new attempt at the board of a game, other players only get to retry the games already
+// finished
+func (svc *GameServiceSvc) RetryGame(ctx context.Context, req *pb.RetryGameRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	// A retry holds the whole board
+	if !services.CanManage(user, gameStore) && !game.IsFinished() {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "retry")
+	}
+	retry, err := svc.gameSvc.RetryGame(ctx, game, user)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(retry, user.Fullname), nil
+}
+
+// ListAttempts lists the attempts of the current user at the board of a game, from the first one on
+func (svc *GameServiceSvc) ListAttempts(ctx context.Context, req *pb.ListAttemptsRequest) (
+	*pb.ListAttemptsResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, req.GetGameId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	firstAttemptID := game.FirstAttemptID
+	if firstAttemptID == "" {
+		firstAttemptID = game.ID
+	}
+	gameStores, err := svc.gameRepo.ListAttempts(ctx, firstAttemptID, user.ID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	list := &pb.ListAttemptsResponse{Attempts: make([]*pb.Attempt, 0, len(gameStores))}
+	for _, gameStore := range gameStores {
+		// Attempts being played are summarized out of the engine, their stored state may be behind
+		attempt, err := svc.gameEngineSvc.GetGame(gameStore.ID)
+		if err != nil {
+			if attempt, err = gameStore.GetGameState(); err != nil {
+				svc.log.Warn().Err(err).Str("gameID", gameStore.ID).Msg("Attempt left out of the list")
+				continue
+			}
+		}
+		list.Attempts = append(list.Attempts, toAttempt(attempt.Attempt()))
+	}
+
+	return list, nil
+}
@@ -0,0 +1,367 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+	"github.com/loopcontext/msgcat"
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+type GameServiceSvc struct {
+	pb.UnimplementedGameServiceServer
+	log           logger.Logger
+	catalog       msgcat.MessageCatalog
+	gameRepo      repo.GameRepo
+	gameEngineSvc service.MineSweeperGameSvc
+	gameSvc       services.GameSvc
+}
+
+func NewGameServiceSvc(log logger.Logger, catalog msgcat.MessageCatalog, gameRepo repo.GameRepo,
+	gameEngineSvc service.MineSweeperGameSvc) pb.GameServiceServer {
+	return &GameServiceSvc{
+		log:           log,
+		catalog:       catalog,
+		gameRepo:      gameRepo,
+		gameEngineSvc: gameEngineSvc,
+		gameSvc:       services.NewGameSvcImpl(catalog, gameRepo, gameEngineSvc),
+	}
+}
+
+// CreateGame creates a game for the current user
+func (svc *GameServiceSvc) CreateGame(ctx context.Context, req *pb.CreateGameRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	input := requests.GameCreateInput{
+		Rows:       int(req.GetRows()),
+		Cols:       int(req.GetCols()),
+		Mines:      int(req.GetMines()),
+		Type:       req.GetType(),
+		Kernel:     req.GetKernel(),
+		Arcade:     req.GetArcade(),
+		Tier:       req.GetTier(),
+		Durability: req.GetDurability(),
+	}
+	if fog := req.GetFog(); fog != nil {
+		input.Fog = &requests.Fog{Radius: int(fog.GetRadius()), Memory: int(fog.GetMemory())}
+	}
+	game, _, err := svc.gameSvc.CreateGame(ctx, input, user)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// CreateGameFromCode creates a game for the current user with the board of a board code
+func (svc *GameServiceSvc) CreateGameFromCode(ctx context.Context, req *pb.CreateGameFromCodeRequest) (
+	*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	game, err := svc.gameEngineSvc.CreateGameFromCode(req.GetCode(), user.Fullname)
+	if errors.Is(err, engine.ErrInvalidBoardCode) || errors.Is(err, engine.ErrGeneratorVersion) {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.InvalidArgument, codes.MsgCodeInvalidBoardCode,
+			req.GetCode(), err.Error())
+	} else if err != nil {
+		return nil, statusError(err)
+	}
+	if _, err = svc.gameSvc.StoreNewGame(ctx, game, user); err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// GetGame returns a game as the current user is allowed to see it
+func (svc *GameServiceSvc) GetGame(ctx context.Context, req *pb.GetGameRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, req.GetGameId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// ListGames lists the games of the current user, of the status if given
+func (svc *GameServiceSvc) ListGames(ctx context.Context, req *pb.ListGamesRequest) (*pb.ListGamesResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameStores, err := svc.gameRepo.ListByCreator(ctx, user.ID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	list := &pb.ListGamesResponse{Games: make([]*pb.Game, 0, len(gameStores))}
+	for _, gameStore := range gameStores {
+		if req.GetStatus() != "" && gameStore.Status != req.GetStatus() {
+			continue
+		}
+		// Cached games hold the latest state, the stored one is only read
+		game, err := svc.gameEngineSvc.GetGame(gameStore.ID)
+		if err != nil {
+			if game, err = gameStore.GetGameState(); err == nil {
+				err = game.Validate()
+			}
+			if err != nil {
+				svc.log.Warn().Err(err).Str("gameID", gameStore.ID).Msg("Game left out of the list")
+				continue
+			}
+		}
+		list.Games = append(list.Games, toGame(game, user.Fullname))
+	}
+
+	return list, nil
+}
+
+// ViewGame returns a window of the board of a game as the current user sees it
+func (svc *GameServiceSvc) ViewGame(ctx context.Context, req *pb.ViewGameRequest) (*pb.Viewport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, req.GetGameId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toViewport(game.Viewport(user.Fullname, int(req.GetRow()), int(req.GetCol()), int(req.GetRows()),
+		int(req.GetCols()))), nil
+}
+
+// GetDifficulty rates the board of a game, endless boards can't be rated
+func (svc *GameServiceSvc) GetDifficulty(ctx context.Context, req *pb.GetDifficultyRequest) (*pb.Difficulty, error) {
+	difficulty, err := svc.gameSvc.RateGame(ctx, req.GetGameId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toDifficulty(difficulty), nil
+}
+
+// ListMoves lists the moves played in a game, only its creator and the admins get them before it finished
+func (svc *GameServiceSvc) ListMoves(ctx context.Context, req *pb.ListMovesRequest) (*pb.ListMovesResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if !services.CanManage(user, gameStore) && !game.IsFinished() {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "read the moves of")
+	}
+	list := &pb.ListMovesResponse{Moves: make([]*pb.Move, len(game.History))}
+	for i := range game.History {
+		list.Moves[i] = toMove(&game.History[i])
+	}
+
+	return list, nil
+}
+
+// StartGame starts a game and stores it
+func (svc *GameServiceSvc) StartGame(ctx context.Context, req *pb.StartGameRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	game, err := svc.gameEngineSvc.StartGame(ctx, gameID)
+	if err != nil {
+		return nil, statusError(svc.gameSvc.GameError(ctx, gameID, err))
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// Click clicks a field of a game for the current user and returns what it changed, a losing click is
+// told by the status of the delta
+func (svc *GameServiceSvc) Click(ctx context.Context, req *pb.ClickRequest) (*pb.Delta, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	game, delta, err := svc.gameEngineSvc.Click(ctx, gameID, user.Fullname, toClickType(req.GetClickType()),
+		int(req.GetRow()), int(req.GetCol()))
+	if err != nil && !errors.Is(err, engine.ErrDefeat) {
+		return nil, statusError(svc.gameSvc.GameError(ctx, gameID, err))
+	}
+
+	return toDelta(game.DeltaFor(user.Fullname, delta)), nil
+}
+
+// PlayMoves plays a batch of moves in a game for the current user and stores it once
+func (svc *GameServiceSvc) PlayMoves(ctx context.Context, req *pb.PlayMovesRequest) (*pb.MovesResult, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetMoves()) == 0 || len(req.GetMoves()) > requests.MovesMaxCount {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.InvalidArgument, codes.MsgCodeInvalidMoves,
+			requests.MovesMaxCount, len(req.GetMoves()))
+	}
+	moves := make([]engine.PlannedMove, len(req.GetMoves()))
+	for i, move := range req.GetMoves() {
+		moves[i] = engine.PlannedMove{
+			ClickType: toClickType(move.GetClickType()),
+			Row:       int(move.GetRow()),
+			Col:       int(move.GetCol()),
+		}
+	}
+	gameID := req.GetGameId()
+	game, result, err := svc.gameEngineSvc.PlayMoves(ctx, gameID, user.Fullname, moves)
+	if err != nil {
+		return nil, statusError(svc.gameSvc.GameError(ctx, gameID, err))
+	}
+
+	return toMovesResult(game.MovesFor(user.Fullname, result)), nil
+}
+
+// Forfeit gives up a game of the current user, admins can forfeit any game
+func (svc *GameServiceSvc) Forfeit(ctx context.Context, req *pb.ForfeitRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.Read(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if !services.CanManage(user, gameStore) {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "forfeit")
+	}
+	game, err := svc.gameEngineSvc.Forfeit(ctx, gameID, user.Fullname)
+	if err != nil {
+		return nil, statusError(svc.gameSvc.GameError(ctx, gameID, err))
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// DeleteGame deletes a game of the current user, admins can delete any game and for good
+func (svc *GameServiceSvc) DeleteGame(ctx context.Context, req *pb.DeleteGameRequest) (
+	*pb.DeleteGameResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetHard() && !user.Admin {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeUnauthorized)
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.ReadWithDeleted(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if !services.CanManage(user, gameStore) {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "delete")
+	}
+	if req.GetHard() {
+		err = svc.gameRepo.HardDelete(ctx, gameID)
+	} else {
+		err = svc.gameRepo.Delete(ctx, gameID)
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	svc.gameEngineSvc.RemoveGame(gameID)
+
+	return &pb.DeleteGameResponse{}, nil
+}
+
+// RestoreGame brings back a deleted game of the current user, admins can restore any game
+func (svc *GameServiceSvc) RestoreGame(ctx context.Context, req *pb.RestoreGameRequest) (*pb.Game, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gameID := req.GetGameId()
+	gameStore, err := svc.gameRepo.ReadWithDeleted(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if !services.CanManage(user, gameStore) {
+		return nil, catalogStatus(ctx, svc.catalog, grpccodes.PermissionDenied, codes.MsgCodeGameNotOwned,
+			gameID, "restore")
+	}
+	if err = svc.gameRepo.Restore(ctx, gameID); err != nil {
+		return nil, statusError(err)
+	}
+	game, err := svc.gameSvc.LoadGame(ctx, gameID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toGame(game, user.Fullname), nil
+}
+
+// StreamEvents sends the events of a game as the current user is allowed to see them, resuming after
+// last_seq when it is set
+func (svc *GameServiceSvc) StreamEvents(req *pb.StreamEventsRequest, stream pb.GameService_StreamEventsServer) error {
+	ctx := stream.Context()
+	user, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	player := user.Fullname
+	gameID := req.GetGameId()
+	if _, err = svc.gameSvc.LoadGame(ctx, gameID); err != nil {
+		return statusError(err)
+	}
+	var sub *service.Subscription
+	var missed []engine.Event
+	if req.GetLastSeq() > 0 {
+		if sub, missed, err = svc.gameEngineSvc.Resume(ctx, gameID, player, req.GetLastSeq()); err != nil {
+			return statusError(svc.gameSvc.GameError(ctx, gameID, err))
+		}
+	} else {
+		sub = svc.gameEngineSvc.Subscribe(gameID, player)
+	}
+	defer svc.gameEngineSvc.Unsubscribe(sub)
+	svc.gameEngineSvc.Join(gameID, player)
+	for _, e := range missed {
+		if err = stream.Send(toEvent(e)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case e, ok := <-sub.Events:
+			if !ok {
+				return nil
+			}
+			if err = stream.Send(toEvent(e)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package rpc serves the game and auth operations over gRPC, next to the HTTP server
+package rpc
+
+//go:generate protoc -I . --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative minesweeper.proto
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/utils"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/rpc/pb"
+	"github.com/cmelgarejo/minesweeper-svc/web/services"
+	"github.com/loopcontext/msgcat"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// MetadataAPIKey is the metadata the API key is sent in, like the X-API-KEY header of the HTTP API
+const MetadataAPIKey = "x-api-key"
+
+// publicMethods can be called without an API key
+var publicMethods = map[string]bool{
+	"/" + pb.AuthService_ServiceDesc.ServiceName + "/SignIn":     true,
+	"/" + pb.AuthService_ServiceDesc.ServiceName + "/CreateUser": true,
+}
+
+// NewServer builds the gRPC server of the game and auth services, its calls are authorized by API key
+func NewServer(log logger.Logger, catalog msgcat.MessageCatalog, authRepo repo.AuthRepo, gameRepo repo.GameRepo,
+	gameEngineSvc service.MineSweeperGameSvc) *grpc.Server {
+	auth := &authInterceptor{log: log, catalog: catalog, authRepo: authRepo}
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.unary),
+		grpc.StreamInterceptor(auth.stream),
+	)
+	pb.RegisterAuthServiceServer(srv, NewAuthServiceSvc(log, catalog, authRepo))
+	pb.RegisterGameServiceServer(srv, NewGameServiceSvc(log, catalog, gameRepo, gameEngineSvc))
+
+	return srv
+}
+
+// authInterceptor puts the user of the API key of the calls in their context, like the auth middleware of
+// the HTTP API
+type authInterceptor struct {
+	log      logger.Logger
+	catalog  msgcat.MessageCatalog
+	authRepo repo.AuthRepo
+}
+
+func (ai *authInterceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+	ctx, err := ai.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (ai *authInterceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	ctx, err := ai.authorize(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+}
+
+func (ai *authInterceptor) authorize(ctx context.Context) (context.Context, error) {
+	var apiKey string
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(MetadataAPIKey)) > 0 {
+		apiKey = md.Get(MetadataAPIKey)[0]
+	}
+	user, err := ai.authRepo.CheckUserAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, status.Error(grpccodes.Unauthenticated, err.Error())
+	}
+
+	return context.WithValue(ctx, utils.CurrrentUserCtxKey, user), nil
+}
+
+// authorizedStream is a server stream carrying the context with the user of the call
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (as *authorizedStream) Context() context.Context {
+	return as.ctx
+}
+
+// currentUser returns the user the interceptor put in the context of the call
+func currentUser(ctx context.Context) (*models.User, error) {
+	user, _ := ctx.Value(utils.CurrrentUserCtxKey).(*models.User)
+	if user == nil {
+		return nil, status.Error(grpccodes.Unauthenticated, "No current user")
+	}
+
+	return user, nil
+}
+
+// statusError converts an error to a gRPC status, telling missing games, moves the game can't take and
+// invalid input apart like the HTTP status of the v2 routes do
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	code := grpccodes.Internal
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, service.ErrGameNotFound):
+		code = grpccodes.NotFound
+	case errors.Is(err, engine.ErrNotActive), errors.Is(err, engine.ErrNotStartable),
+		errors.Is(err, engine.ErrTierNotReached), errors.Is(err, engine.ErrRevealed),
+		errors.Is(err, engine.ErrFlagged), errors.Is(err, engine.ErrNotRateable):
+		code = grpccodes.FailedPrecondition
+	case errors.Is(err, engine.ErrOutOfBounds), errors.Is(err, engine.ErrUnsupportedClick),
+		errors.Is(err, engine.ErrInvalidBoardCode), errors.Is(err, engine.ErrGeneratorVersion),
+		errors.Is(err, services.ErrInvalidGameRules):
+		code = grpccodes.InvalidArgument
+	}
+
+	return status.Error(code, errorMessage(err))
+}
+
+// errorMessage is the message of an error, catalog errors tell their short and long messages
+func errorMessage(err error) string {
+	var cde *msgcat.DefaultError
+	if errors.As(err, &cde) {
+		if long := strings.TrimSpace(cde.GetLongMessage()); long != "" {
+			return cde.GetShortMessage() + ": " + long
+		}
+		return cde.GetShortMessage()
+	}
+
+	return err.Error()
+}
+
+// catalogStatus builds a gRPC status out of a message of the catalog
+func catalogStatus(ctx context.Context, catalog msgcat.MessageCatalog, code grpccodes.Code, msgCode int,
+	msgParams ...interface{}) error {
+	return status.Error(code, errorMessage(catalog.GetErrorWithCtx(ctx, msgCode, msgParams...)))
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
 	"github.com/cmelgarejo/minesweeper-svc/utils"
 	"github.com/loopcontext/msgcat"
-	"github.com/valyala/fasthttp"
 )
 
 type AuthSvc interface {
@@ -25,10 +24,11 @@ func NewAuthSvcImpl(catalog msgcat.MessageCatalog) AuthSvc {
 }
 
 func (svc *AuthSvcImpl) GetCurrentUser(ctx context.Context) (currentUser *models.User, err error) {
-	if ctx, ok := ctx.(*fasthttp.RequestCtx); ok {
-		currentUser = ctx.UserValue(string(utils.CurrrentUserCtxKey)).(*models.User)
-	} else {
-		currentUser = ctx.Value(utils.CurrrentUserCtxKey).(*models.User)
+	currentUser, _ = ctx.Value(utils.CurrrentUserCtxKey).(*models.User)
+	if currentUser == nil {
+		// Fiber keeps it among the values of the request under its name, requests handed to the net/http
+		// handlers carry them in their context
+		currentUser, _ = ctx.Value(string(utils.CurrrentUserCtxKey)).(*models.User)
 	}
 	if currentUser == nil {
 		err = svc.catalog.GetErrorWithCtx(ctx, codes.MsgCodeHelperCurrentUserNotFound)
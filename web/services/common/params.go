@@ -0,0 +1,22 @@
+package common
+
+import (
+	"context"
+	"net/http"
+)
+
+// routeParamsKey is the key the params of the matched route are kept under in the request context
+type routeParamsKey struct{}
+
+// WithRouteParams returns the request carrying the params of the route it matched
+func WithRouteParams(r *http.Request, params map[string]string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), routeParamsKey{}, params))
+}
+
+// RouteParam returns a param of the route the request matched, for the HTTP handlers mounted along the
+// params of their routes
+func RouteParam(r *http.Request, name string) string {
+	params, _ := r.Context().Value(routeParamsKey{}).(map[string]string)
+
+	return params[name]
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cmelgarejo/minesweeper-svc/database/models"
+	"github.com/cmelgarejo/minesweeper-svc/database/repo"
+	"github.com/cmelgarejo/minesweeper-svc/resources/messages/codes"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/engine"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/cmelgarejo/minesweeper-svc/web/models/requests"
+	"github.com/loopcontext/msgcat"
+)
+
+// ErrInvalidGameRules is returned for new games asking for rules that don't exist
+var ErrInvalidGameRules = errors.New("Invalid game rules")
+
+// GameSvc holds what the REST, gRPC and GraphQL APIs share to serve the games
+type GameSvc interface {
+	CreateGame(ctx context.Context, input requests.GameCreateInput, createdBy *models.User) (*engine.Game,
+		*models.Game, error)
+	StoreNewGame(ctx context.Context, game *engine.Game, createdBy *models.User) (*models.Game, error)
+	LoadGame(ctx context.Context, gameID string) (*engine.Game, error)
+	ForkGame(ctx context.Context, game *engine.Game, createdBy *models.User, practice bool) (*engine.Game, error)
+	RetryGame(ctx context.Context, game *engine.Game, createdBy *models.User) (*engine.Game, error)
+	RateGame(ctx context.Context, gameID string) (*engine.Difficulty, error)
+	GameError(ctx context.Context, gameID string, err error) error
+}
+
+type GameSvcImpl struct {
+	catalog       msgcat.MessageCatalog
+	gameRepo      repo.GameRepo
+	gameEngineSvc service.MineSweeperGameSvc
+}
+
+func NewGameSvcImpl(catalog msgcat.MessageCatalog, gameRepo repo.GameRepo,
+	gameEngineSvc service.MineSweeperGameSvc) GameSvc {
+	return &GameSvcImpl{
+		catalog:       catalog,
+		gameRepo:      gameRepo,
+		gameEngineSvc: gameEngineSvc,
+	}
+}
+
+// CreateGame creates a game with the rules of the input for the user and stores it. Rules that don't
+// exist are ErrInvalidGameRules, wrapped in their catalog error.
+func (svc *GameSvcImpl) CreateGame(ctx context.Context, input requests.GameCreateInput, createdBy *models.User) (
+	*engine.Game, *models.Game, error) {
+	rules, err := input.GetRules()
+	if err != nil {
+		return nil, nil, svc.rulesError(ctx, err)
+	}
+	durability, err := engine.ParseDurability(input.Durability)
+	if err != nil {
+		return nil, nil, svc.rulesError(ctx, err)
+	}
+	game, err := svc.gameEngineSvc.CreateGame(input.Rows, input.Cols, input.Mines, rules, createdBy.Fullname)
+	if err != nil {
+		return nil, nil, err
+	}
+	game.Durability = durability
+	gameStore, err := svc.StoreNewGame(ctx, game, createdBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return game, gameStore, nil
+}
+
+func (svc *GameSvcImpl) rulesError(ctx context.Context, err error) error {
+	return svc.catalog.WrapErrorWithCtx(ctx, fmt.Errorf("%w: %s", ErrInvalidGameRules, err),
+		codes.MsgCodeInvalidGameRules, err.Error())
+}
+
+// StoreNewGame persists a game just created by the game engine, it returns the stored game
+func (svc *GameSvcImpl) StoreNewGame(ctx context.Context, game *engine.Game, createdBy *models.User) (
+	*models.Game, error) {
+	gameStore := &models.Game{
+		Rows:        game.Rows,
+		Cols:        game.Cols,
+		Mines:       game.Mines,
+		Status:      string(game.Status),
+		CreatedByID: createdBy.ID,
+	}
+	gameStore.ID = game.ID
+	gameStore.UpdateGameState(game)
+	if _, err := svc.gameRepo.UpsertGame(ctx, nil, gameStore); err != nil {
+		return nil, err
+	}
+	gameStore.CreatedBy = createdBy
+
+	return gameStore, nil
+}
+
+// LoadGame gets a game from the game engine service, which reads it from the db if it is not cached
+func (svc *GameSvcImpl) LoadGame(ctx context.Context, gameID string) (*engine.Game, error) {
+	game, err := svc.gameEngineSvc.LoadGame(ctx, gameID)
+
+	return game, svc.GameError(ctx, gameID, err)
+}
+
+// ForkGame forks a game for the user, hands the fork to the game engine service and stores it. Practice
+// forks come from save slots.
+func (svc *GameSvcImpl) ForkGame(ctx context.Context, game *engine.Game, createdBy *models.User, practice bool) (
+	*engine.Game, error) {
+	fork, err := game.Fork(createdBy.Fullname)
+	if err != nil {
+		return nil, err
+	}
+	fork.Rules.Practice = practice
+	if err = svc.gameEngineSvc.UpdateGameState(fork.ID, fork); err != nil {
+		return nil, err
+	}
+	if _, err = svc.StoreNewGame(ctx, fork, createdBy); err != nil {
+		return nil, err
+	}
+
+	return fork, nil
+}
+
+// RetryGame creates a new attempt at the board of a game for the user, hands it to the game engine service
+// and stores it
+func (svc *GameSvcImpl) RetryGame(ctx context.Context, game *engine.Game, createdBy *models.User) (
+	*engine.Game, error) {
+	retry := game.Retry(createdBy.Fullname)
+	if err := svc.gameEngineSvc.UpdateGameState(retry.ID, retry); err != nil {
+		return nil, err
+	}
+	if _, err := svc.StoreNewGame(ctx, retry, createdBy); err != nil {
+		return nil, err
+	}
+
+	return retry, nil
+}
+
+// RateGame returns the difficulty of the board of a game. Games created before boards were rated get rated
+// and stored the first time they are asked for, endless ones are engine.ErrNotRateable.
+func (svc *GameSvcImpl) RateGame(ctx context.Context, gameID string) (*engine.Difficulty, error) {
+	game, err := svc.LoadGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if game.Difficulty != nil {
+		return game.Difficulty, nil
+	}
+	game, err = svc.gameEngineSvc.UpdateGame(ctx, gameID, func(game *engine.Game) error {
+		if game.Difficulty == nil {
+			game.Difficulty = game.Rate()
+		}
+		if game.Difficulty == nil {
+			return engine.ErrNotRateable
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, svc.GameError(ctx, gameID, err)
+	}
+
+	return game.Difficulty, nil
+}
+
+// GameError wraps the corrupted game states refused by the game engine service in their catalog error
+func (svc *GameSvcImpl) GameError(ctx context.Context, gameID string, err error) error {
+	if errors.Is(err, service.ErrCorruptedGameState) {
+		return svc.catalog.WrapErrorWithCtx(ctx, err, codes.MsgCodeCorruptedGameState, gameID, err.Error())
+	}
+
+	return err
+}
+
+// CanManage tells whether the user can forfeit, delete or restore the game: its creator and the admins
+func CanManage(user *models.User, gameStore *models.Game) bool {
+	return user.Admin || gameStore.CreatedByID == user.ID
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/cmelgarejo/minesweeper-svc/database"
+	"github.com/cmelgarejo/minesweeper-svc/utils/logger"
+	"github.com/cmelgarejo/minesweeper-svc/web/game/service"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+)
+
+// Shutdown stops accepting requests and waits up to timeout for the in-flight ones, then stores the games
+// held in memory and closes the db. The gRPC server is optional.
+func Shutdown(log *logger.Logger, timeout time.Duration, appsrv *fiber.App, grpcsrv *grpc.Server,
+	gameEngine service.MineSweeperGameSvc, db *database.DB) {
+	start := time.Now()
+	// Event streams and sockets never go idle on their own
+	gameEngine.CloseSubscriptions()
+	grpcStopped := make(chan struct{})
+	if grpcsrv != nil {
+		go func() {
+			grpcsrv.GracefulStop()
+			close(grpcStopped)
+		}()
+	}
+	drained := true
+	if err := appsrv.ShutdownWithTimeout(timeout); err != nil {
+		drained = false
+		log.Warn().Err(err).Dur("timeout", timeout).Msg("In-flight requests did not finish in time")
+	}
+	if grpcsrv != nil {
+		select {
+		case <-grpcStopped:
+		case <-time.After(time.Until(start.Add(timeout))):
+			drained = false
+			grpcsrv.Stop()
+			log.Warn().Dur("timeout", timeout).Msg("In-flight calls did not finish in time")
+		}
+	}
+	games, _ := gameEngine.GetGameList()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	flushed, err := gameEngine.Flush(ctx)
+	if err != nil {
+		log.Error().Err(err).Int("flushed", flushed).Msg("Games could not be stored")
+	}
+	if err = db.Close(); err != nil {
+		log.Error().Err(err).Msg("Database could not be closed")
+	}
+	log.Info().
+		Bool("drained", drained).
+		Int("games", len(games)).
+		Int("flushed", flushed).
+		Dur("took", time.Since(start)).
+		Msg("Shutdown complete")
+}